@@ -0,0 +1,234 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/urfave/cli/v3"
+)
+
+var (
+	subcmdPlugin = &cli.Command{
+		Name:  "plugin",
+		Usage: "Manage plugins of the running gitea process",
+		Commands: []*cli.Command{
+			microcmdPluginList,
+			microcmdPluginInstall,
+			microcmdPluginEnable,
+			microcmdPluginDisable,
+			microcmdPluginConfig,
+			microcmdPluginUninstall,
+			microcmdPluginUpgrade,
+			microcmdPluginRollback,
+		},
+	}
+
+	microcmdPluginList = &cli.Command{
+		Name:   "list",
+		Usage:  "List installed plugins",
+		Action: runPluginList,
+	}
+
+	microcmdPluginInstall = &cli.Command{
+		Name:   "install",
+		Usage:  "Install a plugin from the marketplace",
+		Action: runPluginInstall,
+		Flags: []cli.Flag{
+			pluginIDFlag(),
+			&cli.StringFlag{
+				Name:  "version",
+				Usage: "Version to install",
+				Value: "latest",
+			},
+		},
+	}
+
+	microcmdPluginEnable = &cli.Command{
+		Name:   "enable",
+		Usage:  "Enable an installed plugin",
+		Action: runPluginEnable,
+		Flags: []cli.Flag{
+			pluginIDFlag(),
+			&cli.BoolFlag{
+				Name:  "approve-permissions",
+				Usage: "Approve the permissions declared by the plugin before enabling it",
+			},
+		},
+	}
+
+	microcmdPluginDisable = &cli.Command{
+		Name:   "disable",
+		Usage:  "Disable an installed plugin",
+		Action: runPluginDisable,
+		Flags:  []cli.Flag{pluginIDFlag()},
+	}
+
+	microcmdPluginConfig = &cli.Command{
+		Name:   "config",
+		Usage:  "Show or edit the configuration of a plugin",
+		Action: runPluginConfig,
+		Flags: []cli.Flag{
+			pluginIDFlag(),
+			&cli.StringSliceFlag{
+				Name:  "set",
+				Usage: "Set a field as key=value, the value is parsed as JSON and falls back to a plain string (can be repeated)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "unset",
+				Usage: "Reset a field to its default value (can be repeated)",
+			},
+		},
+	}
+
+	microcmdPluginUninstall = &cli.Command{
+		Name:   "uninstall",
+		Usage:  "Uninstall a plugin",
+		Action: runPluginUninstall,
+		Flags: []cli.Flag{
+			pluginIDFlag(),
+			&cli.BoolFlag{
+				Name:  "purge-data",
+				Usage: "Also drop the database tables and schema version of the plugin",
+			},
+		},
+	}
+
+	microcmdPluginUpgrade = &cli.Command{
+		Name:   "upgrade",
+		Usage:  "Upgrade a plugin from the marketplace, keeping the current version for rollback",
+		Action: runPluginUpgrade,
+		Flags: []cli.Flag{
+			pluginIDFlag(),
+			&cli.StringFlag{
+				Name:  "version",
+				Usage: "Version to upgrade to",
+				Value: "latest",
+			},
+		},
+	}
+
+	microcmdPluginRollback = &cli.Command{
+		Name:   "rollback",
+		Usage:  "Roll a plugin back to the version kept by its last upgrade",
+		Action: runPluginRollback,
+		Flags:  []cli.Flag{pluginIDFlag()},
+	}
+)
+
+func runPluginUpgrade(ctx context.Context, c *cli.Command) error {
+	setting.MustInstalled()
+	extra := private.UpgradePlugin(ctx, c.String("id"), c.String("version"))
+	return handleCliResponseExtra(extra)
+}
+
+func runPluginRollback(ctx context.Context, c *cli.Command) error {
+	setting.MustInstalled()
+	extra := private.RollbackPlugin(ctx, c.String("id"))
+	return handleCliResponseExtra(extra)
+}
+
+func pluginIDFlag() *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:     "id",
+		Usage:    "ID of the plugin",
+		Required: true,
+	}
+}
+
+func runPluginList(ctx context.Context, _ *cli.Command) error {
+	setting.MustInstalled()
+	plugins, extra := private.ListPlugins(ctx)
+	if extra.HasError() {
+		return handleCliResponseExtra(extra)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintf(w, "ID\tName\tVersion\tEnabled\tSource\n")
+	for _, p := range plugins {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", p.ID, p.Name, p.Version, p.Enabled, p.Source)
+	}
+	return w.Flush()
+}
+
+func runPluginInstall(ctx context.Context, c *cli.Command) error {
+	setting.MustInstalled()
+	extra := private.InstallPlugin(ctx, c.String("id"), c.String("version"))
+	return handleCliResponseExtra(extra)
+}
+
+func runPluginEnable(ctx context.Context, c *cli.Command) error {
+	setting.MustInstalled()
+	extra := private.EnablePlugin(ctx, c.String("id"), c.Bool("approve-permissions"))
+	return handleCliResponseExtra(extra)
+}
+
+func runPluginDisable(ctx context.Context, c *cli.Command) error {
+	setting.MustInstalled()
+	extra := private.DisablePlugin(ctx, c.String("id"))
+	return handleCliResponseExtra(extra)
+}
+
+func runPluginConfig(ctx context.Context, c *cli.Command) error {
+	setting.MustInstalled()
+	pluginID := c.String("id")
+
+	if !c.IsSet("set") && !c.IsSet("unset") {
+		cfg, extra := private.GetPluginConfig(ctx, pluginID)
+		if extra.HasError() {
+			return handleCliResponseExtra(extra)
+		}
+		out, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(os.Stdout, string(out))
+		return nil
+	}
+
+	values, err := parsePluginConfigFlags(c.StringSlice("set"), c.StringSlice("unset"))
+	if err != nil {
+		return err
+	}
+	extra := private.EditPluginConfig(ctx, pluginID, values)
+	return handleCliResponseExtra(extra)
+}
+
+// parsePluginConfigFlags converts the --set and --unset flags to a config patch, unset fields are sent as null
+func parsePluginConfigFlags(sets, unsets []string) (map[string]any, error) {
+	values := make(map[string]any, len(sets)+len(unsets))
+	for _, kv := range sets {
+		key, raw, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set %q, expected key=value", kv)
+		}
+		var v any
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			v = raw
+		}
+		values[key] = v
+	}
+	for _, key := range unsets {
+		if key == "" {
+			return nil, errors.New("--unset requires a field name")
+		}
+		values[key] = nil
+	}
+	return values, nil
+}
+
+func runPluginUninstall(ctx context.Context, c *cli.Command) error {
+	setting.MustInstalled()
+	extra := private.UninstallPlugin(ctx, c.String("id"), c.Bool("purge-data"))
+	return handleCliResponseExtra(extra)
+}
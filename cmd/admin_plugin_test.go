@@ -0,0 +1,34 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePluginConfigFlags(t *testing.T) {
+	values, err := parsePluginConfigFlags(
+		[]string{"enabled=true", "max_items=20", "endpoint=https://example.com/a=b", "tags=[\"a\",\"b\"]", "name=plain text"},
+		[]string{"token"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"enabled":   true,
+		"max_items": float64(20),
+		"endpoint":  "https://example.com/a=b",
+		"tags":      []any{"a", "b"},
+		"name":      "plain text",
+		"token":     nil,
+	}, values)
+
+	_, err = parsePluginConfigFlags([]string{"novalue"}, nil)
+	assert.Error(t, err)
+	_, err = parsePluginConfigFlags([]string{"=x"}, nil)
+	assert.Error(t, err)
+	_, err = parsePluginConfigFlags(nil, []string{""})
+	assert.Error(t, err)
+}
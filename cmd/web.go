@@ -205,25 +205,24 @@ func serveInstalled(c *cli.Command) error {
 
 	routers.InitWebInstalled(graceful.GetManager().HammerContext())
 
-	// 初始化插件系统
-	pluginsDir := setting.PluginsDir
-	if pluginsDir == "" {
-		pluginsDir = "./plugins"
-	}
-	
-	marketplaceURL := setting.PluginMarketplaceURL
-	if marketplaceURL == "" {
-		marketplaceURL = "https://plugins.gitea.io"
-	}
+	// 初始化插件系统，禁用时插件存储未初始化，插件管理器保持为 nil
+	if setting.PluginEnabled {
+		pluginsDir := setting.PluginsDir
+		if pluginsDir == "" {
+			pluginsDir = "./plugins"
+		}
 
-	if err := plugin_service.InitManager(pluginsDir, marketplaceURL); err != nil {
-		log.Error("Failed to initialize plugin manager: %v", err)
-	} else {
-		// 加载所有已安装的插件
-		loader := plugin_service.GetLoader()
-		if err := loader.LoadAll(graceful.GetManager().HammerContext()); err != nil {
-			log.Error("Failed to load plugins: %v", err)
+		if err := plugin_service.InitManager(pluginsDir); err != nil {
+			log.Error("Failed to initialize plugin manager: %v", err)
+		} else {
+			// 加载所有已安装的插件
+			loader := plugin_service.GetLoader()
+			if err := loader.LoadAll(graceful.GetManager().HammerContext()); err != nil {
+				log.Error("Failed to load plugins: %v", err)
+			}
 		}
+	} else {
+		log.Info("Plugin system is disabled")
 	}
 
 	// We check that AppDataPath exists here (it should have been created during installation)
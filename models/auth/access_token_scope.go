@@ -5,6 +5,7 @@ package auth
 
 import (
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
@@ -194,10 +195,12 @@ var accessTokenScopes = map[AccessTokenScopeLevel]map[AccessTokenScopeCategory]A
 	},
 }
 
+// GetAccessTokenCategories returns the names of all scope categories, including the ones provided by plugins
 func GetAccessTokenCategories() (res []string) {
 	for _, cat := range accessTokenScopes[Read] {
 		res = append(res, strings.TrimPrefix(string(cat), "read:"))
 	}
+	res = append(res, GetPluginScopeCategories()...)
 	slices.Sort(res)
 	return res
 }
@@ -234,9 +237,18 @@ func GetScopeLevelFromAccessMode(mode perm.AccessMode) AccessTokenScopeLevel {
 	}
 }
 
+// parsedAccessTokenScope is the parsed form of an AccessTokenScope: the built-in scopes as a bitmap,
+// and the plugin scopes (see RegisterPluginScopeCategory) by category.
+type parsedAccessTokenScope struct {
+	bitmap  accessTokenScopeBitmap
+	plugins map[string]AccessTokenScopeLevel
+}
+
 // parse the scope string into a bitmap, thus removing possible duplicates.
-func (s AccessTokenScope) parse() (accessTokenScopeBitmap, error) {
-	var bitmap accessTokenScopeBitmap
+// Plugin scopes are only validated in strict mode, so tokens keep working (without the plugin scopes)
+// after the plugin providing them has been removed.
+func (s AccessTokenScope) parse(strict bool) (parsedAccessTokenScope, error) {
+	var parsed parsedAccessTokenScope
 
 	// The following is the more performant equivalent of 'for _, v := range strings.Split(remainingScope, ",")' as this is hot code
 	remainingScopes := string(s)
@@ -258,18 +270,27 @@ func (s AccessTokenScope) parse() (accessTokenScopeBitmap, error) {
 			continue
 		}
 		if singleScope == AccessTokenScopeAll {
-			bitmap |= accessTokenScopeAllBits
+			parsed.bitmap |= accessTokenScopeAllBits
 			continue
 		}
 
 		bits, ok := allAccessTokenScopeBits[singleScope]
-		if !ok {
-			return 0, fmt.Errorf("invalid access token scope: %s", singleScope)
+		if ok {
+			parsed.bitmap |= bits
+			continue
+		}
+
+		category, level := parsePluginScope(singleScope)
+		if level == NoAccess || (strict && !IsPluginScopeCategory(category)) {
+			return parsedAccessTokenScope{}, fmt.Errorf("invalid access token scope: %s", singleScope)
 		}
-		bitmap |= bits
+		if parsed.plugins == nil {
+			parsed.plugins = make(map[string]AccessTokenScopeLevel)
+		}
+		parsed.plugins[category] = max(parsed.plugins[category], level)
 	}
 
-	return bitmap, nil
+	return parsed, nil
 }
 
 // StringSlice returns the AccessTokenScope as a []string
@@ -282,12 +303,12 @@ func (s AccessTokenScope) StringSlice() []string {
 
 // Normalize returns a normalized scope string without any duplicates.
 func (s AccessTokenScope) Normalize() (AccessTokenScope, error) {
-	bitmap, err := s.parse()
+	parsed, err := s.parse(true)
 	if err != nil {
 		return "", err
 	}
 
-	return bitmap.toScope(), nil
+	return parsed.toScope(), nil
 }
 
 func (s AccessTokenScope) HasPermissionScope() bool {
@@ -296,23 +317,23 @@ func (s AccessTokenScope) HasPermissionScope() bool {
 
 // PublicOnly checks if this token scope is limited to public resources
 func (s AccessTokenScope) PublicOnly() (bool, error) {
-	bitmap, err := s.parse()
+	parsed, err := s.parse(false)
 	if err != nil {
 		return false, err
 	}
 
-	return bitmap.hasScope(AccessTokenScopePublicOnly)
+	return parsed.hasScope(AccessTokenScopePublicOnly)
 }
 
 // HasScope returns true if the string has the given scope
 func (s AccessTokenScope) HasScope(scopes ...AccessTokenScope) (bool, error) {
-	bitmap, err := s.parse()
+	parsed, err := s.parse(false)
 	if err != nil {
 		return false, err
 	}
 
 	for _, s := range scopes {
-		if has, err := bitmap.hasScope(s); !has || err != nil {
+		if has, err := parsed.hasScope(s); !has || err != nil {
 			return has, err
 		}
 	}
@@ -322,13 +343,13 @@ func (s AccessTokenScope) HasScope(scopes ...AccessTokenScope) (bool, error) {
 
 // HasAnyScope returns true if any of the scopes is contained in the string
 func (s AccessTokenScope) HasAnyScope(scopes ...AccessTokenScope) (bool, error) {
-	bitmap, err := s.parse()
+	parsed, err := s.parse(false)
 	if err != nil {
 		return false, err
 	}
 
 	for _, s := range scopes {
-		if has, err := bitmap.hasScope(s); has || err != nil {
+		if has, err := parsed.hasScope(s); has || err != nil {
 			return has, err
 		}
 	}
@@ -336,6 +357,40 @@ func (s AccessTokenScope) HasAnyScope(scopes ...AccessTokenScope) (bool, error)
 	return false, nil
 }
 
+// hasScope returns true if the parsed scope has the given scope.
+// A token with access to all built-in scopes also has access to all plugin scopes.
+func (parsed parsedAccessTokenScope) hasScope(scope AccessTokenScope) (bool, error) {
+	if _, ok := allAccessTokenScopeBits[scope]; ok {
+		return parsed.bitmap.hasScope(scope)
+	}
+	category, level := parsePluginScope(scope)
+	if level == NoAccess || !IsPluginScopeCategory(category) {
+		return false, fmt.Errorf("invalid access token scope: %s", scope)
+	}
+	if parsed.bitmap&accessTokenScopeAllBits == accessTokenScopeAllBits {
+		return true, nil
+	}
+	return parsed.plugins[category] >= level, nil
+}
+
+// toScope returns a normalized scope string without any duplicates, the plugin scopes are sorted by category.
+func (parsed parsedAccessTokenScope) toScope() AccessTokenScope {
+	scope := parsed.bitmap.toScope()
+	if len(parsed.plugins) == 0 || parsed.bitmap&accessTokenScopeAllBits == accessTokenScopeAllBits {
+		return scope
+	}
+
+	scopes := scope.StringSlice()
+	for _, category := range slices.Sorted(maps.Keys(parsed.plugins)) {
+		prefix := "read:"
+		if parsed.plugins[category] == Write {
+			prefix = "write:"
+		}
+		scopes = append(scopes, prefix+category)
+	}
+	return AccessTokenScope(strings.Join(scopes, ","))
+}
+
 // hasScope returns true if the string has the given scope
 func (bitmap accessTokenScopeBitmap) hasScope(scope AccessTokenScope) (bool, error) {
 	expectedBits, ok := allAccessTokenScopeBits[scope]
@@ -0,0 +1,77 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var pluginScopeCategoryPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// pluginScopeCategories maps the scope categories provided by plugins to the plugin ID
+var pluginScopeCategories = struct {
+	sync.RWMutex
+	owners map[string]string
+}{owners: make(map[string]string)}
+
+// RegisterPluginScopeCategory makes the scopes "read:<category>" and "write:<category>" available to access tokens.
+// A category can not shadow a built-in category or a category registered by another plugin.
+func RegisterPluginScopeCategory(pluginID, category string) error {
+	if !pluginScopeCategoryPattern.MatchString(category) {
+		return fmt.Errorf("invalid scope category %q", category)
+	}
+	if _, ok := allAccessTokenScopeBits[AccessTokenScope("read:"+category)]; ok {
+		return fmt.Errorf("scope category %q is built-in", category)
+	}
+
+	pluginScopeCategories.Lock()
+	defer pluginScopeCategories.Unlock()
+	if owner, ok := pluginScopeCategories.owners[category]; ok && owner != pluginID {
+		return fmt.Errorf("scope category %q is already provided by plugin %s", category, owner)
+	}
+	pluginScopeCategories.owners[category] = pluginID
+	return nil
+}
+
+// UnregisterPluginScopeCategories removes the scope categories of a plugin. Existing tokens keep the scopes,
+// they become effective again when the plugin registers the categories again.
+func UnregisterPluginScopeCategories(pluginID string) {
+	pluginScopeCategories.Lock()
+	defer pluginScopeCategories.Unlock()
+	maps.DeleteFunc(pluginScopeCategories.owners, func(_, owner string) bool {
+		return owner == pluginID
+	})
+}
+
+// IsPluginScopeCategory returns whether the category is currently provided by a plugin
+func IsPluginScopeCategory(category string) bool {
+	pluginScopeCategories.RLock()
+	defer pluginScopeCategories.RUnlock()
+	_, ok := pluginScopeCategories.owners[category]
+	return ok
+}
+
+// GetPluginScopeCategories returns the sorted scope categories currently provided by plugins
+func GetPluginScopeCategories() []string {
+	pluginScopeCategories.RLock()
+	defer pluginScopeCategories.RUnlock()
+	return slices.Sorted(maps.Keys(pluginScopeCategories.owners))
+}
+
+// parsePluginScope splits a scope like "write:license" into its category and level,
+// the level is NoAccess if the scope is not in this form
+func parsePluginScope(scope AccessTokenScope) (string, AccessTokenScopeLevel) {
+	if category, ok := strings.CutPrefix(string(scope), "read:"); ok && pluginScopeCategoryPattern.MatchString(category) {
+		return category, Read
+	}
+	if category, ok := strings.CutPrefix(string(scope), "write:"); ok && pluginScopeCategoryPattern.MatchString(category) {
+		return category, Write
+	}
+	return "", NoAccess
+}
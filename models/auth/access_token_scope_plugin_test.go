@@ -0,0 +1,51 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginScopeCategory(t *testing.T) {
+	defer UnregisterPluginScopeCategories("license-manager")
+
+	assert.Error(t, RegisterPluginScopeCategory("license-manager", "repository"))
+	assert.Error(t, RegisterPluginScopeCategory("license-manager", "Bad-Name"))
+	require.NoError(t, RegisterPluginScopeCategory("license-manager", "license"))
+	require.NoError(t, RegisterPluginScopeCategory("license-manager", "license"))
+	assert.Error(t, RegisterPluginScopeCategory("other", "license"))
+	assert.Contains(t, GetAccessTokenCategories(), "license")
+
+	scope, err := AccessTokenScope("read:license,write:user,write:license,read:repository").Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, AccessTokenScope("read:repository,write:user,write:license"), scope)
+	_, err = AccessTokenScope("read:unknown").Normalize()
+	assert.Error(t, err)
+
+	has, err := scope.HasScope(AccessTokenScope("read:license"), AccessTokenScopeWriteUser)
+	require.NoError(t, err)
+	assert.True(t, has)
+	has, err = AccessTokenScope("read:license").HasScope("write:license")
+	require.NoError(t, err)
+	assert.False(t, has)
+	has, err = AccessTokenScopeAll.HasScope("write:license")
+	require.NoError(t, err)
+	assert.True(t, has)
+	has, err = AccessTokenScopeReadRepository.HasScope("read:license")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	// tokens keep working after the plugin is removed, the plugin scopes are not usable any more
+	UnregisterPluginScopeCategories("license-manager")
+	has, err = scope.HasScope(AccessTokenScopeReadRepository)
+	require.NoError(t, err)
+	assert.True(t, has)
+	_, err = scope.HasScope("read:license")
+	assert.Error(t, err)
+	_, err = scope.Normalize()
+	assert.Error(t, err)
+}
@@ -111,6 +111,39 @@ func NamesToBean(names ...string) ([]any, error) {
 	return beans, nil
 }
 
+// TableName returns the table name of the bean
+func TableName(bean any) string {
+	return xormEngine.TableName(bean)
+}
+
+// GetXORMEngine returns the global xorm engine, for schema migrations that run outside models/migrations (e.g. plugins)
+func GetXORMEngine() *xorm.Engine {
+	return xormEngine
+}
+
+// IsRegisteredTable returns whether the table belongs to a model registered by RegisterModel
+func IsRegisteredTable(tableName string) bool {
+	for _, bean := range registeredModels {
+		if xormEngine.TableName(bean) == tableName {
+			return true
+		}
+	}
+	return false
+}
+
+// TableNames returns the names of all tables in the database
+func TableNames() ([]string, error) {
+	tables, err := xormEngine.DBMetas()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	return names, nil
+}
+
 // MaxBatchInsertSize returns the table's max batch insert size
 func MaxBatchInsertSize(bean any) int {
 	t, err := xormEngine.TableInfo(bean)
@@ -0,0 +1,76 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// APIKey 授权 API 密钥，嵌入到客户端程序中，用于在没有 Gitea 登录的情况下验证授权和获取吊销列表。
+// 密钥只保存哈希值，通过密钥只能访问所属用户的授权
+type APIKey struct {
+	ID           int64              `xorm:"pk autoincr"`
+	UserID       int64              `xorm:"NOT NULL INDEX"` // 所属用户ID
+	Name         string             `xorm:"VARCHAR(255) NOT NULL"`
+	KeyHash      string             `xorm:"VARCHAR(64) UNIQUE NOT NULL" json:"-"` // 密钥的 SHA256
+	KeyLastEight string             `xorm:"VARCHAR(8)"`                           // 密钥末 8 位，用于在列表中辨认
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	LastUsedUnix timeutil.TimeStamp `xorm:"INDEX"`
+}
+
+func init() {
+	db.RegisterModel(new(APIKey))
+}
+
+// TableName 表名
+func (k *APIKey) TableName() string {
+	return "license_api_key"
+}
+
+// CreateAPIKey 创建 API 密钥
+func CreateAPIKey(ctx context.Context, key *APIKey) error {
+	_, err := db.GetEngine(ctx).Insert(key)
+	return err
+}
+
+// GetAPIKeyByHash 根据密钥哈希获取 API 密钥
+func GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	key := &APIKey{}
+	has, err := db.GetEngine(ctx).Where("key_hash = ?", keyHash).Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrAPIKeyNotExist{}
+	}
+	return key, nil
+}
+
+// UpdateAPIKeyLastUsed 更新最后使用时间
+func UpdateAPIKeyLastUsed(ctx context.Context, key *APIKey) error {
+	key.LastUsedUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(ctx).ID(key.ID).Cols("last_used_unix").Update(key)
+	return err
+}
+
+// ListAPIKeys 列出用户的 API 密钥
+func ListAPIKeys(ctx context.Context, userID int64) ([]*APIKey, error) {
+	keys := make([]*APIKey, 0, 5)
+	return keys, db.GetEngine(ctx).Where("user_id = ?", userID).OrderBy("id").Find(&keys)
+}
+
+// DeleteAPIKey 删除用户的 API 密钥
+func DeleteAPIKey(ctx context.Context, userID, id int64) error {
+	n, err := db.GetEngine(ctx).Where("user_id = ? AND id = ?", userID, id).Delete(&APIKey{})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAPIKeyNotExist{ID: id}
+	}
+	return nil
+}
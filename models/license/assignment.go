@@ -0,0 +1,89 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// LicenseAssignment 组织授权分配给成员的席位，成员使用自己的账号激活
+type LicenseAssignment struct { //revive:disable-line:exported
+	ID          int64              `xorm:"pk autoincr"`
+	LicenseID   int64              `xorm:"NOT NULL UNIQUE(s)"`
+	UserID      int64              `xorm:"NOT NULL UNIQUE(s) INDEX"` // 分配到席位的组织成员
+	AssignerID  int64              `xorm:"NOT NULL"`                 // 分配席位的管理员
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(LicenseAssignment))
+}
+
+// TableName 表名
+func (a *LicenseAssignment) TableName() string {
+	return "license_assignment"
+}
+
+// GetAssignment 获取成员在授权上的席位分配
+func GetAssignment(ctx context.Context, licenseID, userID int64) (*LicenseAssignment, error) {
+	a := &LicenseAssignment{}
+	has, err := db.GetEngine(ctx).Where("license_id = ? AND user_id = ?", licenseID, userID).Get(a)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrAssignmentNotExist{LicenseID: licenseID, UserID: userID}
+	}
+	return a, nil
+}
+
+// ListAssignments 列出授权的所有席位分配
+func ListAssignments(ctx context.Context, licenseID int64) ([]*LicenseAssignment, error) {
+	assignments := make([]*LicenseAssignment, 0, 10)
+	return assignments, db.GetEngine(ctx).Where("license_id = ?", licenseID).OrderBy("id").Find(&assignments)
+}
+
+// CountAssignments 统计授权已分配的席位数
+func CountAssignments(ctx context.Context, licenseID int64) (int64, error) {
+	return db.GetEngine(ctx).Where("license_id = ?", licenseID).Count(&LicenseAssignment{})
+}
+
+// CreateAssignment 创建席位分配
+func CreateAssignment(ctx context.Context, a *LicenseAssignment) error {
+	_, err := db.GetEngine(ctx).Insert(a)
+	return err
+}
+
+// DeleteAssignment 取消席位分配，并释放该成员激活的机器
+func DeleteAssignment(ctx context.Context, l *License, a *LicenseAssignment) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		activations, err := ListUserActivations(ctx, l.ID, a.UserID)
+		if err != nil {
+			return err
+		}
+		for _, activation := range activations {
+			if err := DeleteActivation(ctx, l, activation); err != nil {
+				return err
+			}
+		}
+		_, err = db.GetEngine(ctx).ID(a.ID).Delete(&LicenseAssignment{})
+		return err
+	})
+}
+
+// ListAssignedLicenses 列出分配给用户席位的授权
+func ListAssignedLicenses(ctx context.Context, userID int64) ([]*License, error) {
+	licenses := make([]*License, 0, 10)
+	if err := db.GetEngine(ctx).
+		Join("INNER", "license_assignment", "license_assignment.license_id = license.id").
+		Where("license_assignment.user_id = ?", userID).
+		OrderBy("license.id DESC").
+		Find(&licenses); err != nil {
+		return nil, err
+	}
+	return licenses, LoadSeatsUsed(ctx, licenses...)
+}
@@ -27,6 +27,9 @@ type AuthorizedDevice struct {
 	UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
 	LastVerifiedAt timeutil.TimeStamp `xorm:"INDEX"`
 	Remarks        string             `xorm:"TEXT"`
+	ProductID      int64              `xorm:"INDEX"`     // 所属产品，0 表示未关联产品
+	EditionID      int64              `xorm:"INDEX"`     // 产品版本，版本的功能与 Features 合并后写入离线授权令牌
+	Features       []*Entitlement     `xorm:"JSON TEXT"` // 单个授权额外的功能，同名时覆盖版本的功能
 }
 
 func init() {
@@ -38,15 +41,14 @@ func (d *AuthorizedDevice) TableName() string {
 	return "authorized_device"
 }
 
+// IsExpired 授权是否已过期，永久授权不会过期
+func (d *AuthorizedDevice) IsExpired() bool {
+	return !d.ExpiryDate.IsZero() && d.ExpiryDate.AsTime().Before(time.Now())
+}
+
 // IsValid 检查授权是否有效
 func (d *AuthorizedDevice) IsValid() bool {
-	if !d.IsEnabled {
-		return false
-	}
-	if !d.ExpiryDate.IsZero() && d.ExpiryDate.AsTime().Before(time.Now()) {
-		return false
-	}
-	return true
+	return d.IsEnabled && !d.IsExpired()
 }
 
 // UpdateLastVerified 更新最后验证时间
@@ -107,26 +109,39 @@ func UpdateDevice(ctx context.Context, device *AuthorizedDevice) error {
 	return err
 }
 
-// DeleteDevice 删除设备
+// DeleteDevice 删除设备，并记录到吊销列表
 func DeleteDevice(ctx context.Context, id int64) error {
-	_, err := db.GetEngine(ctx).ID(id).Delete(&AuthorizedDevice{})
-	return err
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		device, err := GetDeviceByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := db.GetEngine(ctx).ID(id).Delete(&AuthorizedDevice{}); err != nil {
+			return err
+		}
+		return db.Insert(ctx, &RevokedDevice{
+			UserID:      device.UserID,
+			DeviceID:    device.DeviceID,
+			MachineCode: device.MachineCode,
+			ExpiryDate:  device.ExpiryDate,
+		})
+	})
 }
 
 // SearchDevicesOptions 搜索设备选项
 type SearchDevicesOptions struct {
 	db.ListOptions
-	UserID    int64  // 用户ID（必需，用于数据隔离）
+	UserID    int64 // 用户ID（必需，用于数据隔离）
 	Keyword   string
 	IsEnabled *bool
 }
 
 func (opts *SearchDevicesOptions) toConds() builder.Cond {
 	cond := builder.NewCond()
-	
+
 	// 必须按用户ID过滤
 	cond = cond.And(builder.Eq{"user_id": opts.UserID})
-	
+
 	if opts.Keyword != "" {
 		cond = cond.And(builder.Or(
 			builder.Like{"machine_code", opts.Keyword},
@@ -0,0 +1,67 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// Entitlement 授权包含的一项功能，Quantity 为 0 表示不限数量
+type Entitlement struct {
+	Name     string `json:"name"`
+	Quantity int64  `json:"quantity,omitempty"`
+}
+
+// UnmarshalJSON 兼容早期只保存功能名称的授权，例如 ["export", "sync"]
+func (e *Entitlement) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		*e = Entitlement{}
+		return json.Unmarshal(data, &e.Name)
+	}
+	type entitlement Entitlement
+	return json.Unmarshal(data, (*entitlement)(e))
+}
+
+// ValidateEntitlements 检查功能列表：名称不能为空或重复，数量不能为负数
+func ValidateEntitlements(entitlements []*Entitlement) error {
+	seen := make(map[string]bool, len(entitlements))
+	for _, e := range entitlements {
+		name := strings.TrimSpace(e.Name)
+		if name == "" || len(name) > 100 {
+			return ErrInvalidEntitlement{Name: e.Name, Reason: "name must be 1-100 characters"}
+		}
+		if e.Quantity < 0 {
+			return ErrInvalidEntitlement{Name: e.Name, Reason: "quantity must not be negative"}
+		}
+		if seen[name] {
+			return ErrInvalidEntitlement{Name: e.Name, Reason: "duplicated"}
+		}
+		seen[name] = true
+		e.Name = name
+	}
+	return nil
+}
+
+// MergeEntitlements 合并版本和单个授权的功能，同名功能以单个授权为准，结果按名称排序
+func MergeEntitlements(base, overrides []*Entitlement) []*Entitlement {
+	merged := make([]*Entitlement, 0, len(base)+len(overrides))
+	index := make(map[string]int, len(base)+len(overrides))
+	for _, list := range [][]*Entitlement{base, overrides} {
+		for _, e := range list {
+			if i, ok := index[e.Name]; ok {
+				merged[i] = e
+				continue
+			}
+			index[e.Name] = len(merged)
+			merged = append(merged, e)
+		}
+	}
+	slices.SortFunc(merged, func(a, b *Entitlement) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return merged
+}
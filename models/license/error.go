@@ -54,3 +54,201 @@ func (err ErrDeviceAlreadyExist) Error() string {
 	}
 	return "device already exists"
 }
+
+// ErrAPIKeyNotExist API 密钥不存在错误
+type ErrAPIKeyNotExist struct {
+	ID int64
+}
+
+// IsErrAPIKeyNotExist 检查是否为 API 密钥不存在错误
+func IsErrAPIKeyNotExist(err error) bool {
+	_, ok := err.(ErrAPIKeyNotExist)
+	return ok
+}
+
+func (err ErrAPIKeyNotExist) Error() string {
+	if err.ID > 0 {
+		return fmt.Sprintf("license api key does not exist [id: %d]", err.ID)
+	}
+	return "license api key does not exist"
+}
+
+// ErrProductNotExist 产品不存在错误
+type ErrProductNotExist struct {
+	ID int64
+}
+
+// IsErrProductNotExist 检查是否为产品不存在错误
+func IsErrProductNotExist(err error) bool {
+	_, ok := err.(ErrProductNotExist)
+	return ok
+}
+
+func (err ErrProductNotExist) Error() string {
+	return fmt.Sprintf("license product does not exist [id: %d]", err.ID)
+}
+
+// ErrProductAlreadyExist 产品已存在错误
+type ErrProductAlreadyExist struct {
+	Key string
+}
+
+// IsErrProductAlreadyExist 检查是否为产品已存在错误
+func IsErrProductAlreadyExist(err error) bool {
+	_, ok := err.(ErrProductAlreadyExist)
+	return ok
+}
+
+func (err ErrProductAlreadyExist) Error() string {
+	return fmt.Sprintf("license product already exists [key: %s]", err.Key)
+}
+
+// ErrEditionNotExist 版本不存在错误
+type ErrEditionNotExist struct {
+	ID int64
+}
+
+// IsErrEditionNotExist 检查是否为版本不存在错误
+func IsErrEditionNotExist(err error) bool {
+	_, ok := err.(ErrEditionNotExist)
+	return ok
+}
+
+func (err ErrEditionNotExist) Error() string {
+	return fmt.Sprintf("license edition does not exist [id: %d]", err.ID)
+}
+
+// ErrEditionAlreadyExist 版本已存在错误
+type ErrEditionAlreadyExist struct {
+	Key string
+}
+
+// IsErrEditionAlreadyExist 检查是否为版本已存在错误
+func IsErrEditionAlreadyExist(err error) bool {
+	_, ok := err.(ErrEditionAlreadyExist)
+	return ok
+}
+
+func (err ErrEditionAlreadyExist) Error() string {
+	return fmt.Sprintf("license edition already exists [key: %s]", err.Key)
+}
+
+// ErrLicenseInUse 产品或版本仍被授权使用错误
+type ErrLicenseInUse struct {
+	ProductID int64
+	EditionID int64
+}
+
+// IsErrLicenseInUse 检查是否为产品或版本仍被授权使用错误
+func IsErrLicenseInUse(err error) bool {
+	_, ok := err.(ErrLicenseInUse)
+	return ok
+}
+
+func (err ErrLicenseInUse) Error() string {
+	if err.EditionID > 0 {
+		return fmt.Sprintf("license edition is still used by devices [id: %d]", err.EditionID)
+	}
+	return fmt.Sprintf("license product is still used by devices [id: %d]", err.ProductID)
+}
+
+// ErrInvalidEntitlement 功能不合法错误
+type ErrInvalidEntitlement struct {
+	Name   string
+	Reason string
+}
+
+// IsErrInvalidEntitlement 检查是否为功能不合法错误
+func IsErrInvalidEntitlement(err error) bool {
+	_, ok := err.(ErrInvalidEntitlement)
+	return ok
+}
+
+func (err ErrInvalidEntitlement) Error() string {
+	return fmt.Sprintf("invalid entitlement %q: %s", err.Name, err.Reason)
+}
+
+// ErrLicenseNotExist 授权不存在错误
+type ErrLicenseNotExist struct {
+	ID         int64
+	LicenseKey string
+}
+
+// IsErrLicenseNotExist 检查是否为授权不存在错误
+func IsErrLicenseNotExist(err error) bool {
+	_, ok := err.(ErrLicenseNotExist)
+	return ok
+}
+
+func (err ErrLicenseNotExist) Error() string {
+	if err.ID > 0 {
+		return fmt.Sprintf("license does not exist [id: %d]", err.ID)
+	}
+	return fmt.Sprintf("license does not exist [license_key: %s]", err.LicenseKey)
+}
+
+// ErrActivationNotExist 激活不存在错误，浮动授权的租约过期后也返回此错误
+type ErrActivationNotExist struct {
+	ID          int64
+	MachineCode string
+}
+
+// IsErrActivationNotExist 检查是否为激活不存在错误
+func IsErrActivationNotExist(err error) bool {
+	_, ok := err.(ErrActivationNotExist)
+	return ok
+}
+
+func (err ErrActivationNotExist) Error() string {
+	if err.ID > 0 {
+		return fmt.Sprintf("license activation does not exist [id: %d]", err.ID)
+	}
+	return fmt.Sprintf("license activation does not exist [machine_code: %s]", err.MachineCode)
+}
+
+// ErrNoSeatsAvailable 授权的席位已用完错误
+type ErrNoSeatsAvailable struct {
+	LicenseID int64
+	Seats     int
+}
+
+// IsErrNoSeatsAvailable 检查是否为席位已用完错误
+func IsErrNoSeatsAvailable(err error) bool {
+	_, ok := err.(ErrNoSeatsAvailable)
+	return ok
+}
+
+func (err ErrNoSeatsAvailable) Error() string {
+	return fmt.Sprintf("all %d seats of license are in use [id: %d]", err.Seats, err.LicenseID)
+}
+
+// ErrAssignmentNotExist 席位分配不存在错误
+type ErrAssignmentNotExist struct {
+	LicenseID int64
+	UserID    int64
+}
+
+// IsErrAssignmentNotExist 检查是否为席位分配不存在错误
+func IsErrAssignmentNotExist(err error) bool {
+	_, ok := err.(ErrAssignmentNotExist)
+	return ok
+}
+
+func (err ErrAssignmentNotExist) Error() string {
+	return fmt.Sprintf("license seat is not assigned to user [license_id: %d, user_id: %d]", err.LicenseID, err.UserID)
+}
+
+// ErrLicenseNotActive 授权已禁用或已过期错误
+type ErrLicenseNotActive struct {
+	ID int64
+}
+
+// IsErrLicenseNotActive 检查是否为授权已禁用或已过期错误
+func IsErrLicenseNotActive(err error) bool {
+	_, ok := err.(ErrLicenseNotActive)
+	return ok
+}
+
+func (err ErrLicenseNotActive) Error() string {
+	return fmt.Sprintf("license is disabled or expired [id: %d]", err.ID)
+}
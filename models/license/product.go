@@ -0,0 +1,186 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+	"regexp"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// keyPattern 产品和版本标识的格式，标识会写入离线授权令牌
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,49}$`)
+
+// IsValidKey 检查产品或版本标识是否合法
+func IsValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
+
+// LicenseProduct 用户销售的产品，每个产品有多个版本
+type LicenseProduct struct { //revive:disable-line:exported
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"NOT NULL UNIQUE(s)"` // 所属用户ID
+	Key         string             `xorm:"VARCHAR(50) NOT NULL UNIQUE(s)"`
+	Name        string             `xorm:"VARCHAR(255) NOT NULL"`
+	Description string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// LicenseEdition 产品的版本（例如专业版、企业版），决定授权包含的功能
+type LicenseEdition struct { //revive:disable-line:exported
+	ID          int64              `xorm:"pk autoincr"`
+	ProductID   int64              `xorm:"NOT NULL UNIQUE(s)"`
+	Key         string             `xorm:"VARCHAR(50) NOT NULL UNIQUE(s)"`
+	Name        string             `xorm:"VARCHAR(255) NOT NULL"`
+	Features    []*Entitlement     `xorm:"JSON TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(LicenseProduct))
+	db.RegisterModel(new(LicenseEdition))
+}
+
+// TableName 表名
+func (p *LicenseProduct) TableName() string {
+	return "license_product"
+}
+
+// TableName 表名
+func (e *LicenseEdition) TableName() string {
+	return "license_edition"
+}
+
+// CreateProduct 创建产品
+func CreateProduct(ctx context.Context, product *LicenseProduct) error {
+	exist, err := db.GetEngine(ctx).Where("user_id = ? AND `key` = ?", product.UserID, product.Key).Exist(&LicenseProduct{})
+	if err != nil {
+		return err
+	}
+	if exist {
+		return ErrProductAlreadyExist{Key: product.Key}
+	}
+	_, err = db.GetEngine(ctx).Insert(product)
+	return err
+}
+
+// GetProductByUserAndID 根据用户ID和产品ID获取产品
+func GetProductByUserAndID(ctx context.Context, userID, id int64) (*LicenseProduct, error) {
+	product := &LicenseProduct{}
+	has, err := db.GetEngine(ctx).Where("user_id = ? AND id = ?", userID, id).Get(product)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrProductNotExist{ID: id}
+	}
+	return product, nil
+}
+
+// GetProductByID 根据ID获取产品
+func GetProductByID(ctx context.Context, id int64) (*LicenseProduct, error) {
+	product := &LicenseProduct{}
+	has, err := db.GetEngine(ctx).ID(id).Get(product)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrProductNotExist{ID: id}
+	}
+	return product, nil
+}
+
+// ListProducts 列出用户的产品
+func ListProducts(ctx context.Context, userID int64) ([]*LicenseProduct, error) {
+	products := make([]*LicenseProduct, 0, 5)
+	return products, db.GetEngine(ctx).Where("user_id = ?", userID).OrderBy("id").Find(&products)
+}
+
+// DeleteProduct 删除产品及其版本，仍有授权使用该产品时不能删除
+func DeleteProduct(ctx context.Context, product *LicenseProduct) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		inUse, err := db.GetEngine(ctx).Where("product_id = ?", product.ID).Exist(&AuthorizedDevice{})
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrLicenseInUse{ProductID: product.ID}
+		}
+		if _, err := db.GetEngine(ctx).Where("product_id = ?", product.ID).Delete(&LicenseEdition{}); err != nil {
+			return err
+		}
+		_, err = db.GetEngine(ctx).ID(product.ID).Delete(&LicenseProduct{})
+		return err
+	})
+}
+
+// CreateEdition 创建版本
+func CreateEdition(ctx context.Context, edition *LicenseEdition) error {
+	exist, err := db.GetEngine(ctx).Where("product_id = ? AND `key` = ?", edition.ProductID, edition.Key).Exist(&LicenseEdition{})
+	if err != nil {
+		return err
+	}
+	if exist {
+		return ErrEditionAlreadyExist{Key: edition.Key}
+	}
+	_, err = db.GetEngine(ctx).Insert(edition)
+	return err
+}
+
+// GetEditionByProductAndID 根据产品ID和版本ID获取版本
+func GetEditionByProductAndID(ctx context.Context, productID, id int64) (*LicenseEdition, error) {
+	edition := &LicenseEdition{}
+	has, err := db.GetEngine(ctx).Where("product_id = ? AND id = ?", productID, id).Get(edition)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrEditionNotExist{ID: id}
+	}
+	return edition, nil
+}
+
+// GetEditionByID 根据ID获取版本
+func GetEditionByID(ctx context.Context, id int64) (*LicenseEdition, error) {
+	edition := &LicenseEdition{}
+	has, err := db.GetEngine(ctx).ID(id).Get(edition)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrEditionNotExist{ID: id}
+	}
+	return edition, nil
+}
+
+// ListEditions 列出产品的版本
+func ListEditions(ctx context.Context, productID int64) ([]*LicenseEdition, error) {
+	editions := make([]*LicenseEdition, 0, 5)
+	return editions, db.GetEngine(ctx).Where("product_id = ?", productID).OrderBy("id").Find(&editions)
+}
+
+// UpdateEdition 更新版本的名称和功能
+func UpdateEdition(ctx context.Context, edition *LicenseEdition) error {
+	_, err := db.GetEngine(ctx).ID(edition.ID).Cols("name", "features").Update(edition)
+	return err
+}
+
+// DeleteEdition 删除版本，仍有授权使用该版本时不能删除
+func DeleteEdition(ctx context.Context, edition *LicenseEdition) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		inUse, err := db.GetEngine(ctx).Where("edition_id = ?", edition.ID).Exist(&AuthorizedDevice{})
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrLicenseInUse{EditionID: edition.ID}
+		}
+		_, err = db.GetEngine(ctx).ID(edition.ID).Delete(&LicenseEdition{})
+		return err
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RevokedDevice 已删除的授权设备或已停用的激活。删除时记录，使之前签发的离线授权令牌出现在吊销列表中
+type RevokedDevice struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"NOT NULL INDEX"`
+	DeviceID    string             `xorm:"VARCHAR(64) NOT NULL"`
+	MachineCode string             `xorm:"VARCHAR(64) NOT NULL"`
+	ExpiryDate  timeutil.TimeStamp `xorm:"INDEX"` // 原授权的到期时间，到期后令牌自然失效，不再需要出现在吊销列表中
+	RevokedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(RevokedDevice))
+}
+
+// TableName 表名
+func (r *RevokedDevice) TableName() string {
+	return "license_revoked_device"
+}
+
+// Revocation 吊销列表中的一项
+type Revocation struct {
+	DeviceID    string             `json:"device_id"`
+	MachineCode string             `json:"machine_code"`
+	Reason      string             `json:"reason"` // disabled 或 deleted
+	RevokedUnix timeutil.TimeStamp `json:"revoked_at"`
+}
+
+// 吊销原因
+const (
+	RevocationReasonDisabled = "disabled"
+	RevocationReasonDeleted  = "deleted"
+)
+
+// notExpiredCond 尚未过期（包括永久授权）的条件
+const notExpiredCond = "(expiry_date = 0 OR expiry_date IS NULL OR expiry_date > ?)"
+
+// GetRevocations 获取用户尚未过期的已禁用和已删除设备，以及已禁用的多席位授权的激活，已过期的授权不需要吊销
+func GetRevocations(ctx context.Context, userID int64) ([]*Revocation, error) {
+	now := timeutil.TimeStampNow()
+
+	disabled := make([]*AuthorizedDevice, 0, 10)
+	if err := db.GetEngine(ctx).
+		Where("user_id = ? AND is_enabled = ?", userID, false).
+		And(notExpiredCond, now).
+		OrderBy("id").
+		Find(&disabled); err != nil {
+		return nil, err
+	}
+
+	deleted := make([]*RevokedDevice, 0, 10)
+	if err := db.GetEngine(ctx).
+		Where("user_id = ?", userID).
+		And(notExpiredCond, now).
+		OrderBy("id").
+		Find(&deleted); err != nil {
+		return nil, err
+	}
+
+	disabledLicenses := make([]*License, 0, 10)
+	if err := db.GetEngine(ctx).
+		Where("user_id = ? AND is_enabled = ?", userID, false).
+		And(notExpiredCond, now).
+		Find(&disabledLicenses); err != nil {
+		return nil, err
+	}
+	licenseByID := make(map[int64]*License, len(disabledLicenses))
+	for _, l := range disabledLicenses {
+		licenseByID[l.ID] = l
+	}
+	activations := make([]*LicenseActivation, 0, 10)
+	if len(disabledLicenses) > 0 {
+		if err := db.GetEngine(ctx).
+			Where(builder.In("license_id", slices.Collect(maps.Keys(licenseByID))).And(activeCond(now))).
+			OrderBy("id").
+			Find(&activations); err != nil {
+			return nil, err
+		}
+	}
+
+	revocations := make([]*Revocation, 0, len(disabled)+len(deleted)+len(activations))
+	for _, d := range disabled {
+		revocations = append(revocations, &Revocation{
+			DeviceID:    d.DeviceID,
+			MachineCode: d.MachineCode,
+			Reason:      RevocationReasonDisabled,
+			RevokedUnix: d.UpdatedUnix,
+		})
+	}
+	for _, a := range activations {
+		revocations = append(revocations, &Revocation{
+			DeviceID:    a.DeviceID,
+			MachineCode: a.MachineCode,
+			Reason:      RevocationReasonDisabled,
+			RevokedUnix: licenseByID[a.LicenseID].UpdatedUnix,
+		})
+	}
+	for _, r := range deleted {
+		revocations = append(revocations, &Revocation{
+			DeviceID:    r.DeviceID,
+			MachineCode: r.MachineCode,
+			Reason:      RevocationReasonDeleted,
+			RevokedUnix: r.RevokedUnix,
+		})
+	}
+	return revocations, nil
+}
+
+// DeleteExpiredRevocations 删除原授权已过期的吊销记录
+func DeleteExpiredRevocations(ctx context.Context) error {
+	_, err := db.GetEngine(ctx).
+		Where("expiry_date > 0 AND expiry_date <= ?", timeutil.TimeStampNow()).
+		Delete(&RevokedDevice{})
+	return err
+}
@@ -0,0 +1,314 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// 授权类型
+const (
+	LicenseTypeSeat     = "seat"     // 按席位授权，激活后一直占用席位，直到停用
+	LicenseTypeFloating = "floating" // 浮动授权，客户端通过心跳续租，租约过期后自动释放席位
+)
+
+// License 可供多台机器使用的授权，同时激活的机器数不能超过席位数
+type License struct {
+	ID           int64              `xorm:"pk autoincr"`
+	UserID       int64              `xorm:"NOT NULL INDEX"` // 所属用户ID
+	Name         string             `xorm:"VARCHAR(255)"`
+	LicenseKey   string             `xorm:"VARCHAR(128) UNIQUE NOT NULL"`
+	Type         string             `xorm:"VARCHAR(20) NOT NULL DEFAULT 'seat'"`
+	Seats        int                `xorm:"NOT NULL DEFAULT 1"`
+	LeaseSeconds int64              `xorm:"NOT NULL DEFAULT 0"` // 浮动授权的租约时长
+	ProductID    int64              `xorm:"INDEX"`
+	EditionID    int64              `xorm:"INDEX"`
+	Features     []*Entitlement     `xorm:"JSON TEXT"`
+	IsEnabled    bool               `xorm:"NOT NULL DEFAULT true INDEX"`
+	ExpiryDate   timeutil.TimeStamp `xorm:"INDEX"`
+	Remarks      string             `xorm:"TEXT"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+
+	SeatsUsed int64 `xorm:"-"` // 由 LoadSeatsUsed 填充
+}
+
+// LicenseActivation 授权在一台机器上的激活，占用一个席位
+type LicenseActivation struct { //revive:disable-line:exported
+	ID                int64              `xorm:"pk autoincr"`
+	LicenseID         int64              `xorm:"NOT NULL UNIQUE(s)"`
+	MachineCode       string             `xorm:"VARCHAR(64) NOT NULL UNIQUE(s)"`
+	MachineName       string             `xorm:"VARCHAR(200)"`
+	DeviceID          string             `xorm:"VARCHAR(64) NOT NULL"` // 离线授权令牌的 sub，用于吊销
+	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
+	LastHeartbeatUnix timeutil.TimeStamp
+	LeaseExpiresUnix  timeutil.TimeStamp `xorm:"INDEX"` // 浮动授权的租约到期时间，按席位授权为 0
+	UserID            int64              `xorm:"INDEX"` // 使用分配的席位激活的组织成员，通过 API 密钥激活时为 0
+}
+
+func init() {
+	db.RegisterModel(new(License))
+	db.RegisterModel(new(LicenseActivation))
+}
+
+// TableName 表名
+func (l *License) TableName() string {
+	return "license"
+}
+
+// TableName 表名
+func (a *LicenseActivation) TableName() string {
+	return "license_activation"
+}
+
+// IsFloating 是否为浮动授权
+func (l *License) IsFloating() bool {
+	return l.Type == LicenseTypeFloating
+}
+
+// LeaseDuration 浮动授权的租约时长
+func (l *License) LeaseDuration() time.Duration {
+	return time.Duration(l.LeaseSeconds) * time.Second
+}
+
+// IsExpired 授权是否已过期，永久授权不会过期
+func (l *License) IsExpired() bool {
+	return !l.ExpiryDate.IsZero() && l.ExpiryDate.AsTime().Before(time.Now())
+}
+
+// IsValid 检查授权是否有效
+func (l *License) IsValid() bool {
+	return l.IsEnabled && !l.IsExpired()
+}
+
+// IsLeaseExpired 浮动授权的租约是否已过期
+func (a *LicenseActivation) IsLeaseExpired() bool {
+	return !a.LeaseExpiresUnix.IsZero() && a.LeaseExpiresUnix.AsTime().Before(time.Now())
+}
+
+// TokenExpiry 激活的离线授权令牌到期时间：浮动授权为租约到期时间，但不晚于授权到期时间
+func (a *LicenseActivation) TokenExpiry(l *License) timeutil.TimeStamp {
+	if a.LeaseExpiresUnix.IsZero() {
+		return l.ExpiryDate
+	}
+	if !l.ExpiryDate.IsZero() && l.ExpiryDate < a.LeaseExpiresUnix {
+		return l.ExpiryDate
+	}
+	return a.LeaseExpiresUnix
+}
+
+// CreateLicense 创建授权
+func CreateLicense(ctx context.Context, l *License) error {
+	_, err := db.GetEngine(ctx).Insert(l)
+	return err
+}
+
+// GetLicenseByUserAndID 根据用户ID和授权ID获取授权
+func GetLicenseByUserAndID(ctx context.Context, userID, id int64) (*License, error) {
+	l := &License{}
+	has, err := db.GetEngine(ctx).Where("user_id = ? AND id = ?", userID, id).Get(l)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrLicenseNotExist{ID: id}
+	}
+	return l, nil
+}
+
+// GetLicenseByID 根据ID获取授权，调用方需要自行检查访问权限
+func GetLicenseByID(ctx context.Context, id int64) (*License, error) {
+	l := &License{}
+	has, err := db.GetEngine(ctx).ID(id).Get(l)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrLicenseNotExist{ID: id}
+	}
+	return l, nil
+}
+
+// GetLicenseByUserAndKey 根据用户ID和授权码获取授权
+func GetLicenseByUserAndKey(ctx context.Context, userID int64, licenseKey string) (*License, error) {
+	l := &License{}
+	has, err := db.GetEngine(ctx).Where("user_id = ? AND license_key = ?", userID, licenseKey).Get(l)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrLicenseNotExist{LicenseKey: licenseKey}
+	}
+	return l, nil
+}
+
+// LockLicense 在事务中锁定授权记录，使同一授权的并发激活依次执行，避免超出席位数
+func LockLicense(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).Exec("UPDATE `license` SET updated_unix = ? WHERE id = ?", timeutil.TimeStampNow(), id)
+	return err
+}
+
+// UpdateLicense 更新授权
+func UpdateLicense(ctx context.Context, l *License) error {
+	_, err := db.GetEngine(ctx).ID(l.ID).AllCols().Update(l)
+	return err
+}
+
+// SearchLicensesOptions 搜索授权选项
+type SearchLicensesOptions struct {
+	db.ListOptions
+	UserID int64 // 0 表示所有用户，仅供管理员使用
+}
+
+// SearchLicenses 搜索授权，并填充已使用的席位数
+func SearchLicenses(ctx context.Context, opts *SearchLicensesOptions) ([]*License, int64, error) {
+	cond := builder.NewCond()
+	if opts.UserID > 0 {
+		cond = cond.And(builder.Eq{"user_id": opts.UserID})
+	}
+	sess := db.GetEngine(ctx).Where(cond).OrderBy("id DESC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+
+	licenses := make([]*License, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&licenses)
+	if err != nil {
+		return nil, 0, err
+	}
+	return licenses, count, LoadSeatsUsed(ctx, licenses...)
+}
+
+// activeCond 占用席位的激活：按席位授权的激活和租约未过期的浮动授权激活
+func activeCond(now timeutil.TimeStamp) builder.Cond {
+	return builder.Or(builder.Eq{"lease_expires_unix": 0}, builder.Gte{"lease_expires_unix": now})
+}
+
+// LoadSeatsUsed 填充授权已使用的席位数
+func LoadSeatsUsed(ctx context.Context, licenses ...*License) error {
+	if len(licenses) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(licenses))
+	for _, l := range licenses {
+		ids = append(ids, l.ID)
+	}
+
+	type seatCount struct {
+		LicenseID int64
+		Count     int64
+	}
+	counts := make([]*seatCount, 0, len(ids))
+	if err := db.GetEngine(ctx).Table("license_activation").
+		Select("license_id, COUNT(*) AS count").
+		Where(builder.In("license_id", ids).And(activeCond(timeutil.TimeStampNow()))).
+		GroupBy("license_id").
+		Find(&counts); err != nil {
+		return err
+	}
+	used := make(map[int64]int64, len(counts))
+	for _, c := range counts {
+		used[c.LicenseID] = c.Count
+	}
+	for _, l := range licenses {
+		l.SeatsUsed = used[l.ID]
+	}
+	return nil
+}
+
+// CountActiveActivations 统计授权已使用的席位数
+func CountActiveActivations(ctx context.Context, licenseID int64) (int64, error) {
+	return db.GetEngine(ctx).Where(builder.Eq{"license_id": licenseID}.And(activeCond(timeutil.TimeStampNow()))).
+		Count(&LicenseActivation{})
+}
+
+// GetActivation 获取授权在指定机器上的激活
+func GetActivation(ctx context.Context, licenseID int64, machineCode string) (*LicenseActivation, error) {
+	a := &LicenseActivation{}
+	has, err := db.GetEngine(ctx).Where("license_id = ? AND machine_code = ?", licenseID, machineCode).Get(a)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrActivationNotExist{MachineCode: machineCode}
+	}
+	return a, nil
+}
+
+// ListActivations 列出授权的所有激活，包括租约已过期但尚未清理的激活
+func ListActivations(ctx context.Context, licenseID int64) ([]*LicenseActivation, error) {
+	activations := make([]*LicenseActivation, 0, 10)
+	return activations, db.GetEngine(ctx).Where("license_id = ?", licenseID).OrderBy("id").Find(&activations)
+}
+
+// ListUserActivations 列出组织成员使用分配的席位激活的机器
+func ListUserActivations(ctx context.Context, licenseID, userID int64) ([]*LicenseActivation, error) {
+	activations := make([]*LicenseActivation, 0, 1)
+	return activations, db.GetEngine(ctx).Where("license_id = ? AND user_id = ?", licenseID, userID).Find(&activations)
+}
+
+// CreateActivation 创建激活
+func CreateActivation(ctx context.Context, a *LicenseActivation) error {
+	_, err := db.GetEngine(ctx).Insert(a)
+	return err
+}
+
+// UpdateActivationLease 更新激活的心跳时间和租约
+func UpdateActivationLease(ctx context.Context, a *LicenseActivation) error {
+	_, err := db.GetEngine(ctx).ID(a.ID).Cols("machine_name", "last_heartbeat_unix", "lease_expires_unix").Update(a)
+	return err
+}
+
+// DeleteActivation 删除激活以释放席位。仍可能在使用的离线授权令牌记录到吊销列表中
+func DeleteActivation(ctx context.Context, l *License, a *LicenseActivation) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).ID(a.ID).Delete(&LicenseActivation{}); err != nil {
+			return err
+		}
+		if a.IsLeaseExpired() {
+			return nil
+		}
+		return db.Insert(ctx, &RevokedDevice{
+			UserID:      l.UserID,
+			DeviceID:    a.DeviceID,
+			MachineCode: a.MachineCode,
+			ExpiryDate:  a.TokenExpiry(l),
+		})
+	})
+}
+
+// DeleteLicense 删除授权及其所有激活和席位分配
+func DeleteLicense(ctx context.Context, l *License) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		activations, err := ListActivations(ctx, l.ID)
+		if err != nil {
+			return err
+		}
+		for _, a := range activations {
+			if err := DeleteActivation(ctx, l, a); err != nil {
+				return err
+			}
+		}
+		if _, err := db.GetEngine(ctx).Where("license_id = ?", l.ID).Delete(&LicenseAssignment{}); err != nil {
+			return err
+		}
+		_, err = db.GetEngine(ctx).ID(l.ID).Delete(&License{})
+		return err
+	})
+}
+
+// DeleteExpiredLeases 删除租约已过期的浮动授权激活，licenseID 为 0 时处理所有授权
+func DeleteExpiredLeases(ctx context.Context, licenseID int64) (int64, error) {
+	cond := builder.Gt{"lease_expires_unix": 0}.And(builder.Lt{"lease_expires_unix": timeutil.TimeStampNow()})
+	if licenseID > 0 {
+		cond = cond.And(builder.Eq{"license_id": licenseID})
+	}
+	return db.GetEngine(ctx).Where(cond).Delete(&LicenseActivation{})
+}
@@ -402,6 +402,17 @@ func prepareMigrationTasks() []*migration {
 		newMigration(325, "Fix missed repo_id when migrate attachments", v1_26.FixMissedRepoIDWhenMigrateAttachments),
 		newMigration(326, "Add plugin table", v1_26.AddPluginTable),
 		newMigration(327, "Add authorized device table", v1_26.AddAuthorizedDeviceTable),
+		newMigration(328, "Add plugin trusted key table", v1_26.AddPluginTrustedKeyTable),
+		newMigration(329, "Add source to plugin table", v1_26.AddSourceToPlugin),
+		newMigration(330, "Add previous version to plugin table", v1_26.AddPreviousVersionToPlugin),
+		newMigration(331, "Add permissions to plugin table", v1_26.AddPermissionsToPlugin),
+		newMigration(332, "Add plugin schema version table", v1_26.AddPluginSchemaVersionTable),
+		newMigration(333, "Add plugin audit table", v1_26.AddPluginAuditTable),
+		newMigration(334, "Add features to authorized device table", v1_26.AddFeaturesToAuthorizedDevice),
+		newMigration(335, "Add license api key and revoked device tables", v1_26.AddLicenseAPIKeyAndRevokedDeviceTables),
+		newMigration(336, "Add license product and edition tables", v1_26.AddLicenseProductAndEditionTables),
+		newMigration(337, "Add license and license activation tables", v1_26.AddLicenseAndActivationTables),
+		newMigration(338, "Add organization license management and license seat assignments", v1_26.AddOrgLicenseManagementAndSeatAssignments),
 	}
 	return preparedMigrations
 }
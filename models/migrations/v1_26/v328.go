@@ -0,0 +1,27 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_26
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddPluginTrustedKeyTable(x *xorm.Engine) error {
+	type Plugin struct {
+		Signer string `xorm:"VARCHAR(255)"`
+	}
+
+	type PluginTrustedKey struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Name        string             `xorm:"VARCHAR(200) NOT NULL"`
+		KeyType     string             `xorm:"VARCHAR(10) NOT NULL"`
+		Content     string             `xorm:"TEXT NOT NULL"`
+		Fingerprint string             `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(Plugin), new(PluginTrustedKey))
+}
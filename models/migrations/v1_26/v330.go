@@ -0,0 +1,17 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_26
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddPreviousVersionToPlugin(x *xorm.Engine) error {
+	type Plugin struct {
+		PreviousVersion string `xorm:"VARCHAR(50)"`
+		PreviousPath    string `xorm:"VARCHAR(500)"`
+	}
+
+	return x.Sync(new(Plugin))
+}
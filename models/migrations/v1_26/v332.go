@@ -0,0 +1,25 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_26
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type pluginSchemaVersion struct {
+	ID          int64              `xorm:"pk autoincr"`
+	PluginID    string             `xorm:"VARCHAR(100) UNIQUE NOT NULL"`
+	Version     int64              `xorm:"NOT NULL DEFAULT 0"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func (pluginSchemaVersion) TableName() string {
+	return "plugin_schema_version"
+}
+
+func AddPluginSchemaVersionTable(x *xorm.Engine) error {
+	return x.Sync(new(pluginSchemaVersion))
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_26
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type pluginAuditConfigChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+type pluginAudit struct {
+	ID          int64                      `xorm:"pk autoincr"`
+	PluginID    string                     `xorm:"VARCHAR(100) INDEX NOT NULL"`
+	Action      string                     `xorm:"VARCHAR(50) INDEX NOT NULL"`
+	DoerID      int64                      `xorm:"INDEX"`
+	DoerName    string                     `xorm:"VARCHAR(255)"`
+	OldVersion  string                     `xorm:"VARCHAR(50)"`
+	NewVersion  string                     `xorm:"VARCHAR(50)"`
+	ConfigDiff  []*pluginAuditConfigChange `xorm:"JSON TEXT"`
+	Detail      string                     `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp         `xorm:"created INDEX"`
+}
+
+func (pluginAudit) TableName() string {
+	return "plugin_audit"
+}
+
+func AddPluginAuditTable(x *xorm.Engine) error {
+	return x.Sync(new(pluginAudit))
+}
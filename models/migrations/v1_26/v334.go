@@ -0,0 +1,16 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_26
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddFeaturesToAuthorizedDevice(x *xorm.Engine) error {
+	type AuthorizedDevice struct {
+		Features []string `xorm:"JSON TEXT"`
+	}
+
+	return x.Sync(new(AuthorizedDevice))
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_26
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type licenseAPIKey struct {
+	ID           int64              `xorm:"pk autoincr"`
+	UserID       int64              `xorm:"NOT NULL INDEX"`
+	Name         string             `xorm:"VARCHAR(255) NOT NULL"`
+	KeyHash      string             `xorm:"VARCHAR(64) UNIQUE NOT NULL"`
+	KeyLastEight string             `xorm:"VARCHAR(8)"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	LastUsedUnix timeutil.TimeStamp `xorm:"INDEX"`
+}
+
+func (licenseAPIKey) TableName() string {
+	return "license_api_key"
+}
+
+type licenseRevokedDevice struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"NOT NULL INDEX"`
+	DeviceID    string             `xorm:"VARCHAR(64) NOT NULL"`
+	MachineCode string             `xorm:"VARCHAR(64) NOT NULL"`
+	ExpiryDate  timeutil.TimeStamp `xorm:"INDEX"`
+	RevokedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func (licenseRevokedDevice) TableName() string {
+	return "license_revoked_device"
+}
+
+func AddLicenseAPIKeyAndRevokedDeviceTables(x *xorm.Engine) error {
+	return x.Sync(new(licenseAPIKey), new(licenseRevokedDevice))
+}
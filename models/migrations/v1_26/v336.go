@@ -0,0 +1,52 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_26
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type licenseEntitlement struct {
+	Name     string `json:"name"`
+	Quantity int64  `json:"quantity,omitempty"`
+}
+
+type licenseProduct struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"NOT NULL UNIQUE(s)"`
+	Key         string             `xorm:"VARCHAR(50) NOT NULL UNIQUE(s)"`
+	Name        string             `xorm:"VARCHAR(255) NOT NULL"`
+	Description string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func (licenseProduct) TableName() string {
+	return "license_product"
+}
+
+type licenseEdition struct {
+	ID          int64                 `xorm:"pk autoincr"`
+	ProductID   int64                 `xorm:"NOT NULL UNIQUE(s)"`
+	Key         string                `xorm:"VARCHAR(50) NOT NULL UNIQUE(s)"`
+	Name        string                `xorm:"VARCHAR(255) NOT NULL"`
+	Features    []*licenseEntitlement `xorm:"JSON TEXT"`
+	CreatedUnix timeutil.TimeStamp    `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp    `xorm:"updated"`
+}
+
+func (licenseEdition) TableName() string {
+	return "license_edition"
+}
+
+func AddLicenseProductAndEditionTables(x *xorm.Engine) error {
+	type AuthorizedDevice struct {
+		ProductID int64 `xorm:"INDEX"`
+		EditionID int64 `xorm:"INDEX"`
+	}
+
+	return x.Sync(new(licenseProduct), new(licenseEdition), new(AuthorizedDevice))
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_26
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type seatLicense struct {
+	ID           int64                 `xorm:"pk autoincr"`
+	UserID       int64                 `xorm:"NOT NULL INDEX"`
+	Name         string                `xorm:"VARCHAR(255)"`
+	LicenseKey   string                `xorm:"VARCHAR(128) UNIQUE NOT NULL"`
+	Type         string                `xorm:"VARCHAR(20) NOT NULL DEFAULT 'seat'"`
+	Seats        int                   `xorm:"NOT NULL DEFAULT 1"`
+	LeaseSeconds int64                 `xorm:"NOT NULL DEFAULT 0"`
+	ProductID    int64                 `xorm:"INDEX"`
+	EditionID    int64                 `xorm:"INDEX"`
+	Features     []*licenseEntitlement `xorm:"JSON TEXT"`
+	IsEnabled    bool                  `xorm:"NOT NULL DEFAULT true INDEX"`
+	ExpiryDate   timeutil.TimeStamp    `xorm:"INDEX"`
+	Remarks      string                `xorm:"TEXT"`
+	CreatedUnix  timeutil.TimeStamp    `xorm:"created"`
+	UpdatedUnix  timeutil.TimeStamp    `xorm:"updated"`
+}
+
+func (seatLicense) TableName() string {
+	return "license"
+}
+
+type licenseActivation struct {
+	ID                int64              `xorm:"pk autoincr"`
+	LicenseID         int64              `xorm:"NOT NULL UNIQUE(s)"`
+	MachineCode       string             `xorm:"VARCHAR(64) NOT NULL UNIQUE(s)"`
+	MachineName       string             `xorm:"VARCHAR(200)"`
+	DeviceID          string             `xorm:"VARCHAR(64) NOT NULL"`
+	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
+	LastHeartbeatUnix timeutil.TimeStamp
+	LeaseExpiresUnix  timeutil.TimeStamp `xorm:"INDEX"`
+}
+
+func (licenseActivation) TableName() string {
+	return "license_activation"
+}
+
+func AddLicenseAndActivationTables(x *xorm.Engine) error {
+	return x.Sync(new(seatLicense), new(licenseActivation))
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_26
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddOrgLicenseManagementAndSeatAssignments(x *xorm.Engine) error {
+	type Team struct {
+		CanManageLicenses bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type LicenseActivation struct {
+		UserID int64 `xorm:"INDEX"`
+	}
+
+	type LicenseAssignment struct {
+		ID          int64              `xorm:"pk autoincr"`
+		LicenseID   int64              `xorm:"NOT NULL UNIQUE(s)"`
+		UserID      int64              `xorm:"NOT NULL UNIQUE(s) INDEX"`
+		AssignerID  int64              `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync(new(Team), new(LicenseActivation), new(LicenseAssignment)); err != nil {
+		return err
+	}
+
+	// owner teams can always manage the organization's licenses
+	_, err := x.Exec("UPDATE team SET can_manage_licenses = ? WHERE authorize = ?", true, 4)
+	return err
+}
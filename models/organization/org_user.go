@@ -114,6 +114,16 @@ func CanCreateOrgRepo(ctx context.Context, orgID, uid int64) (bool, error) {
 		Exist(new(Team))
 }
 
+// CanManageLicenses returns true if user can manage the licenses owned by organization
+func CanManageLicenses(ctx context.Context, orgID, uid int64) (bool, error) {
+	return db.GetEngine(ctx).
+		Where(builder.Eq{"team.can_manage_licenses": true}).
+		Join("INNER", "team_user", "team_user.team_id = team.id").
+		And("team_user.uid = ?", uid).
+		And("team_user.org_id = ?", orgID).
+		Exist(new(Team))
+}
+
 // IsUserOrgOwner returns true if user is in the owner team of given organization.
 func IsUserOrgOwner(ctx context.Context, users user_model.UserList, orgID int64) map[int64]bool {
 	results := make(map[int64]bool, len(users))
@@ -84,6 +84,7 @@ type Team struct {
 	Units                   []*TeamUnit `xorm:"-"`
 	IncludesAllRepositories bool        `xorm:"NOT NULL DEFAULT false"`
 	CanCreateOrgRepo        bool        `xorm:"NOT NULL DEFAULT false"`
+	CanManageLicenses       bool        `xorm:"NOT NULL DEFAULT false"`
 }
 
 func init() {
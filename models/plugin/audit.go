@@ -0,0 +1,149 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// 审计日志的操作类型
+const (
+	AuditActionInstall            = "install"
+	AuditActionUninstall          = "uninstall"
+	AuditActionEnable             = "enable"
+	AuditActionDisable            = "disable"
+	AuditActionQuarantine         = "quarantine" // 失败过多被自动禁用
+	AuditActionUpgrade            = "upgrade"
+	AuditActionRollback           = "rollback"
+	AuditActionConfig             = "config"
+	AuditActionApprovePermissions = "approve_permissions"
+)
+
+// AuditActions 所有操作类型，用于筛选
+var AuditActions = []string{
+	AuditActionInstall,
+	AuditActionUninstall,
+	AuditActionEnable,
+	AuditActionDisable,
+	AuditActionQuarantine,
+	AuditActionUpgrade,
+	AuditActionRollback,
+	AuditActionConfig,
+	AuditActionApprovePermissions,
+}
+
+// RedactedValue 审计日志中秘密字段的值
+const RedactedValue = "******"
+
+// ConfigChange 配置中一个字段的变更，秘密字段的值记录为 RedactedValue
+type ConfigChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// Audit 插件管理操作的审计日志，卸载插件后保留
+type Audit struct {
+	ID         int64           `xorm:"pk autoincr"`
+	PluginID   string          `xorm:"VARCHAR(100) INDEX NOT NULL"`
+	Action     string          `xorm:"VARCHAR(50) INDEX NOT NULL"`
+	DoerID     int64           `xorm:"INDEX"`        // 操作者，0 表示系统（命令行或自动隔离）
+	DoerName   string          `xorm:"VARCHAR(255)"` // 操作时的用户名，用户删除或改名后仍可追溯
+	OldVersion string          `xorm:"VARCHAR(50)"`
+	NewVersion string          `xorm:"VARCHAR(50)"`
+	ConfigDiff []*ConfigChange `xorm:"JSON TEXT"`
+	Detail     string          `xorm:"TEXT"` // 补充说明，如安装来源、隔离原因
+	// 创建时间
+	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX"`
+}
+
+func init() {
+	db.RegisterModel(new(Audit))
+}
+
+// TableName 表名
+func (a *Audit) TableName() string {
+	return "plugin_audit"
+}
+
+// InsertAudit 写入审计日志
+func InsertAudit(ctx context.Context, a *Audit) error {
+	return db.Insert(ctx, a)
+}
+
+// FindAuditOptions 审计日志的查询条件
+type FindAuditOptions struct {
+	db.ListOptions
+	PluginID string
+	Action   string
+	DoerName string
+	Since    timeutil.TimeStamp // 包含
+	Before   timeutil.TimeStamp // 不包含
+}
+
+// ToConds 转换为查询条件
+func (opts FindAuditOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.PluginID != "" {
+		cond = cond.And(builder.Eq{"plugin_id": opts.PluginID})
+	}
+	if opts.Action != "" {
+		cond = cond.And(builder.Eq{"action": opts.Action})
+	}
+	if opts.DoerName != "" {
+		cond = cond.And(builder.Eq{"doer_name": opts.DoerName})
+	}
+	if opts.Since > 0 {
+		cond = cond.And(builder.Gte{"created_unix": opts.Since})
+	}
+	if opts.Before > 0 {
+		cond = cond.And(builder.Lt{"created_unix": opts.Before})
+	}
+	return cond
+}
+
+// ToOrders 按时间倒序
+func (opts FindAuditOptions) ToOrders() string {
+	return "created_unix DESC, id DESC"
+}
+
+// DiffConfig 比较新旧配置，秘密字段只记录是否设置，不记录值
+func DiffConfig(fields []*ConfigField, old, cur map[string]any) []*ConfigChange {
+	var changes []*ConfigChange
+	for _, f := range fields {
+		o, n := old[f.Name], cur[f.Name]
+		if configValueEqual(o, n) {
+			continue
+		}
+		if f.Secret {
+			o, n = redactSecret(o), redactSecret(n)
+		}
+		changes = append(changes, &ConfigChange{Field: f.Name, Old: o, New: n})
+	}
+	return changes
+}
+
+// configValueEqual 按 JSON 比较配置值，从数据库读出的数字为 float64，表单解析的可能为 int64
+func configValueEqual(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+func redactSecret(v any) any {
+	if v == nil || v == "" {
+		return nil
+	}
+	return RedactedValue
+}
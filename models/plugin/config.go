@@ -0,0 +1,223 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/secret"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// 配置字段类型，对应 JSON Schema 的 type
+const (
+	ConfigTypeString  = "string"
+	ConfigTypeInteger = "integer"
+	ConfigTypeNumber  = "number"
+	ConfigTypeBoolean = "boolean"
+	ConfigTypeJSON    = "json" // array、object 等复杂类型以 JSON 文本编辑
+)
+
+// ConfigField 由 config_schema 的一个属性生成的配置表单字段
+type ConfigField struct {
+	Name        string
+	Type        string
+	Title       string
+	Description string
+	Default     any
+	Enum        []any
+	Required    bool
+	Secret      bool // writeOnly 或 format 为 password 的字段，加密存储且不在页面回显
+}
+
+// ParseConfigFields 解析 config_schema 顶层 properties 生成表单字段，按属性名排序。
+// 属性可通过 x-order 指定显示顺序
+func ParseConfigFields(schema map[string]any) []*ConfigField {
+	properties, _ := schema["properties"].(map[string]any)
+	var required []string
+	if list, ok := schema["required"].([]any); ok {
+		for _, name := range list {
+			if s, ok := name.(string); ok {
+				required = append(required, s)
+			}
+		}
+	}
+
+	fields := make([]*ConfigField, 0, len(properties))
+	orders := make(map[string]float64, len(properties))
+	for name, v := range properties {
+		prop, _ := v.(map[string]any)
+		field := &ConfigField{Name: name, Type: ConfigTypeJSON, Required: slices.Contains(required, name)}
+		switch t, _ := prop["type"].(string); t {
+		case ConfigTypeString, ConfigTypeInteger, ConfigTypeNumber, ConfigTypeBoolean:
+			field.Type = t
+		}
+		field.Title, _ = prop["title"].(string)
+		field.Description, _ = prop["description"].(string)
+		field.Default = prop["default"]
+		field.Enum, _ = prop["enum"].([]any)
+		writeOnly, _ := prop["writeOnly"].(bool)
+		format, _ := prop["format"].(string)
+		field.Secret = field.Type == ConfigTypeString && (writeOnly || format == "password")
+		orders[name], _ = prop["x-order"].(float64)
+		fields = append(fields, field)
+	}
+	slices.SortFunc(fields, func(a, b *ConfigField) int {
+		if orders[a.Name] != orders[b.Name] {
+			if orders[a.Name] < orders[b.Name] {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return fields
+}
+
+// ParseValue 将表单提交的文本按字段类型转换为配置值，空文本表示未设置
+func (f *ConfigField) ParseValue(s string) (any, error) {
+	if f.Type != ConfigTypeString {
+		s = strings.TrimSpace(s)
+	}
+	if s == "" && f.Type != ConfigTypeBoolean {
+		return nil, nil
+	}
+	switch f.Type {
+	case ConfigTypeString:
+		return s, nil
+	case ConfigTypeInteger:
+		return strconv.ParseInt(s, 10, 64)
+	case ConfigTypeNumber:
+		return strconv.ParseFloat(s, 64)
+	case ConfigTypeBoolean:
+		return s == "on" || s == "true", nil
+	default:
+		var v any
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// FormatValue 将配置值转换为表单中显示的文本
+func (f *ConfigField) FormatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool, int, int64:
+		return fmt.Sprint(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// ValidateConfig 按 config_schema 校验配置
+func ValidateConfig(pluginID string, schema, config map[string]any) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("config_schema.json", bytes.NewReader(b)); err != nil {
+		return ErrPluginConfigInvalid{PluginID: pluginID, Reason: "invalid config_schema: " + err.Error()}
+	}
+	sch, err := c.Compile("config_schema.json")
+	if err != nil {
+		return ErrPluginConfigInvalid{PluginID: pluginID, Reason: "invalid config_schema: " + err.Error()}
+	}
+
+	// 统一为 JSON 解码后的值类型再校验
+	b, err = json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if err := sch.Validate(v); err != nil {
+		var ve *jsonschema.ValidationError
+		if !errors.As(err, &ve) {
+			return err
+		}
+		for len(ve.Causes) > 0 {
+			ve = ve.Causes[0]
+		}
+		return ErrPluginConfigInvalid{PluginID: pluginID, Field: strings.TrimPrefix(ve.InstanceLocation, "/"), Reason: ve.Message}
+	}
+	return nil
+}
+
+// ApplyConfigDefaults 为未设置的字段填充 schema 中的默认值
+func ApplyConfigDefaults(fields []*ConfigField, config map[string]any) map[string]any {
+	result := make(map[string]any, len(fields))
+	for k, v := range config {
+		result[k] = v
+	}
+	for _, f := range fields {
+		if _, ok := result[f.Name]; !ok && f.Default != nil {
+			result[f.Name] = f.Default
+		}
+	}
+	return result
+}
+
+// GetConfig 解析存储的配置并解密其中的秘密字段
+func (p *Plugin) GetConfig(fields []*ConfigField, key string) (map[string]any, error) {
+	config := make(map[string]any)
+	if p.Config != "" {
+		if err := json.Unmarshal([]byte(p.Config), &config); err != nil {
+			return nil, fmt.Errorf("unmarshal config: %w", err)
+		}
+	}
+	for _, f := range fields {
+		if s, ok := config[f.Name].(string); ok && f.Secret && s != "" {
+			plain, err := secret.DecryptSecret(key, s)
+			if err != nil {
+				return nil, fmt.Errorf("decrypt %s: %w", f.Name, err)
+			}
+			config[f.Name] = plain
+		}
+	}
+	return config, nil
+}
+
+// SetConfig 加密秘密字段后保存配置
+func (p *Plugin) SetConfig(fields []*ConfigField, key string, config map[string]any) error {
+	stored := make(map[string]any, len(config))
+	for k, v := range config {
+		stored[k] = v
+	}
+	for _, f := range fields {
+		if s, ok := stored[f.Name].(string); ok && f.Secret && s != "" {
+			encrypted, err := secret.EncryptSecret(key, s)
+			if err != nil {
+				return fmt.Errorf("encrypt %s: %w", f.Name, err)
+			}
+			stored[f.Name] = encrypted
+		}
+	}
+	b, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	p.Config = string(b)
+	return nil
+}
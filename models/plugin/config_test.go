@@ -0,0 +1,118 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/json"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigSchema = `{
+	"type": "object",
+	"required": ["endpoint"],
+	"properties": {
+		"endpoint": {"type": "string", "title": "Endpoint", "format": "uri", "x-order": 1},
+		"token": {"type": "string", "writeOnly": true, "x-order": 2},
+		"max_devices": {"type": "integer", "default": 10, "minimum": 1},
+		"mode": {"type": "string", "enum": ["fast", "safe"]},
+		"enabled": {"type": "boolean", "default": true},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+func parseTestSchema(t *testing.T) map[string]any {
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal([]byte(testConfigSchema), &schema))
+	return schema
+}
+
+func TestParseConfigFields(t *testing.T) {
+	fields := ParseConfigFields(parseTestSchema(t))
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"enabled", "max_devices", "mode", "tags", "endpoint", "token"}, names)
+
+	assert.Equal(t, ConfigTypeBoolean, fields[0].Type)
+	assert.InDelta(t, 10, fields[1].Default, 0)
+	assert.Equal(t, []any{"fast", "safe"}, fields[2].Enum)
+	assert.Equal(t, ConfigTypeJSON, fields[3].Type)
+	assert.True(t, fields[4].Required)
+	assert.Equal(t, "Endpoint", fields[4].Title)
+	assert.True(t, fields[5].Secret)
+}
+
+func TestConfigFieldParseValue(t *testing.T) {
+	v, err := (&ConfigField{Type: ConfigTypeInteger}).ParseValue(" 42 ")
+	require.NoError(t, err)
+	assert.EqualValues(t, 42, v)
+
+	_, err = (&ConfigField{Type: ConfigTypeInteger}).ParseValue("4.2")
+	assert.Error(t, err)
+
+	v, err = (&ConfigField{Type: ConfigTypeBoolean}).ParseValue("")
+	require.NoError(t, err)
+	assert.Equal(t, false, v)
+
+	v, err = (&ConfigField{Type: ConfigTypeJSON}).ParseValue(`["a"]`)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"a"}, v)
+
+	v, err = (&ConfigField{Type: ConfigTypeString}).ParseValue("")
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestValidateConfig(t *testing.T) {
+	schema := parseTestSchema(t)
+
+	assert.NoError(t, ValidateConfig("test", schema, map[string]any{"endpoint": "https://example.com", "max_devices": int64(5)}))
+
+	err := ValidateConfig("test", schema, map[string]any{"endpoint": "https://example.com", "max_devices": int64(0)})
+	require.True(t, IsErrPluginConfigInvalid(err), err)
+	assert.Equal(t, "max_devices", err.(ErrPluginConfigInvalid).Field)
+
+	err = ValidateConfig("test", schema, map[string]any{"endpoint": "https://example.com", "mode": "slow"})
+	require.True(t, IsErrPluginConfigInvalid(err), err)
+	assert.Equal(t, "mode", err.(ErrPluginConfigInvalid).Field)
+
+	assert.True(t, IsErrPluginConfigInvalid(ValidateConfig("test", schema, map[string]any{})))
+}
+
+func TestPluginConfigSecrets(t *testing.T) {
+	fields := ParseConfigFields(parseTestSchema(t))
+	p := &Plugin{}
+	require.NoError(t, p.SetConfig(fields, "secret-key", map[string]any{"endpoint": "https://example.com", "token": "s3cr3t"}))
+	assert.NotContains(t, p.Config, "s3cr3t")
+	assert.Contains(t, p.Config, "https://example.com")
+
+	config, err := p.GetConfig(fields, "secret-key")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", config["token"])
+
+	config = ApplyConfigDefaults(fields, config)
+	assert.Equal(t, true, config["enabled"])
+	assert.InDelta(t, 10, config["max_devices"], 0)
+}
+
+func TestDiffConfig(t *testing.T) {
+	fields := ParseConfigFields(parseTestSchema(t))
+	old := map[string]any{"endpoint": "https://a.example.com", "token": "old-secret", "max_devices": float64(10), "tags": []any{"x"}}
+	cur := map[string]any{"endpoint": "https://b.example.com", "token": "new-secret", "max_devices": int64(10), "tags": []string{"x"}, "mode": "safe"}
+
+	changes := DiffConfig(fields, old, cur)
+	assert.Equal(t, []*ConfigChange{
+		{Field: "mode", Old: nil, New: "safe"},
+		{Field: "endpoint", Old: "https://a.example.com", New: "https://b.example.com"},
+		{Field: "token", Old: RedactedValue, New: RedactedValue},
+	}, changes)
+
+	changes = DiffConfig(fields, old, map[string]any{"endpoint": "https://a.example.com", "max_devices": float64(10), "tags": []any{"x"}})
+	assert.Equal(t, []*ConfigChange{{Field: "token", Old: RedactedValue, New: nil}}, changes)
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"regexp"
+)
+
+// DefaultCronSchedule 未指定执行计划的定时任务每天零点执行
+const DefaultCronSchedule = "@midnight"
+
+// CronTask 插件注册的定时任务
+type CronTask struct {
+	Name       string `json:"name"`         // 插件内唯一的任务名，只能包含小写字母、数字和下划线
+	Title      string `json:"title"`        // 在管理后台显示的名称，为空时显示完整任务名
+	Schedule   string `json:"schedule"`     // 默认执行计划，如 "@every 1h"、"0 3 * * *"
+	RunAtStart bool   `json:"run_at_start"` // 插件启用时是否立即执行一次
+
+	// Run 执行任务，ctx 在 Gitea 关闭时取消。进程运行时的插件不需要设置，由宿主通过 RPC 调用
+	Run func(ctx context.Context) error `json:"-"`
+}
+
+// CronProvider 需要定时任务的插件实现此接口。任务在插件启用时注册到 Gitea 的定时任务中，
+// 完整名称为 plugin.<插件ID>.<任务名>，可在 app.ini 的 [cron.plugin.<插件ID>.<任务名>] 中配置；插件禁用或卸载时自动移除
+type CronProvider interface {
+	CronTasks() []*CronTask
+}
+
+var validCronTaskNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// IsValidCronTaskName 检查定时任务名是否合法
+func IsValidCronTaskName(name string) bool {
+	return validCronTaskNamePattern.MatchString(name)
+}
+
+// CronTaskName 插件定时任务在 Gitea 定时任务中的完整名称
+func CronTaskName(pluginID, name string) string {
+	return "plugin." + pluginID + "." + name
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// Dependency 插件依赖，plugin.json 中的格式为 "<插件ID>" 或 "<插件ID> <版本约束>"，
+// 如 "license-core >=1.2.0, <2.0.0"
+type Dependency struct {
+	PluginID   string
+	Constraint string // 为空时表示任意版本
+}
+
+// ParseDependency 解析 plugin.json 中的依赖声明
+func ParseDependency(s string) (*Dependency, error) {
+	s = strings.TrimSpace(s)
+	id, constraint := s, ""
+	if i := strings.IndexAny(s, " =!<>~"); i >= 0 {
+		id, constraint = s[:i], strings.TrimSpace(s[i:])
+	}
+	if !IsValidPluginID(id) {
+		return nil, ErrPluginDependencyInvalid{Dependency: s}
+	}
+	if constraint != "" {
+		if _, err := version.NewConstraint(constraint); err != nil {
+			return nil, ErrPluginDependencyInvalid{Dependency: s}
+		}
+	}
+	return &Dependency{PluginID: id, Constraint: constraint}, nil
+}
+
+// Satisfies 检查给定版本是否满足依赖的版本约束
+func (d *Dependency) Satisfies(v string) bool {
+	return CheckVersionConstraint(d.Constraint, v)
+}
+
+// ParseDependencies 解析插件声明的全部依赖
+func (m *PluginMetadata) ParseDependencies() ([]*Dependency, error) {
+	deps := make([]*Dependency, 0, len(m.Dependencies))
+	for _, s := range m.Dependencies {
+		dep, err := ParseDependency(s)
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// CheckVersionConstraint 检查版本是否满足约束，约束为空时总是满足。
+// 预发布版本和构建元数据按其正式版本比较，如 1.26.0-rc1 满足 >=1.26.0
+func CheckVersionConstraint(constraint, v string) bool {
+	if constraint == "" {
+		return true
+	}
+	c, err := version.NewConstraint(constraint)
+	if err != nil {
+		return false
+	}
+	ver, err := version.NewVersion(v)
+	if err != nil {
+		return false
+	}
+	return c.Check(ver.Core())
+}
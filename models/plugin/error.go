@@ -3,7 +3,10 @@
 
 package plugin
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ErrPluginNotExist 插件不存在错误
 type ErrPluginNotExist struct {
@@ -34,3 +37,291 @@ func IsErrPluginAlreadyExist(err error) bool {
 	_, ok := err.(ErrPluginAlreadyExist)
 	return ok
 }
+
+// ErrTrustedKeyInvalid 发布者密钥无效错误
+type ErrTrustedKeyInvalid struct {
+	Reason string
+}
+
+func (err ErrTrustedKeyInvalid) Error() string {
+	return fmt.Sprintf("invalid trusted key: %s", err.Reason)
+}
+
+// IsErrTrustedKeyInvalid 检查是否为发布者密钥无效错误
+func IsErrTrustedKeyInvalid(err error) bool {
+	_, ok := err.(ErrTrustedKeyInvalid)
+	return ok
+}
+
+// ErrTrustedKeyAlreadyExist 发布者密钥已存在错误
+type ErrTrustedKeyAlreadyExist struct {
+	Fingerprint string
+}
+
+func (err ErrTrustedKeyAlreadyExist) Error() string {
+	return fmt.Sprintf("trusted key already exists [fingerprint: %s]", err.Fingerprint)
+}
+
+// IsErrTrustedKeyAlreadyExist 检查是否为发布者密钥已存在错误
+func IsErrTrustedKeyAlreadyExist(err error) bool {
+	_, ok := err.(ErrTrustedKeyAlreadyExist)
+	return ok
+}
+
+// ErrPackageVerification 插件包签名或校验和验证失败错误
+type ErrPackageVerification struct {
+	Reason string
+}
+
+func (err ErrPackageVerification) Error() string {
+	return fmt.Sprintf("plugin package verification failed: %s", err.Reason)
+}
+
+// IsErrPackageVerification 检查是否为插件包验证失败错误
+func IsErrPackageVerification(err error) bool {
+	_, ok := err.(ErrPackageVerification)
+	return ok
+}
+
+// ErrPluginInvalidID 插件 ID 不合法错误
+type ErrPluginInvalidID struct {
+	PluginID string
+}
+
+func (err ErrPluginInvalidID) Error() string {
+	return fmt.Sprintf("invalid plugin id [plugin_id: %s]", err.PluginID)
+}
+
+// IsErrPluginInvalidID 检查是否为插件 ID 不合法错误
+func IsErrPluginInvalidID(err error) bool {
+	_, ok := err.(ErrPluginInvalidID)
+	return ok
+}
+
+// ErrPluginDependencyInvalid 依赖声明格式错误
+type ErrPluginDependencyInvalid struct {
+	Dependency string
+}
+
+func (err ErrPluginDependencyInvalid) Error() string {
+	return fmt.Sprintf("invalid plugin dependency [dependency: %s]", err.Dependency)
+}
+
+// IsErrPluginDependencyInvalid 检查是否为依赖声明格式错误
+func IsErrPluginDependencyInvalid(err error) bool {
+	_, ok := err.(ErrPluginDependencyInvalid)
+	return ok
+}
+
+// ErrPluginIncompatible 插件与当前 Gitea 版本不兼容错误
+type ErrPluginIncompatible struct {
+	PluginID     string
+	Constraint   string
+	GiteaVersion string
+}
+
+func (err ErrPluginIncompatible) Error() string {
+	return fmt.Sprintf("plugin requires gitea %s, running %s [plugin_id: %s]", err.Constraint, err.GiteaVersion, err.PluginID)
+}
+
+// IsErrPluginIncompatible 检查是否为插件与 Gitea 版本不兼容错误
+func IsErrPluginIncompatible(err error) bool {
+	_, ok := err.(ErrPluginIncompatible)
+	return ok
+}
+
+// ErrPluginDependencyNotSatisfied 插件依赖未满足错误
+type ErrPluginDependencyNotSatisfied struct {
+	PluginID     string
+	DependencyID string
+	Constraint   string
+	Reason       string
+}
+
+func (err ErrPluginDependencyNotSatisfied) Error() string {
+	return fmt.Sprintf("plugin dependency %s %s not satisfied: %s [plugin_id: %s]", err.DependencyID, err.Constraint, err.Reason, err.PluginID)
+}
+
+// IsErrPluginDependencyNotSatisfied 检查是否为插件依赖未满足错误
+func IsErrPluginDependencyNotSatisfied(err error) bool {
+	_, ok := err.(ErrPluginDependencyNotSatisfied)
+	return ok
+}
+
+// ErrPluginHasDependents 插件仍被其他插件依赖错误
+type ErrPluginHasDependents struct {
+	PluginID   string
+	Dependents []string
+}
+
+func (err ErrPluginHasDependents) Error() string {
+	return fmt.Sprintf("plugin is required by %s [plugin_id: %s]", strings.Join(err.Dependents, ", "), err.PluginID)
+}
+
+// IsErrPluginHasDependents 检查是否为插件仍被依赖错误
+func IsErrPluginHasDependents(err error) bool {
+	_, ok := err.(ErrPluginHasDependents)
+	return ok
+}
+
+// ErrPluginInvalidVersion 插件版本号不合法错误
+type ErrPluginInvalidVersion struct {
+	Version string
+}
+
+func (err ErrPluginInvalidVersion) Error() string {
+	return fmt.Sprintf("invalid plugin version [version: %s]", err.Version)
+}
+
+// IsErrPluginInvalidVersion 检查是否为插件版本号不合法错误
+func IsErrPluginInvalidVersion(err error) bool {
+	_, ok := err.(ErrPluginInvalidVersion)
+	return ok
+}
+
+// ErrPluginVersionInstalled 插件已是指定版本错误
+type ErrPluginVersionInstalled struct {
+	PluginID string
+	Version  string
+}
+
+func (err ErrPluginVersionInstalled) Error() string {
+	return fmt.Sprintf("plugin version already installed [plugin_id: %s, version: %s]", err.PluginID, err.Version)
+}
+
+// IsErrPluginVersionInstalled 检查是否为插件已是指定版本错误
+func IsErrPluginVersionInstalled(err error) bool {
+	_, ok := err.(ErrPluginVersionInstalled)
+	return ok
+}
+
+// ErrPluginNoPreviousVersion 插件没有可回滚的版本错误
+type ErrPluginNoPreviousVersion struct {
+	PluginID string
+}
+
+func (err ErrPluginNoPreviousVersion) Error() string {
+	return fmt.Sprintf("plugin has no previous version to roll back to [plugin_id: %s]", err.PluginID)
+}
+
+// IsErrPluginNoPreviousVersion 检查是否为插件没有可回滚版本错误
+func IsErrPluginNoPreviousVersion(err error) bool {
+	_, ok := err.(ErrPluginNoPreviousVersion)
+	return ok
+}
+
+// ErrPluginHotUpgradeUnsupported 插件不能在运行时升级或回滚错误
+type ErrPluginHotUpgradeUnsupported struct {
+	PluginID string
+}
+
+func (err ErrPluginHotUpgradeUnsupported) Error() string {
+	return fmt.Sprintf("native plugins cannot switch versions at runtime, reinstall and restart gitea instead [plugin_id: %s]", err.PluginID)
+}
+
+// IsErrPluginHotUpgradeUnsupported 检查是否为插件不能在运行时升级或回滚错误
+func IsErrPluginHotUpgradeUnsupported(err error) bool {
+	_, ok := err.(ErrPluginHotUpgradeUnsupported)
+	return ok
+}
+
+// ErrPluginSchemaDowngrade 插件版本的迁移落后于数据库版本错误，旧版本代码不能运行在新的表结构上
+type ErrPluginSchemaDowngrade struct {
+	PluginID      string
+	SchemaVersion int64
+	Migrations    int64
+}
+
+func (err ErrPluginSchemaDowngrade) Error() string {
+	return fmt.Sprintf("plugin database version %d is newer than its migrations (%d) [plugin_id: %s]", err.SchemaVersion, err.Migrations, err.PluginID)
+}
+
+// IsErrPluginSchemaDowngrade 检查是否为插件迁移落后于数据库版本错误
+func IsErrPluginSchemaDowngrade(err error) bool {
+	_, ok := err.(ErrPluginSchemaDowngrade)
+	return ok
+}
+
+// ErrPermissionDenied 插件使用了未获批准的能力
+type ErrPermissionDenied struct {
+	PluginID   string
+	Permission string
+}
+
+func (err ErrPermissionDenied) Error() string {
+	return fmt.Sprintf("plugin permission denied [plugin_id: %s, permission: %s]", err.PluginID, err.Permission)
+}
+
+// IsErrPermissionDenied 检查是否为插件权限不足错误
+func IsErrPermissionDenied(err error) bool {
+	_, ok := err.(ErrPermissionDenied)
+	return ok
+}
+
+// ErrPermissionsNotApproved 插件声明的权限尚未经管理员批准
+type ErrPermissionsNotApproved struct {
+	PluginID    string
+	Permissions []string
+}
+
+func (err ErrPermissionsNotApproved) Error() string {
+	return fmt.Sprintf("plugin permissions not approved: %s [plugin_id: %s]", strings.Join(err.Permissions, ", "), err.PluginID)
+}
+
+// IsErrPermissionsNotApproved 检查是否为插件权限未批准错误
+func IsErrPermissionsNotApproved(err error) bool {
+	_, ok := err.(ErrPermissionsNotApproved)
+	return ok
+}
+
+// ErrPluginTablePrefix 插件数据表未使用插件的表名前缀
+type ErrPluginTablePrefix struct {
+	PluginID string
+	Table    string
+}
+
+func (err ErrPluginTablePrefix) Error() string {
+	return fmt.Sprintf("plugin table %s must start with %s [plugin_id: %s]", err.Table, TablePrefix(err.PluginID), err.PluginID)
+}
+
+// IsErrPluginTablePrefix 检查是否为插件表名前缀错误
+func IsErrPluginTablePrefix(err error) bool {
+	_, ok := err.(ErrPluginTablePrefix)
+	return ok
+}
+
+// ErrPluginConfigInvalid 插件配置不符合 config_schema
+type ErrPluginConfigInvalid struct {
+	PluginID string
+	Field    string
+	Reason   string
+}
+
+func (err ErrPluginConfigInvalid) Error() string {
+	if err.Field == "" {
+		return fmt.Sprintf("invalid plugin config: %s [plugin_id: %s]", err.Reason, err.PluginID)
+	}
+	return fmt.Sprintf("invalid plugin config %s: %s [plugin_id: %s]", err.Field, err.Reason, err.PluginID)
+}
+
+// IsErrPluginConfigInvalid 检查是否为插件配置无效错误
+func IsErrPluginConfigInvalid(err error) bool {
+	_, ok := err.(ErrPluginConfigInvalid)
+	return ok
+}
+
+// ErrMarketPluginNotExist 插件市场中没有指定的插件或版本
+type ErrMarketPluginNotExist struct {
+	PluginID string
+	Version  string
+}
+
+func (err ErrMarketPluginNotExist) Error() string {
+	return fmt.Sprintf("plugin not found in marketplace [plugin_id: %s, version: %s]", err.PluginID, err.Version)
+}
+
+// IsErrMarketPluginNotExist 检查是否为插件市场中不存在插件错误
+func IsErrMarketPluginNotExist(err error) bool {
+	_, ok := err.(ErrMarketPluginNotExist)
+	return ok
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// 插件可订阅的事件类型，与 notify.Notifier 的同名方法对应
+const (
+	EventCreateRepository   = "CreateRepository"
+	EventDeleteRepository   = "DeleteRepository"
+	EventNewIssue           = "NewIssue"
+	EventIssueChangeStatus  = "IssueChangeStatus"
+	EventCreateIssueComment = "CreateIssueComment"
+	EventNewPullRequest     = "NewPullRequest"
+	EventMergePullRequest   = "MergePullRequest"
+	EventPushCommits        = "PushCommits"
+	EventCreateRef          = "CreateRef"
+	EventDeleteRef          = "DeleteRef"
+	EventNewRelease         = "NewRelease"
+	EventPackageCreate      = "PackageCreate"
+	EventPackageDelete      = "PackageDelete"
+
+	EventAll = "*" // 订阅全部事件
+)
+
+// KnownEvents 所有可订阅的事件类型
+var KnownEvents = []string{
+	EventCreateRepository,
+	EventDeleteRepository,
+	EventNewIssue,
+	EventIssueChangeStatus,
+	EventCreateIssueComment,
+	EventNewPullRequest,
+	EventMergePullRequest,
+	EventPushCommits,
+	EventCreateRef,
+	EventDeleteRef,
+	EventNewRelease,
+	EventPackageCreate,
+	EventPackageDelete,
+}
+
+// Event 投递给插件的事件，只包含 ID 和少量快照字段，可序列化后跨进程传递
+type Event struct {
+	Type    string             `json:"type"`
+	Created timeutil.TimeStamp `json:"created"`
+
+	DoerID       int64  `json:"doer_id,omitempty"`
+	DoerName     string `json:"doer_name,omitempty"`
+	RepoID       int64  `json:"repo_id,omitempty"`
+	RepoOwner    string `json:"repo_owner,omitempty"`
+	RepoName     string `json:"repo_name,omitempty"`
+	IsPrivate    bool   `json:"is_private,omitempty"`
+	IssueID      int64  `json:"issue_id,omitempty"`
+	IssueIndex   int64  `json:"issue_index,omitempty"`
+	IsPull       bool   `json:"is_pull,omitempty"`
+	IsClosed     bool   `json:"is_closed,omitempty"`
+	Title        string `json:"title,omitempty"`
+	CommentID    int64  `json:"comment_id,omitempty"`
+	PullID       int64  `json:"pull_id,omitempty"`
+	RefName      string `json:"ref_name,omitempty"` // 完整引用名，如 refs/heads/main
+	OldCommitID  string `json:"old_commit_id,omitempty"`
+	NewCommitID  string `json:"new_commit_id,omitempty"`
+	ReleaseID    int64  `json:"release_id,omitempty"`
+	TagName      string `json:"tag_name,omitempty"`
+	PackageVerID int64  `json:"package_version_id,omitempty"`
+	PackageType  string `json:"package_type,omitempty"`
+	PackageName  string `json:"package_name,omitempty"`
+	PackageVer   string `json:"package_version,omitempty"`
+	PackageOwner string `json:"package_owner,omitempty"`
+
+	Commits []*EventCommit `json:"commits,omitempty"`
+}
+
+// EventCommit 推送事件中的提交
+type EventCommit struct {
+	Sha1           string    `json:"sha1"`
+	Message        string    `json:"message"`
+	AuthorEmail    string    `json:"author_email"`
+	AuthorName     string    `json:"author_name"`
+	CommitterEmail string    `json:"committer_email"`
+	CommitterName  string    `json:"committer_name"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// EventSubscriber 订阅事件的插件实现此接口。事件通过队列异步投递，
+// HandleEvent 返回错误或 panic 计入插件的事件失败次数，不会重试
+type EventSubscriber interface {
+	// SubscribedEvents 订阅的事件类型，包含 EventAll 时订阅全部事件
+	SubscribedEvents() []string
+	HandleEvent(ctx context.Context, event *Event) error
+}
+
+// IsSubscribed 检查事件类型是否在订阅列表中
+func IsSubscribed(subscribed []string, eventType string) bool {
+	return slices.Contains(subscribed, EventAll) || slices.Contains(subscribed, eventType)
+}
@@ -0,0 +1,12 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import "context"
+
+// HealthChecker 希望由宿主定期检查运行状态的插件实现此接口。
+// 返回错误计为一次失败，连续失败过多时插件会被自动隔离（禁用）
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
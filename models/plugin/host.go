@@ -0,0 +1,137 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"xorm.io/builder"
+)
+
+// 插件可声明的权限，plugin.json 中的 permissions 字段
+const (
+	PermissionDatabaseRead     = "database.read"     // 读取插件自身的数据表
+	PermissionDatabaseWrite    = "database.write"    // 写入插件自身的数据表
+	PermissionUserRead         = "user.read"         // 查询用户信息
+	PermissionNotificationSend = "notification.send" // 向用户发送通知邮件
+	PermissionSettingRead      = "setting.read"      // 读取实例的基本设置
+	PermissionHTTPEgress       = "http.egress"       // 访问外部网络
+	PermissionAPICreate        = "api.create"        // 注册 API 路由
+	PermissionUIModify         = "ui.modify"         // 注册页面和模板
+	PermissionEventRead        = "event.read"        // 接收仓库、工单、推送和软件包事件
+	PermissionStorage          = "storage.data"      // 使用插件专属的数据目录和对象存储
+	PermissionRepoRead         = "repo.read"         // 查询仓库信息及用户对仓库的访问权限
+)
+
+// KnownPermissions 所有已知权限，按授权页面的展示顺序排列
+var KnownPermissions = []string{
+	PermissionDatabaseRead,
+	PermissionDatabaseWrite,
+	PermissionUserRead,
+	PermissionNotificationSend,
+	PermissionSettingRead,
+	PermissionHTTPEgress,
+	PermissionAPICreate,
+	PermissionUIModify,
+	PermissionEventRead,
+	PermissionStorage,
+	PermissionRepoRead,
+}
+
+// TablePrefix 插件数据表必须使用的表名前缀，如 license-manager 为 plugin_license_manager_
+func TablePrefix(pluginID string) string {
+	return "plugin_" + strings.NewReplacer("-", "_", ".", "_").Replace(pluginID) + "_"
+}
+
+// Host 宿主在 Init 时交给插件的能力接口，每项能力都受管理员批准的权限控制，
+// 未获批准时返回 ErrPermissionDenied
+type Host interface {
+	// PluginID 当前插件 ID
+	PluginID() string
+
+	// DB 只能访问插件自身数据表的数据库会话，需要 database.read 或 database.write
+	DB() DB
+
+	// GetUserByID 根据 ID 查询用户，需要 user.read
+	GetUserByID(ctx context.Context, id int64) (*HostUser, error)
+
+	// GetUserByName 根据用户名查询用户，需要 user.read
+	GetUserByName(ctx context.Context, name string) (*HostUser, error)
+
+	// SendNotification 向用户发送通知邮件，需要 notification.send
+	SendNotification(ctx context.Context, userID int64, subject, body string) error
+
+	// Settings 读取实例的基本设置，需要 setting.read
+	Settings(ctx context.Context) (*HostSettings, error)
+
+	// HTTPClient 用于访问外部网络的 HTTP 客户端，只能访问管理员允许的主机，需要 http.egress。
+	// 插件不应自行建立网络连接
+	HTTPClient(ctx context.Context) (*http.Client, error)
+
+	// DataDir 插件专属的数据目录，不存在时自动创建，卸载插件时删除，需要 storage.data
+	DataDir(ctx context.Context) (string, error)
+
+	// Storage 插件专属的对象存储命名空间，卸载插件时清空，需要 storage.data
+	Storage(ctx context.Context) (Storage, error)
+
+	// GetRepository 查询用户 doerID（0 表示未登录）可以访问的仓库，仓库不存在或无权访问时
+	// 返回的错误满足 errors.Is(err, util.ErrNotExist)，需要 repo.read
+	GetRepository(ctx context.Context, doerID int64, ownerName, repoName string) (*HostRepo, error)
+}
+
+// Storage 插件对象存储，路径相对于插件的命名空间，不能访问其他插件的文件
+type Storage interface {
+	// Save 保存文件，size 未知时传 -1
+	Save(ctx context.Context, path string, r io.Reader, size int64) (int64, error)
+	// Open 打开文件，文件不存在时返回的错误满足 errors.Is(err, os.ErrNotExist)
+	Open(ctx context.Context, path string) (io.ReadCloser, error)
+	Delete(ctx context.Context, path string) error
+	// List 列出目录（含子目录）下的所有文件路径
+	List(ctx context.Context, dir string) ([]string, error)
+}
+
+// DB 限定在插件自身数据表（表名以 TablePrefix 开头）的数据库操作，
+// 读操作需要 database.read，写操作需要 database.write。Update 和 Delete 必须给出非空条件
+type DB interface {
+	Get(ctx context.Context, bean any) (bool, error)
+	Find(ctx context.Context, rowsSlicePtr any, cond builder.Cond) error
+	Count(ctx context.Context, bean any, cond builder.Cond) (int64, error)
+	Insert(ctx context.Context, beans ...any) error
+	Update(ctx context.Context, bean any, cond builder.Cond) (int64, error)
+	Delete(ctx context.Context, bean any, cond builder.Cond) (int64, error)
+}
+
+// HostUser 提供给插件的用户信息
+type HostUser struct {
+	ID       int64
+	Name     string
+	FullName string
+	Email    string
+	IsAdmin  bool
+	IsActive bool
+}
+
+// HostRepo 提供给插件的仓库信息
+type HostRepo struct {
+	ID            int64
+	OwnerName     string
+	Name          string
+	Description   string
+	IsPrivate     bool
+	IsArchived    bool
+	DefaultBranch string
+	HTMLURL       string
+	AccessMode    string // 查询时指定的用户对仓库的访问权限：read、write、admin 或 owner
+}
+
+// HostSettings 提供给插件的实例设置
+type HostSettings struct {
+	AppName   string
+	AppURL    string
+	AppSubURL string
+	Version   string
+}
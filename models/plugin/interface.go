@@ -12,8 +12,8 @@ type IPlugin interface {
 	// Info 获取插件信息
 	Info() *PluginInfo
 
-	// Init 初始化插件
-	Init() error
+	// Init 初始化插件，host 提供受权限控制的宿主能力
+	Init(host Host) error
 
 	// RegisterRoutes 注册 Web 路由
 	RegisterRoutes(r chi.Router)
@@ -51,21 +51,30 @@ type IPlugin interface {
 
 // PluginInfo 插件信息
 type PluginInfo struct {
-	ID             string            `json:"id"`
-	Name           string            `json:"name"`
-	Version        string            `json:"version"`
-	Description    string            `json:"description"`
-	Author         string            `json:"author"`
-	Homepage       string            `json:"homepage"`
-	License        string            `json:"license"`
-	GiteaVersion   string            `json:"gitea_version"`
-	Dependencies   []string          `json:"dependencies"`
-	Permissions    []string          `json:"permissions"`
-	ConfigSchema   map[string]interface{} `json:"config_schema"`
-	HasRoutes      bool              `json:"has_routes"`
-	HasAPI         bool              `json:"has_api"`
-	HasModels      bool              `json:"has_models"`
-	HasTemplates   bool              `json:"has_templates"`
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Version      string                 `json:"version"`
+	Description  string                 `json:"description"`
+	Author       string                 `json:"author"`
+	Homepage     string                 `json:"homepage"`
+	License      string                 `json:"license"`
+	GiteaVersion string                 `json:"gitea_version"`
+	Dependencies []string               `json:"dependencies"`
+	Permissions  []string               `json:"permissions"`
+	ConfigSchema map[string]interface{} `json:"config_schema"`
+	Scopes       []*APIScope            `json:"scopes"` // 访问令牌权限类别，见 APIScope
+	HasRoutes    bool                   `json:"has_routes"`
+	HasAPI       bool                   `json:"has_api"`
+	HasModels    bool                   `json:"has_models"`
+	HasTemplates bool                   `json:"has_templates"`
+}
+
+// APIScope 插件提供的访问令牌权限类别。用户创建访问令牌或 OAuth2 授权时可以选择 read:<name> 和 write:<name>，
+// 使用令牌调用插件 API 时，POST、PUT、PATCH 和 DELETE 请求需要 write 权限，其他请求需要 read 权限
+type APIScope struct {
+	Name        string   `json:"name"`        // 类别名，如 license，不能与 Gitea 内置的类别重名
+	Description string   `json:"description"` // 说明
+	Paths       []string `json:"paths"`       // 适用的 API 路径前缀，{name} 段匹配任意一段路径，为空时适用于插件的所有 API 路由
 }
 
 // PluginMetadata 插件元数据（从 plugin.json 读取）
@@ -80,7 +89,38 @@ type PluginMetadata struct {
 	GiteaVersion string                 `json:"gitea_version"`
 	Dependencies []string               `json:"dependencies"`
 	EntryPoint   string                 `json:"entry_point"`
+	Runtime      string                 `json:"runtime"`    // 运行时：native（默认）或 process
+	Executable   string                 `json:"executable"` // process 运行时的可执行文件（相对插件目录）
+	Transport    string                 `json:"transport"`  // process 运行时的通信方式：stdio（默认）或 unix
 	Hooks        map[string]bool        `json:"hooks"`
 	Permissions  []string               `json:"permissions"`
 	ConfigSchema map[string]interface{} `json:"config_schema"`
 }
+
+// 插件运行时
+const (
+	RuntimeNative  = "native"  // 通过 Go plugin.Open 加载到 Gitea 进程内
+	RuntimeProcess = "process" // 作为受监管的子进程运行，通过 RPC 通信
+)
+
+// process 运行时的通信方式
+const (
+	TransportStdio = "stdio"
+	TransportUnix  = "unix"
+)
+
+// GetRuntime 获取插件运行时，未声明时为 native
+func (m *PluginMetadata) GetRuntime() string {
+	if m.Runtime == "" {
+		return RuntimeNative
+	}
+	return m.Runtime
+}
+
+// GetTransport 获取 process 运行时的通信方式，未声明时为 stdio
+func (m *PluginMetadata) GetTransport() string {
+	if m.Transport == "" {
+		return TransportStdio
+	}
+	return m.Transport
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// MarketIndexVersion 当前支持的插件市场索引格式版本
+const MarketIndexVersion = 1
+
+// MarketIndex 插件市场注册表的索引文件（index.json），格式见 PLUGIN_MARKETPLACE.md
+type MarketIndex struct {
+	Version int             `json:"version"`
+	Plugins []*MarketPlugin `json:"plugins"`
+}
+
+// MarketPlugin 索引中的一个插件
+type MarketPlugin struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Author      string           `json:"author"`
+	Homepage    string           `json:"homepage"`
+	License     string           `json:"license"`
+	Categories  []string         `json:"categories"`
+	Versions    []*MarketVersion `json:"versions"`
+}
+
+// MarketVersion 插件的一个可安装版本
+type MarketVersion struct {
+	Version      string   `json:"version"`
+	URL          string   `json:"url"`           // 插件包地址，相对地址基于索引文件的地址
+	SHA256       string   `json:"sha256"`        // 插件包的 SHA256 校验和，下载后校验
+	GiteaVersion string   `json:"gitea_version"` // 兼容的 Gitea 版本范围，与 plugin.json 中的格式相同
+	Dependencies []string `json:"dependencies"`
+	Permissions  []string `json:"permissions"`
+	ReleasedAt   string   `json:"released_at"` // 发布日期，如 2026-01-30
+}
+
+var validSHA256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Validate 检查索引格式，返回无效条目的说明。无效的插件或版本会从索引中移除，不影响其他条目
+func (idx *MarketIndex) Validate() (problems []string, err error) {
+	if idx.Version != MarketIndexVersion {
+		return nil, fmt.Errorf("unsupported index version %d", idx.Version)
+	}
+
+	idx.Plugins = slices.DeleteFunc(idx.Plugins, func(p *MarketPlugin) bool {
+		if p == nil {
+			return true
+		}
+		if !IsValidPluginID(p.ID) {
+			problems = append(problems, fmt.Sprintf("invalid plugin id %q", p.ID))
+			return true
+		}
+		p.Versions = slices.DeleteFunc(p.Versions, func(v *MarketVersion) bool {
+			switch {
+			case v == nil || !IsValidPluginVersion(v.Version):
+				problems = append(problems, fmt.Sprintf("%s: invalid version", p.ID))
+			case v.URL == "":
+				problems = append(problems, fmt.Sprintf("%s %s: missing url", p.ID, v.Version))
+			case !validSHA256Pattern.MatchString(strings.ToLower(v.SHA256)):
+				problems = append(problems, fmt.Sprintf("%s %s: invalid sha256", p.ID, v.Version))
+			default:
+				v.SHA256 = strings.ToLower(v.SHA256)
+				return false
+			}
+			return true
+		})
+		if len(p.Versions) == 0 {
+			problems = append(problems, fmt.Sprintf("%s: no valid versions", p.ID))
+			return true
+		}
+		return false
+	})
+	return problems, nil
+}
+
+// IsCompatible 检查版本是否兼容指定的 Gitea 版本，Gitea 版本无法比较（如开发版本）时视为兼容
+func (v *MarketVersion) IsCompatible(giteaVersion string) bool {
+	if v.GiteaVersion == "" {
+		return true
+	}
+	if _, err := version.NewVersion(giteaVersion); err != nil {
+		return true
+	}
+	return CheckVersionConstraint(v.GiteaVersion, giteaVersion)
+}
+
+// FindVersion 查找指定版本
+func (p *MarketPlugin) FindVersion(v string) *MarketVersion {
+	for _, mv := range p.Versions {
+		if mv.Version == v {
+			return mv
+		}
+	}
+	return nil
+}
+
+// LatestVersion 兼容指定 Gitea 版本的最新版本，没有兼容版本时返回 nil
+func (p *MarketPlugin) LatestVersion(giteaVersion string) *MarketVersion {
+	var latest *MarketVersion
+	for _, mv := range p.Versions {
+		if !mv.IsCompatible(giteaVersion) {
+			continue
+		}
+		if latest == nil || CompareVersions(mv.Version, latest.Version) > 0 {
+			latest = mv
+		}
+	}
+	return latest
+}
+
+// HasCategory 插件是否属于指定分类（不区分大小写）
+func (p *MarketPlugin) HasCategory(category string) bool {
+	return slices.ContainsFunc(p.Categories, func(c string) bool {
+		return strings.EqualFold(c, category)
+	})
+}
+
+// CompareVersions 比较两个版本号，无法解析的版本按字符串比较
+func CompareVersions(a, b string) int {
+	va, errA := version.NewVersion(a)
+	vb, errB := version.NewVersion(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return va.Compare(vb)
+}
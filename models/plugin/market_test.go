@@ -0,0 +1,58 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketIndexValidate(t *testing.T) {
+	sum := strings.Repeat("AB", 32)
+	idx := &MarketIndex{
+		Version: MarketIndexVersion,
+		Plugins: []*MarketPlugin{
+			{ID: "demo", Versions: []*MarketVersion{
+				{Version: "1.0.0", URL: "demo-1.0.0.tar.gz", SHA256: sum},
+				{Version: "1.1.0", URL: "", SHA256: sum},
+				{Version: "1.2.0", URL: "demo-1.2.0.tar.gz", SHA256: "abc"},
+			}},
+			{ID: "../evil", Versions: []*MarketVersion{{Version: "1.0.0", URL: "x", SHA256: sum}}},
+			{ID: "empty"},
+			nil,
+		},
+	}
+	problems, err := idx.Validate()
+	require.NoError(t, err)
+	assert.Len(t, problems, 4)
+	require.Len(t, idx.Plugins, 1)
+	require.Len(t, idx.Plugins[0].Versions, 1)
+	assert.Equal(t, strings.ToLower(sum), idx.Plugins[0].Versions[0].SHA256)
+
+	_, err = (&MarketIndex{Version: 2}).Validate()
+	assert.Error(t, err)
+}
+
+func TestMarketPluginLatestVersion(t *testing.T) {
+	p := &MarketPlugin{Versions: []*MarketVersion{
+		{Version: "1.0.0"},
+		{Version: "1.10.0", GiteaVersion: ">=1.25.0"},
+		{Version: "1.2.0", GiteaVersion: ">=1.24.0"},
+	}}
+	assert.Equal(t, "1.10.0", p.LatestVersion("1.25.1").Version)
+	assert.Equal(t, "1.2.0", p.LatestVersion("1.24.0").Version)
+	assert.Equal(t, "1.10.0", p.LatestVersion("main-dev").Version, "development versions are treated as compatible")
+
+	p.Versions = p.Versions[1:]
+	assert.Nil(t, p.LatestVersion("1.23.0"))
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Positive(t, CompareVersions("1.10.0", "1.9.0"))
+	assert.Negative(t, CompareVersions("1.0.0-rc.1", "1.0.0"))
+	assert.Zero(t, CompareVersions("1.0", "1.0.0"))
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+// Migration 插件的一次数据库迁移，与 models/migrations 一样只能向上迁移。
+// ID 从 1 开始连续递增，已发布的迁移不能修改或删除
+type Migration struct {
+	ID          int64
+	Description string
+	Migrate     func(ctx context.Context, x *xorm.Engine) error
+}
+
+// NewMigration 创建插件数据库迁移
+func NewMigration(id int64, description string, fn func(ctx context.Context, x *xorm.Engine) error) *Migration {
+	return &Migration{ID: id, Description: description, Migrate: fn}
+}
+
+// Migrator 需要管理数据表的插件实现此接口，由 Gitea 在 Init 成功之后按顺序执行尚未执行的迁移。
+// 迁移创建的表名必须以 TablePrefix(插件 ID) 开头
+type Migrator interface {
+	Migrations() []*Migration
+}
+
+// SchemaVersion 插件数据库版本，每个插件一行，记录最后执行的迁移 ID
+type SchemaVersion struct {
+	ID          int64              `xorm:"pk autoincr"`
+	PluginID    string             `xorm:"VARCHAR(100) UNIQUE NOT NULL"`
+	Version     int64              `xorm:"NOT NULL DEFAULT 0"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(SchemaVersion))
+}
+
+// TableName 表名
+func (v *SchemaVersion) TableName() string {
+	return "plugin_schema_version"
+}
+
+// GetSchemaVersion 获取插件的数据库版本，从未执行过迁移时为 0
+func GetSchemaVersion(ctx context.Context, pluginID string) (int64, error) {
+	v := &SchemaVersion{}
+	has, err := db.GetEngine(ctx).Where("plugin_id = ?", pluginID).Get(v)
+	if err != nil || !has {
+		return 0, err
+	}
+	return v.Version, nil
+}
+
+// SetSchemaVersion 保存插件的数据库版本
+func SetSchemaVersion(ctx context.Context, pluginID string, version int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		v := &SchemaVersion{}
+		has, err := db.GetEngine(ctx).Where("plugin_id = ?", pluginID).Get(v)
+		if err != nil {
+			return err
+		}
+		v.Version = version
+		if has {
+			_, err = db.GetEngine(ctx).ID(v.ID).Cols("version").Update(v)
+			return err
+		}
+		v.PluginID = pluginID
+		_, err = db.GetEngine(ctx).Insert(v)
+		return err
+	})
+}
+
+// DeleteSchemaVersion 删除插件的数据库版本记录
+func DeleteSchemaVersion(ctx context.Context, pluginID string) error {
+	_, err := db.GetEngine(ctx).Where("plugin_id = ?", pluginID).Delete(&SchemaVersion{})
+	return err
+}
+
+// OwnsTable 检查表是否属于插件：表名以插件的前缀开头，不是 Gitea 自身的表，
+// 且不属于前缀更长的其他插件（如 plugin_a_b_ 属于 a-b 而不是 a）
+func OwnsTable(pluginID, tableName string, pluginIDs []string) bool {
+	prefix := TablePrefix(pluginID)
+	if !strings.HasPrefix(tableName, prefix) || db.IsRegisteredTable(tableName) {
+		return false
+	}
+	return !slices.ContainsFunc(pluginIDs, func(other string) bool {
+		otherPrefix := TablePrefix(other)
+		return len(otherPrefix) > len(prefix) && strings.HasPrefix(tableName, otherPrefix)
+	})
+}
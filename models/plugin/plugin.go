@@ -5,6 +5,10 @@ package plugin
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -12,26 +16,78 @@ import (
 
 // Plugin 插件数据库模型
 type Plugin struct {
-	ID          int64              `xorm:"pk autoincr"`
-	PluginID    string             `xorm:"VARCHAR(100) UNIQUE NOT NULL INDEX"`
-	Name        string             `xorm:"VARCHAR(200) NOT NULL"`
-	Version     string             `xorm:"VARCHAR(50) NOT NULL"`
-	Description string             `xorm:"TEXT"`
-	Author      string             `xorm:"VARCHAR(200)"`
-	Homepage    string             `xorm:"VARCHAR(500)"`
-	License     string             `xorm:"VARCHAR(50)"`
-	IsEnabled   bool               `xorm:"NOT NULL DEFAULT false"`
-	IsInstalled bool               `xorm:"NOT NULL DEFAULT false"`
-	InstallPath string             `xorm:"VARCHAR(500)"`
-	Config      string             `xorm:"TEXT"` // JSON 格式的配置
-	CreatedUnix timeutil.TimeStamp `xorm:"created"`
-	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	ID              int64              `xorm:"pk autoincr"`
+	PluginID        string             `xorm:"VARCHAR(100) UNIQUE NOT NULL INDEX"`
+	Name            string             `xorm:"VARCHAR(200) NOT NULL"`
+	Version         string             `xorm:"VARCHAR(50) NOT NULL"`
+	Description     string             `xorm:"TEXT"`
+	Author          string             `xorm:"VARCHAR(200)"`
+	Homepage        string             `xorm:"VARCHAR(500)"`
+	License         string             `xorm:"VARCHAR(50)"`
+	IsEnabled       bool               `xorm:"NOT NULL DEFAULT false"`
+	IsInstalled     bool               `xorm:"NOT NULL DEFAULT false"`
+	InstallPath     string             `xorm:"VARCHAR(500)"`
+	PreviousVersion string             `xorm:"VARCHAR(50)"`  // 升级前的版本，保留以便回滚
+	PreviousPath    string             `xorm:"VARCHAR(500)"` // 升级前版本的安装目录
+	Source          string             `xorm:"VARCHAR(255)"` // 安装来源，见 Source* 常量
+	Signer          string             `xorm:"VARCHAR(255)"` // 签名发布者密钥指纹，未签名时为空
+	Config          string             `xorm:"TEXT"`         // JSON 格式的配置
+	Permissions     []string           `xorm:"JSON TEXT"`    // 管理员批准的权限
+	CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
 }
 
 func init() {
 	db.RegisterModel(new(Plugin))
 }
 
+// 插件安装来源
+const (
+	SourceMarketplace = "marketplace"
+	SourceUpload      = "upload"
+	SourceRelease     = "release" // 完整格式为 release:{owner}/{repo}@{tag}
+)
+
+// ReleaseSource 生成仓库发布版本安装来源
+func ReleaseSource(owner, repo, tag string) string {
+	return fmt.Sprintf("%s:%s/%s@%s", SourceRelease, owner, repo, tag)
+}
+
+var validPluginIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,99}$`)
+
+// IsValidPluginID 检查插件 ID 是否合法（同时用作安装目录名）
+func IsValidPluginID(pluginID string) bool {
+	return validPluginIDPattern.MatchString(pluginID) && !strings.Contains(pluginID, "..")
+}
+
+var validPluginVersionPattern = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z.+_-]{0,49}$`)
+
+// IsValidPluginVersion 检查插件版本号是否合法（同时用作版本目录名）
+func IsValidPluginVersion(version string) bool {
+	return validPluginVersionPattern.MatchString(version) && !strings.Contains(version, "..")
+}
+
+// CanRollback 是否保留了可回滚的上一个版本
+func (p *Plugin) CanRollback() bool {
+	return p.PreviousVersion != "" && p.PreviousPath != ""
+}
+
+// HasPermission 检查管理员是否批准了指定权限
+func (p *Plugin) HasPermission(permission string) bool {
+	return slices.Contains(p.Permissions, permission)
+}
+
+// UnapprovedPermissions 返回声明了但尚未批准的权限
+func (p *Plugin) UnapprovedPermissions(declared []string) []string {
+	var result []string
+	for _, permission := range declared {
+		if !p.HasPermission(permission) && !slices.Contains(result, permission) {
+			result = append(result, permission)
+		}
+	}
+	return result
+}
+
 // TableName 表名
 func (p *Plugin) TableName() string {
 	return "plugin"
@@ -0,0 +1,48 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidPluginID(t *testing.T) {
+	for _, id := range []string{"license-manager", "a", "my.plugin_1"} {
+		assert.True(t, IsValidPluginID(id), id)
+	}
+	for _, id := range []string{"", "..", "a..b", "../etc", "a/b", "-plugin", "Plugin", "a b"} {
+		assert.False(t, IsValidPluginID(id), id)
+	}
+}
+
+func TestIsValidPluginVersion(t *testing.T) {
+	for _, v := range []string{"1.0.0", "2.1.0-rc.1", "1.0.0+build.5", "latest"} {
+		assert.True(t, IsValidPluginVersion(v), v)
+	}
+	for _, v := range []string{"", "..", "1.0/../..", ".1", "1 0"} {
+		assert.False(t, IsValidPluginVersion(v), v)
+	}
+}
+
+func TestTablePrefix(t *testing.T) {
+	assert.Equal(t, "plugin_license_manager_", TablePrefix("license-manager"))
+	assert.Equal(t, "plugin_my_plugin_1_", TablePrefix("my.plugin_1"))
+}
+
+func TestUnapprovedPermissions(t *testing.T) {
+	p := &Plugin{Permissions: []string{PermissionDatabaseRead, PermissionUserRead}}
+	assert.True(t, p.HasPermission(PermissionUserRead))
+	assert.False(t, p.HasPermission(PermissionHTTPEgress))
+	assert.Empty(t, p.UnapprovedPermissions([]string{PermissionDatabaseRead}))
+	assert.Equal(t, []string{PermissionHTTPEgress}, p.UnapprovedPermissions([]string{PermissionUserRead, PermissionHTTPEgress, PermissionHTTPEgress}))
+}
+
+func TestIsSubscribed(t *testing.T) {
+	assert.True(t, IsSubscribed([]string{EventPushCommits, EventNewIssue}, EventNewIssue))
+	assert.False(t, IsSubscribed([]string{EventPushCommits}, EventNewIssue))
+	assert.True(t, IsSubscribed([]string{EventAll}, EventPackageDelete))
+	assert.False(t, IsSubscribed(nil, EventPushCommits))
+}
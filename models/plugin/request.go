@@ -0,0 +1,62 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// RequestInfo 宿主随插件路由的请求一并传递的信息，process 插件同样可以取得
+type RequestInfo struct {
+	Doer *HostUser // 当前登录的用户，未登录时为 nil
+	Lang string    // 当前界面语言，如 zh-CN
+}
+
+// HTMLRenderer 使用 Gitea 的模板引擎和页面布局渲染插件模板，name 为插件模板目录中的模板名，不含 plugins/<id>/ 前缀
+type HTMLRenderer func(w http.ResponseWriter, status int, name string, data map[string]any)
+
+// TemplateHeader process 插件请求宿主渲染模板时设置的响应头，值为模板名，响应体为 JSON 格式的模板数据
+const TemplateHeader = "X-Gitea-Plugin-Template"
+
+type (
+	requestInfoKey  struct{}
+	htmlRendererKey struct{}
+)
+
+// WithRequestInfo 在请求上下文中附加请求信息
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// GetRequestInfo 获取请求信息，不是插件路由的请求时返回 nil
+func GetRequestInfo(ctx context.Context) *RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
+
+// WithHTMLRenderer 在请求上下文中附加模板渲染函数，只有 Web 路由的请求可以渲染模板
+func WithHTMLRenderer(ctx context.Context, render HTMLRenderer) context.Context {
+	return context.WithValue(ctx, htmlRendererKey{}, render)
+}
+
+// GetHTMLRenderer 获取模板渲染函数，API 路由的请求返回 nil
+func GetHTMLRenderer(ctx context.Context) HTMLRenderer {
+	render, _ := ctx.Value(htmlRendererKey{}).(HTMLRenderer)
+	return render
+}
+
+// WriteTemplateResponse 将模板名和数据写入响应，由宿主渲染，用于 process 插件和测试
+func WriteTemplateResponse(w http.ResponseWriter, status int, name string, data map[string]any) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(TemplateHeader, name)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"html/template"
+	"slices"
+)
+
+// 插件可以注入内容的页面位置（UI 插槽）
+const (
+	SlotRepoHeaderTab    = "repo.header.tab"    // 仓库头部的标签页，渲染 <a class="item"> 元素
+	SlotUserSettingsMenu = "user.settings.menu" // 用户设置的左侧菜单，渲染 <a class="item"> 元素
+	SlotIssueSidebar     = "issue.sidebar"      // 工单/合并请求页面的右侧边栏
+	SlotAdminDashboard   = "admin.dashboard"    // 管理后台首页的卡片
+	SlotPullMergeBox     = "pull.merge_box"     // 合并请求合并框中的检查项，渲染 <div class="item"> 元素
+)
+
+// KnownSlots 所有可用的插槽
+var KnownSlots = []string{
+	SlotRepoHeaderTab,
+	SlotUserSettingsMenu,
+	SlotIssueSidebar,
+	SlotAdminDashboard,
+	SlotPullMergeBox,
+}
+
+// IsValidSlot 检查插槽名称是否有效
+func IsValidSlot(slot string) bool {
+	return slices.Contains(KnownSlots, slot)
+}
+
+// SlotContext 渲染插槽时的页面上下文，页面没有对应对象时为 nil
+type SlotContext struct {
+	Slot   string
+	Link   string // 当前页面的链接
+	Locale string // 当前用户的语言，如 zh-CN
+	Doer   *HostUser
+	Repo   *SlotRepo
+	Issue  *SlotIssue
+}
+
+// SlotRepo 插槽上下文中的仓库
+type SlotRepo struct {
+	ID        int64
+	OwnerName string
+	Name      string
+	IsPrivate bool
+	Link      string
+}
+
+// SlotIssue 插槽上下文中的工单或合并请求
+type SlotIssue struct {
+	ID        int64
+	Index     int64
+	Title     string
+	IsPull    bool
+	IsClosed  bool
+	HasMerged bool // 仅合并请求
+	Link      string
+}
+
+// SlotRenderer 渲染插槽内容，返回空内容表示不显示
+type SlotRenderer func(ctx context.Context, sc *SlotContext) (template.HTML, error)
+
+// SlotRegistry 插件在 RegisterSlots 中通过它注册插槽
+type SlotRegistry interface {
+	RegisterSlot(slot string, render SlotRenderer)
+}
+
+// SlotProvider 需要向已有页面注入内容的插件实现此接口，插件启用后生效
+type SlotProvider interface {
+	RegisterSlots(r SlotRegistry)
+}
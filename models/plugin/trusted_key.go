@@ -0,0 +1,106 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"strings"
+
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// 受信任发布者密钥类型
+const (
+	TrustedKeyTypeSSH = "ssh" // SSH 公钥（推荐 ssh-ed25519），签名由 ssh-keygen -Y sign 生成
+	TrustedKeyTypeGPG = "gpg" // ASCII armor 格式的 GPG 公钥，签名由 gpg --detach-sign 生成
+)
+
+// TrustedKey 受信任的插件发布者密钥
+type TrustedKey struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Name        string             `xorm:"VARCHAR(200) NOT NULL"` // 发布者名称
+	KeyType     string             `xorm:"VARCHAR(10) NOT NULL"`
+	Content     string             `xorm:"TEXT NOT NULL"`
+	Fingerprint string             `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(TrustedKey))
+}
+
+// TableName 表名
+func (k *TrustedKey) TableName() string {
+	return "plugin_trusted_key"
+}
+
+// ParseTrustedKey 校验公钥内容并计算指纹，复用 asymkey 中 SSH/GPG 密钥的解析逻辑
+func ParseTrustedKey(keyType, content string) (string, string, error) {
+	switch keyType {
+	case TrustedKeyTypeSSH:
+		content, err := asymkey_model.CheckPublicKeyString(content)
+		if err != nil {
+			return "", "", ErrTrustedKeyInvalid{Reason: err.Error()}
+		}
+		fingerprint, err := asymkey_model.CalcFingerprint(content)
+		if err != nil {
+			return "", "", ErrTrustedKeyInvalid{Reason: err.Error()}
+		}
+		return content, fingerprint, nil
+	case TrustedKeyTypeGPG:
+		content = strings.TrimSpace(content)
+		entities, err := asymkey_model.CheckArmoredGPGKeyString(content)
+		if err != nil {
+			return "", "", ErrTrustedKeyInvalid{Reason: err.Error()}
+		}
+		if len(entities) != 1 {
+			return "", "", ErrTrustedKeyInvalid{Reason: "exactly one GPG public key is required"}
+		}
+		return content, entities[0].PrimaryKey.KeyIdString(), nil
+	default:
+		return "", "", ErrTrustedKeyInvalid{Reason: "unknown key type " + keyType}
+	}
+}
+
+// AddTrustedKey 添加受信任的发布者密钥
+func AddTrustedKey(ctx context.Context, name, keyType, content string) (*TrustedKey, error) {
+	content, fingerprint, err := ParseTrustedKey(keyType, content)
+	if err != nil {
+		return nil, err
+	}
+
+	has, err := db.GetEngine(ctx).Exist(&TrustedKey{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		return nil, ErrTrustedKeyAlreadyExist{Fingerprint: fingerprint}
+	}
+
+	key := &TrustedKey{
+		Name:        name,
+		KeyType:     keyType,
+		Content:     content,
+		Fingerprint: fingerprint,
+	}
+	if _, err := db.GetEngine(ctx).Insert(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ListTrustedKeys 列出所有受信任的发布者密钥
+func ListTrustedKeys(ctx context.Context) ([]*TrustedKey, error) {
+	keys := make([]*TrustedKey, 0)
+	err := db.GetEngine(ctx).OrderBy("id").Find(&keys)
+	return keys, err
+}
+
+// DeleteTrustedKey 删除受信任的发布者密钥
+func DeleteTrustedKey(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(&TrustedKey{})
+	return err
+}
@@ -24,6 +24,8 @@ const (
 	NoticeRepository NoticeType = iota + 1
 	// NoticeTask type
 	NoticeTask
+	// NoticePlugin type
+	NoticePlugin
 )
 
 // Notice represents a system notice for admin.
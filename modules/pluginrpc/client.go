@@ -0,0 +1,169 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pluginrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+)
+
+// ErrMessageTooLarge 插件返回的单条消息超过大小限制，连接随之关闭
+var ErrMessageTooLarge = errors.New("plugin message too large")
+
+// Client Gitea 端的 RPC 客户端
+type Client struct {
+	rpc *rpc.Client
+}
+
+// NewClient 在指定连接上创建客户端，插件返回的单条消息超过 maxMessageSize 字节时连接失效，
+// 所有进行中的调用返回 ErrMessageTooLarge。maxMessageSize 为 0 时不限制
+func NewClient(conn io.ReadWriteCloser, maxMessageSize int64) *Client {
+	if maxMessageSize > 0 {
+		conn = &limitedConn{ReadWriteCloser: conn, max: maxMessageSize}
+	}
+	return &Client{rpc: rpc.NewClientWithCodec(jsonrpc.NewClientCodec(conn))}
+}
+
+// limitedConn 限制读取的单条消息的大小。JSON-RPC 的每条消息以换行结束（消息内的换行会被转义），
+// 因此按换行分隔计算消息大小，不必等整条消息解码后才发现过大
+type limitedConn struct {
+	io.ReadWriteCloser
+	max  int64
+	size int64 // 当前消息已读取的字节数
+}
+
+func (c *limitedConn) Read(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Read(p)
+	for b := p[:n]; len(b) > 0; {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			c.size += int64(len(b))
+			break
+		}
+		if c.size+int64(i) > c.max {
+			return 0, ErrMessageTooLarge
+		}
+		c.size = 0
+		b = b[i+1:]
+	}
+	if c.size > c.max {
+		return 0, ErrMessageTooLarge
+	}
+	return n, err
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	return c.rpc.Close()
+}
+
+func (c *Client) call(method string, args, reply any) error {
+	return c.rpc.Call(ServiceName+"."+method, args, reply)
+}
+
+// callContext 与 call 相同，ctx 结束时不再等待插件的响应并返回 ctx.Err()
+func (c *Client) callContext(ctx context.Context, method string, args, reply any) error {
+	call := c.rpc.Go(ServiceName+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Handshake 协商协议版本
+func (c *Client) Handshake() (*HandshakeReply, error) {
+	var reply HandshakeReply
+	if err := c.call("Handshake", HandshakeArgs{ProtocolVersion: ProtocolVersion}, &reply); err != nil {
+		return nil, fmt.Errorf("handshake: %w", err)
+	}
+	if reply.ProtocolVersion != ProtocolVersion {
+		return nil, fmt.Errorf("protocol version mismatch: host %d, plugin %d", ProtocolVersion, reply.ProtocolVersion)
+	}
+	return &reply, nil
+}
+
+// Info 获取插件信息
+func (c *Client) Info() (*plugin_model.PluginInfo, error) {
+	var reply plugin_model.PluginInfo
+	if err := c.call("Info", Empty{}, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// Init 初始化插件
+func (c *Client) Init() error {
+	return c.call("Init", Empty{}, &Empty{})
+}
+
+// Enable 启用插件
+func (c *Client) Enable() error {
+	return c.call("Enable", Empty{}, &Empty{})
+}
+
+// Disable 禁用插件
+func (c *Client) Disable() error {
+	return c.call("Disable", Empty{}, &Empty{})
+}
+
+// Uninstall 卸载插件
+func (c *Client) Uninstall() error {
+	return c.call("Uninstall", Empty{}, &Empty{})
+}
+
+// GetConfig 获取配置
+func (c *Client) GetConfig() (map[string]any, error) {
+	var reply ConfigReply
+	if err := c.call("GetConfig", Empty{}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Config, nil
+}
+
+// SetConfig 设置配置
+func (c *Client) SetConfig(config map[string]any) error {
+	return c.call("SetConfig", ConfigArgs{Config: config}, &Empty{})
+}
+
+// HandleEvent 将事件投递给插件
+func (c *Client) HandleEvent(event *plugin_model.Event) error {
+	return c.call("HandleEvent", event, &Empty{})
+}
+
+// HealthCheck 检查插件运行状态
+func (c *Client) HealthCheck() error {
+	return c.call("HealthCheck", Empty{}, &Empty{})
+}
+
+// RunCronTask 请求插件执行定时任务
+func (c *Client) RunCronTask(name string) error {
+	return c.call("RunCronTask", CronTaskArgs{Name: name}, &Empty{})
+}
+
+// RenderSlot 请求插件渲染插槽
+func (c *Client) RenderSlot(sc *plugin_model.SlotContext) (string, error) {
+	var reply SlotReply
+	if err := c.call("RenderSlot", sc, &reply); err != nil {
+		return "", err
+	}
+	return reply.HTML, nil
+}
+
+// ServeHTTP 将 HTTP 请求转发给插件，ctx 结束时不再等待插件的响应
+func (c *Client) ServeHTTP(ctx context.Context, req *HTTPRequest) (*HTTPResponse, error) {
+	var reply HTTPResponse
+	if err := c.callContext(ctx, "ServeHTTP", req, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
@@ -0,0 +1,368 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pluginrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrDBUnavailable process 插件无法与 Gitea 共享 Go 类型，不能使用宿主数据库
+var ErrDBUnavailable = errors.New("host database is not available to process plugins")
+
+// ErrHostUnavailable 插件进程未连接到宿主能力服务（如脱离 Gitea 单独运行）
+var ErrHostUnavailable = errors.New("host services are not available")
+
+// ErrHTTPResponseTooLarge HTTPDo 的响应体超过了宿主允许的大小
+var ErrHTTPResponseTooLarge = errors.New("http response body is too large")
+
+// ServeHost 在 Gitea 端为插件进程提供宿主能力，直到 listener 关闭。
+// HTTPDo 的响应体整体经 RPC 返回，超过 maxResponseSize 字节时返回 ErrHTTPResponseTooLarge
+func ServeHost(ctx context.Context, l net.Listener, h plugin_model.Host, maxResponseSize int64) error {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName(HostServiceName, &hostService{ctx: ctx, impl: h, maxResponseSize: maxResponseSize}); err != nil {
+		return err
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// hostService Gitea 端宿主能力 RPC 服务，将调用转发给 plugin_model.Host 实现
+type hostService struct {
+	ctx             context.Context
+	impl            plugin_model.Host
+	maxResponseSize int64
+}
+
+// GetUserByID 根据 ID 查询用户
+func (s *hostService) GetUserByID(args UserArgs, reply *plugin_model.HostUser) error {
+	u, err := s.impl.GetUserByID(s.ctx, args.ID)
+	if err != nil {
+		return err
+	}
+	*reply = *u
+	return nil
+}
+
+// GetUserByName 根据用户名查询用户
+func (s *hostService) GetUserByName(args UserArgs, reply *plugin_model.HostUser) error {
+	u, err := s.impl.GetUserByName(s.ctx, args.Name)
+	if err != nil {
+		return err
+	}
+	*reply = *u
+	return nil
+}
+
+// SendNotification 向用户发送通知
+func (s *hostService) SendNotification(args NotificationArgs, _ *Empty) error {
+	return s.impl.SendNotification(s.ctx, args.UserID, args.Subject, args.Body)
+}
+
+// Settings 读取实例设置
+func (s *hostService) Settings(_ Empty, reply *plugin_model.HostSettings) error {
+	settings, err := s.impl.Settings(s.ctx)
+	if err != nil {
+		return err
+	}
+	*reply = *settings
+	return nil
+}
+
+// HTTPDo 使用宿主的 HTTP 客户端访问外部网络
+func (s *hostService) HTTPDo(args HTTPRequest, reply *HTTPResponse) error {
+	client, err := s.impl.HTTPClient(s.ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(s.ctx, args.Method, args.URL, bytes.NewReader(args.Body))
+	if err != nil {
+		return err
+	}
+	req.Header = args.Header
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, s.maxResponseSize+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(body)) > s.maxResponseSize {
+		return ErrHTTPResponseTooLarge
+	}
+	reply.StatusCode = resp.StatusCode
+	reply.Header = resp.Header
+	reply.Body = body
+	return nil
+}
+
+// DataDir 获取插件数据目录
+func (s *hostService) DataDir(_ Empty, reply *DataDirReply) (err error) {
+	reply.Dir, err = s.impl.DataDir(s.ctx)
+	return err
+}
+
+// StorageSave 保存文件到插件的对象存储
+func (s *hostService) StorageSave(args StorageArgs, reply *StorageReply) (err error) {
+	st, err := s.impl.Storage(s.ctx)
+	if err != nil {
+		return err
+	}
+	reply.Size, err = st.Save(s.ctx, args.Path, bytes.NewReader(args.Data), int64(len(args.Data)))
+	return err
+}
+
+// StorageOpen 读取插件对象存储中的文件
+func (s *hostService) StorageOpen(args StorageArgs, reply *StorageReply) error {
+	st, err := s.impl.Storage(s.ctx)
+	if err != nil {
+		return err
+	}
+	f, err := st.Open(s.ctx, args.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		reply.NotExist = true
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+	reply.Data, err = io.ReadAll(f)
+	return err
+}
+
+// StorageDelete 删除插件对象存储中的文件
+func (s *hostService) StorageDelete(args StorageArgs, _ *Empty) error {
+	st, err := s.impl.Storage(s.ctx)
+	if err != nil {
+		return err
+	}
+	return st.Delete(s.ctx, args.Path)
+}
+
+// StorageList 列出插件对象存储中的文件
+func (s *hostService) StorageList(args StorageArgs, reply *StorageReply) error {
+	st, err := s.impl.Storage(s.ctx)
+	if err != nil {
+		return err
+	}
+	reply.Paths, err = st.List(s.ctx, args.Path)
+	return err
+}
+
+// GetRepository 查询用户可以访问的仓库
+func (s *hostService) GetRepository(args RepoArgs, reply *RepoReply) error {
+	repo, err := s.impl.GetRepository(s.ctx, args.DoerID, args.OwnerName, args.RepoName)
+	if errors.Is(err, util.ErrNotExist) {
+		reply.NotExist = true
+		return nil
+	} else if err != nil {
+		return err
+	}
+	reply.Repo = repo
+	return nil
+}
+
+// DialHost 在插件进程中连接 Gitea 的宿主能力服务
+func DialHost(socket, pluginID string) (plugin_model.Host, error) {
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteHost{pluginID: pluginID, rpc: rpc.NewClientWithCodec(jsonrpc.NewClientCodec(conn))}, nil
+}
+
+// remoteHost 插件进程中的宿主能力代理，权限检查在 Gitea 端进行
+type remoteHost struct {
+	pluginID string
+	rpc      *rpc.Client
+}
+
+func (h *remoteHost) call(method string, args, reply any) error {
+	if h.rpc == nil {
+		return ErrHostUnavailable
+	}
+	return h.rpc.Call(HostServiceName+"."+method, args, reply)
+}
+
+// PluginID 当前插件 ID
+func (h *remoteHost) PluginID() string {
+	return h.pluginID
+}
+
+// DB process 插件不支持宿主数据库，所有操作返回 ErrDBUnavailable
+func (h *remoteHost) DB() plugin_model.DB {
+	return unavailableDB{}
+}
+
+// GetUserByID 根据 ID 查询用户
+func (h *remoteHost) GetUserByID(_ context.Context, id int64) (*plugin_model.HostUser, error) {
+	var reply plugin_model.HostUser
+	if err := h.call("GetUserByID", UserArgs{ID: id}, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// GetUserByName 根据用户名查询用户
+func (h *remoteHost) GetUserByName(_ context.Context, name string) (*plugin_model.HostUser, error) {
+	var reply plugin_model.HostUser
+	if err := h.call("GetUserByName", UserArgs{Name: name}, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// SendNotification 向用户发送通知
+func (h *remoteHost) SendNotification(_ context.Context, userID int64, subject, body string) error {
+	return h.call("SendNotification", NotificationArgs{UserID: userID, Subject: subject, Body: body}, &Empty{})
+}
+
+// Settings 读取实例设置
+func (h *remoteHost) Settings(_ context.Context) (*plugin_model.HostSettings, error) {
+	var reply plugin_model.HostSettings
+	if err := h.call("Settings", Empty{}, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// HTTPClient 请求经由 Gitea 发出，受宿主的权限和出站限制约束
+func (h *remoteHost) HTTPClient(_ context.Context) (*http.Client, error) {
+	return &http.Client{Transport: hostRoundTripper{h}}, nil
+}
+
+// DataDir 插件数据目录，插件进程的 HOME 同样指向此目录
+func (h *remoteHost) DataDir(_ context.Context) (string, error) {
+	var reply DataDirReply
+	if err := h.call("DataDir", Empty{}, &reply); err != nil {
+		return "", err
+	}
+	return reply.Dir, nil
+}
+
+// Storage 文件内容经由 RPC 整体传输，不适合保存过大的文件
+func (h *remoteHost) Storage(_ context.Context) (plugin_model.Storage, error) {
+	return remoteStorage{h}, nil
+}
+
+// GetRepository 查询用户可以访问的仓库
+func (h *remoteHost) GetRepository(_ context.Context, doerID int64, ownerName, repoName string) (*plugin_model.HostRepo, error) {
+	var reply RepoReply
+	if err := h.call("GetRepository", RepoArgs{DoerID: doerID, OwnerName: ownerName, RepoName: repoName}, &reply); err != nil {
+		return nil, err
+	}
+	if reply.NotExist {
+		return nil, util.NewNotExistErrorf("repository %s/%s does not exist", ownerName, repoName)
+	}
+	return reply.Repo, nil
+}
+
+type remoteStorage struct {
+	host *remoteHost
+}
+
+func (s remoteStorage) Save(_ context.Context, path string, r io.Reader, _ int64) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	var reply StorageReply
+	if err := s.host.call("StorageSave", StorageArgs{Path: path, Data: data}, &reply); err != nil {
+		return 0, err
+	}
+	return reply.Size, nil
+}
+
+func (s remoteStorage) Open(_ context.Context, path string) (io.ReadCloser, error) {
+	var reply StorageReply
+	if err := s.host.call("StorageOpen", StorageArgs{Path: path}, &reply); err != nil {
+		return nil, err
+	}
+	if reply.NotExist {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(reply.Data)), nil
+}
+
+func (s remoteStorage) Delete(_ context.Context, path string) error {
+	return s.host.call("StorageDelete", StorageArgs{Path: path}, &Empty{})
+}
+
+func (s remoteStorage) List(_ context.Context, dir string) ([]string, error) {
+	var reply StorageReply
+	if err := s.host.call("StorageList", StorageArgs{Path: dir}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Paths, nil
+}
+
+type hostRoundTripper struct {
+	host *remoteHost
+}
+
+func (t hostRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+
+	var reply HTTPResponse
+	if err := t.host.call("HTTPDo", HTTPRequest{Method: req.Method, URL: req.URL.String(), Header: req.Header, Body: body}, &reply); err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode:    reply.StatusCode,
+		Status:        http.StatusText(reply.StatusCode),
+		Header:        reply.Header,
+		Body:          io.NopCloser(bytes.NewReader(reply.Body)),
+		ContentLength: int64(len(reply.Body)),
+		Request:       req,
+	}, nil
+}
+
+type unavailableDB struct{}
+
+func (unavailableDB) Get(context.Context, any) (bool, error) { return false, ErrDBUnavailable }
+func (unavailableDB) Find(context.Context, any, builder.Cond) error {
+	return ErrDBUnavailable
+}
+
+func (unavailableDB) Count(context.Context, any, builder.Cond) (int64, error) {
+	return 0, ErrDBUnavailable
+}
+func (unavailableDB) Insert(context.Context, ...any) error { return ErrDBUnavailable }
+func (unavailableDB) Update(context.Context, any, builder.Cond) (int64, error) {
+	return 0, ErrDBUnavailable
+}
+func (unavailableDB) Delete(context.Context, any, builder.Cond) (int64, error) {
+	return 0, ErrDBUnavailable
+}
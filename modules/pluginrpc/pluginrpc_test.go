@@ -0,0 +1,391 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pluginrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPlugin struct {
+	host      plugin_model.Host
+	config    map[string]any
+	enabled   bool
+	events    []*plugin_model.Event
+	unhealthy bool
+	cronRuns  int
+}
+
+func (p *testPlugin) Info() *plugin_model.PluginInfo {
+	return &plugin_model.PluginInfo{ID: "test", Name: "Test", Version: "1.0.0"}
+}
+
+func (p *testPlugin) Init(host plugin_model.Host) error {
+	p.host = host
+	return nil
+}
+
+func (p *testPlugin) RegisterRoutes(r chi.Router) {
+	r.Get("/test/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	r.Get("/test/hello", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "1")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("hello " + r.URL.Query().Get("name")))
+	})
+}
+
+func (p *testPlugin) RegisterAPIRoutes(r chi.Router) {
+	r.Post("/api/v1/test/ping", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("pong"))
+	})
+}
+
+func (p *testPlugin) RegisterModels() []any     { return nil }
+func (p *testPlugin) GetTemplatePath() string   { return "" }
+func (p *testPlugin) GetAssetsPath() string     { return "" }
+func (p *testPlugin) GetLocalePath() string     { return "" }
+func (p *testPlugin) Enable() error             { p.enabled = true; return nil }
+func (p *testPlugin) Disable() error            { p.enabled = false; return nil }
+func (p *testPlugin) Uninstall() error          { return nil }
+func (p *testPlugin) GetConfig() map[string]any { return p.config }
+func (p *testPlugin) SetConfig(c map[string]any) error {
+	p.config = c
+	return nil
+}
+
+func (p *testPlugin) SubscribedEvents() []string {
+	return []string{plugin_model.EventPushCommits}
+}
+
+func (p *testPlugin) HandleEvent(_ context.Context, event *plugin_model.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *testPlugin) HealthCheck(_ context.Context) error {
+	if p.unhealthy {
+		return errors.New("database unreachable")
+	}
+	return nil
+}
+
+func (p *testPlugin) RegisterSlots(r plugin_model.SlotRegistry) {
+	r.RegisterSlot(plugin_model.SlotIssueSidebar, func(_ context.Context, sc *plugin_model.SlotContext) (template.HTML, error) {
+		return template.HTML("<div>#" + strconv.FormatInt(sc.Issue.Index, 10) + "</div>"), nil
+	})
+}
+
+func (p *testPlugin) CronTasks() []*plugin_model.CronTask {
+	return []*plugin_model.CronTask{{
+		Name:     "cleanup",
+		Title:    "Test cleanup",
+		Schedule: "@every 1h",
+		Run: func(context.Context) error {
+			p.cronRuns++
+			return nil
+		},
+	}}
+}
+
+func TestClientServer(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	p := &testPlugin{}
+	go func() { _ = ServeConn(serverConn, p, &remoteHost{pluginID: "test"}) }()
+
+	client := NewClient(clientConn, 0)
+	defer client.Close()
+
+	hs, err := client.Handshake()
+	require.NoError(t, err)
+	assert.Equal(t, ProtocolVersion, hs.ProtocolVersion)
+	assert.Equal(t, "test", hs.Info.ID)
+	assert.ElementsMatch(t, []Route{
+		{Method: "GET", Pattern: "/test/panic"},
+		{Method: "GET", Pattern: "/test/hello"},
+		{Method: "POST", Pattern: "/api/v1/test/ping", API: true},
+	}, hs.Routes)
+	assert.Equal(t, []string{plugin_model.EventPushCommits}, hs.Events)
+	assert.Equal(t, []string{plugin_model.SlotIssueSidebar}, hs.Slots)
+	require.Len(t, hs.CronTasks, 1)
+	assert.Equal(t, "cleanup", hs.CronTasks[0].Name)
+	assert.Equal(t, "Test cleanup", hs.CronTasks[0].Title)
+	assert.Equal(t, "@every 1h", hs.CronTasks[0].Schedule)
+
+	require.NoError(t, client.Init())
+	require.NotNil(t, p.host)
+	assert.Equal(t, "test", p.host.PluginID())
+	_, err = p.host.Settings(t.Context())
+	assert.ErrorIs(t, err, ErrHostUnavailable)
+
+	require.NoError(t, client.Enable())
+	assert.True(t, p.enabled)
+
+	require.NoError(t, client.SetConfig(map[string]any{"max": 10.0}))
+	cfg, err := client.GetConfig()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"max": 10.0}, cfg)
+
+	resp, err := client.ServeHTTP(t.Context(), &HTTPRequest{Method: "GET", URL: "/test/hello?name=gitea", Header: http.Header{}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("X-Test"))
+	assert.Equal(t, "hello gitea", string(resp.Body))
+
+	resp, err = client.ServeHTTP(t.Context(), &HTTPRequest{API: true, Method: "POST", URL: "/api/v1/test/ping", Header: http.Header{}})
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(resp.Body))
+
+	// a panicking handler does not take the plugin process down
+	resp, err = client.ServeHTTP(t.Context(), &HTTPRequest{Method: "GET", URL: "/test/panic", Header: http.Header{}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	require.NoError(t, client.HealthCheck())
+	p.unhealthy = true
+	assert.ErrorContains(t, client.HealthCheck(), "database unreachable")
+	p.unhealthy = false
+
+	require.NoError(t, client.RunCronTask("cleanup"))
+	assert.Equal(t, 1, p.cronRuns)
+	assert.ErrorContains(t, client.RunCronTask("unknown"), "unknown cron task")
+
+	require.NoError(t, client.HandleEvent(&plugin_model.Event{
+		Type:    plugin_model.EventPushCommits,
+		RepoID:  1,
+		RefName: "refs/heads/main",
+		Commits: []*plugin_model.EventCommit{{Sha1: "abc", Message: "init"}},
+	}))
+	html, err := client.RenderSlot(&plugin_model.SlotContext{Slot: plugin_model.SlotIssueSidebar, Issue: &plugin_model.SlotIssue{Index: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, "<div>#3</div>", html)
+
+	require.Len(t, p.events, 1)
+	assert.Equal(t, "refs/heads/main", p.events[0].RefName)
+	assert.Equal(t, "abc", p.events[0].Commits[0].Sha1)
+}
+
+// limitTestPlugin 响应过大或一直不响应的插件
+type limitTestPlugin struct {
+	testPlugin
+	release chan struct{}
+}
+
+func (p *limitTestPlugin) RegisterRoutes(r chi.Router) {
+	r.Get("/test/large", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("a"), 4096))
+	})
+	r.Get("/test/block", func(w http.ResponseWriter, r *http.Request) {
+		<-p.release
+	})
+}
+
+func TestClientLimits(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	p := &limitTestPlugin{release: make(chan struct{})}
+	defer close(p.release)
+	go func() { _ = ServeConn(serverConn, p, &remoteHost{pluginID: "test"}) }()
+
+	client := NewClient(clientConn, 1024)
+	defer client.Close()
+	_, err := client.Handshake()
+	require.NoError(t, err)
+
+	// 调用在 ctx 结束时返回，不等待插件的响应
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.ServeHTTP(ctx, &HTTPRequest{Method: "GET", URL: "/test/block", Header: http.Header{}})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// 过大的响应使连接失效
+	_, err = client.ServeHTTP(t.Context(), &HTTPRequest{Method: "GET", URL: "/test/large", Header: http.Header{}})
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+	assert.Error(t, client.HealthCheck())
+}
+
+type testHost struct {
+	notified []string
+	client   *http.Client
+	files    testStorage
+}
+
+func (h *testHost) PluginID() string    { return "test" }
+func (h *testHost) DB() plugin_model.DB { return nil }
+func (h *testHost) GetUserByID(_ context.Context, id int64) (*plugin_model.HostUser, error) {
+	if id != 1 {
+		return nil, plugin_model.ErrPermissionDenied{PluginID: "test", Permission: plugin_model.PermissionUserRead}
+	}
+	return &plugin_model.HostUser{ID: 1, Name: "user1"}, nil
+}
+
+func (h *testHost) GetUserByName(_ context.Context, name string) (*plugin_model.HostUser, error) {
+	return &plugin_model.HostUser{ID: 2, Name: name}, nil
+}
+
+func (h *testHost) GetRepository(_ context.Context, doerID int64, ownerName, repoName string) (*plugin_model.HostRepo, error) {
+	if ownerName != "user1" || repoName != "repo1" {
+		return nil, util.NewNotExistErrorf("repository %s/%s does not exist", ownerName, repoName)
+	}
+	mode := "read"
+	if doerID == 1 {
+		mode = "owner"
+	}
+	return &plugin_model.HostRepo{ID: 1, OwnerName: ownerName, Name: repoName, DefaultBranch: "main", AccessMode: mode}, nil
+}
+
+func (h *testHost) SendNotification(_ context.Context, userID int64, subject, _ string) error {
+	h.notified = append(h.notified, subject)
+	return nil
+}
+
+func (h *testHost) Settings(context.Context) (*plugin_model.HostSettings, error) {
+	return &plugin_model.HostSettings{AppName: "Gitea"}, nil
+}
+
+func (h *testHost) HTTPClient(context.Context) (*http.Client, error) {
+	return h.client, nil
+}
+
+func (h *testHost) DataDir(context.Context) (string, error) {
+	return "/data/plugin-data/test", nil
+}
+
+func (h *testHost) Storage(context.Context) (plugin_model.Storage, error) {
+	return h.files, nil
+}
+
+type testStorage map[string][]byte
+
+func (s testStorage) Save(_ context.Context, path string, r io.Reader, _ int64) (int64, error) {
+	data, err := io.ReadAll(r)
+	s[path] = data
+	return int64(len(data)), err
+}
+
+func (s testStorage) Open(_ context.Context, path string) (io.ReadCloser, error) {
+	data, ok := s[path]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s testStorage) Delete(_ context.Context, path string) error {
+	delete(s, path)
+	return nil
+}
+
+func (s testStorage) List(_ context.Context, dir string) ([]string, error) {
+	var paths []string
+	for path := range s {
+		if strings.HasPrefix(path, dir) {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+func TestRemoteHost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		_, _ = w.Write(append([]byte("echo "), body...))
+	}))
+	defer srv.Close()
+
+	socket := filepath.Join(t.TempDir(), "host.sock")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer l.Close()
+
+	h := &testHost{client: srv.Client(), files: testStorage{}}
+	go func() { _ = ServeHost(t.Context(), l, h, 1024) }()
+
+	host, err := DialHost(socket, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "test", host.PluginID())
+
+	u, err := host.GetUserByID(t.Context(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "user1", u.Name)
+	_, err = host.GetUserByID(t.Context(), 2)
+	assert.ErrorContains(t, err, "user.read")
+
+	u, err = host.GetUserByName(t.Context(), "user2")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, u.ID)
+
+	repo, err := host.GetRepository(t.Context(), 1, "user1", "repo1")
+	require.NoError(t, err)
+	assert.Equal(t, "main", repo.DefaultBranch)
+	assert.Equal(t, "owner", repo.AccessMode)
+	_, err = host.GetRepository(t.Context(), 0, "user1", "missing")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	require.NoError(t, host.SendNotification(t.Context(), 1, "hello", "body"))
+	assert.Equal(t, []string{"hello"}, h.notified)
+
+	settings, err := host.Settings(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "Gitea", settings.AppName)
+
+	_, err = host.DB().Count(t.Context(), nil, nil)
+	assert.ErrorIs(t, err, ErrDBUnavailable)
+
+	client, err := host.HTTPClient(t.Context())
+	require.NoError(t, err)
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("ping"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "POST", resp.Header.Get("X-Method"))
+	assert.Equal(t, "echo ping", string(body))
+
+	// 响应体超过宿主允许的大小
+	_, err = client.Post(srv.URL, "text/plain", strings.NewReader(strings.Repeat("a", 1024)))
+	assert.ErrorContains(t, err, ErrHTTPResponseTooLarge.Error())
+
+	dir, err := host.DataDir(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "/data/plugin-data/test", dir)
+
+	st, err := host.Storage(t.Context())
+	require.NoError(t, err)
+	size, err := st.Save(t.Context(), "cache/a.txt", strings.NewReader("hello"), -1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, size)
+	paths, err := st.List(t.Context(), "cache/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache/a.txt"}, paths)
+	f, err := st.Open(t.Context(), "cache/a.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	require.NoError(t, st.Delete(t.Context(), "cache/a.txt"))
+	_, err = st.Open(t.Context(), "cache/a.txt")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package pluginrpc 实现 process 运行时插件与 Gitea 之间的 RPC 协议。
+//
+// 插件作为子进程运行，通过 stdio 或 unix socket 使用 JSON-RPC 通信。
+// 连接建立后 Gitea 首先发送 Handshake，双方协议版本不一致时拒绝连接。
+package pluginrpc
+
+import (
+	"net/http"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+)
+
+// ProtocolVersion 当前协议版本，协议有不兼容变更时递增
+const ProtocolVersion = 1
+
+// ServiceName 插件端注册的 RPC 服务名
+const ServiceName = "Plugin"
+
+// HostServiceName Gitea 端为插件提供宿主能力的 RPC 服务名
+const HostServiceName = "Host"
+
+// 启动插件进程时传递的环境变量
+const (
+	EnvProtocolVersion = "GITEA_PLUGIN_PROTOCOL_VERSION"
+	EnvPluginID        = "GITEA_PLUGIN_ID"
+	EnvSocket          = "GITEA_PLUGIN_SOCKET"      // 为空时使用 stdio
+	EnvHostSocket      = "GITEA_PLUGIN_HOST_SOCKET" // 宿主能力服务的 unix socket
+	EnvDataDir         = "GITEA_PLUGIN_DATA_DIR"    // 插件专属的数据目录，也是插件进程的 HOME
+)
+
+// Empty 无参数/无返回值
+type Empty struct{}
+
+// HandshakeArgs 握手请求
+type HandshakeArgs struct {
+	ProtocolVersion int
+}
+
+// HandshakeReply 握手响应
+type HandshakeReply struct {
+	ProtocolVersion int
+	Info            *plugin_model.PluginInfo
+	Routes          []Route
+	Events          []string // 插件订阅的事件，未实现 EventSubscriber 时为空
+	Slots           []string // 插件注册了渲染函数的 UI 插槽
+	CronTasks       []*plugin_model.CronTask
+}
+
+// Route 插件声明的路由
+type Route struct {
+	Method  string
+	Pattern string
+	API     bool // 是否为 API 路由（RegisterAPIRoutes 注册）
+}
+
+// CronTaskArgs 执行定时任务的参数
+type CronTaskArgs struct {
+	Name string
+}
+
+// SlotReply 插槽渲染结果
+type SlotReply struct {
+	HTML string
+}
+
+// ConfigArgs 配置参数
+type ConfigArgs struct {
+	Config map[string]any
+}
+
+// ConfigReply 配置响应
+type ConfigReply struct {
+	Config map[string]any
+}
+
+// HTTPRequest 转发给插件的 HTTP 请求
+type HTTPRequest struct {
+	API        bool
+	Method     string
+	URL        string
+	Header     http.Header
+	Body       []byte
+	RemoteAddr string
+	Info       *plugin_model.RequestInfo // 当前用户等请求信息
+}
+
+// HTTPResponse 插件返回的 HTTP 响应
+type HTTPResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// UserArgs 查询用户参数
+type UserArgs struct {
+	ID   int64
+	Name string
+}
+
+// NotificationArgs 发送通知参数
+type NotificationArgs struct {
+	UserID  int64
+	Subject string
+	Body    string
+}
+
+// DataDirReply 插件数据目录
+type DataDirReply struct {
+	Dir string
+}
+
+// RepoArgs 查询仓库参数
+type RepoArgs struct {
+	DoerID    int64
+	OwnerName string
+	RepoName  string
+}
+
+// RepoReply 查询仓库结果
+type RepoReply struct {
+	Repo     *plugin_model.HostRepo
+	NotExist bool // 仓库不存在或无权访问，插件端转换为 util.ErrNotExist
+}
+
+// StorageArgs 对象存储操作参数
+type StorageArgs struct {
+	Path string
+	Data []byte
+}
+
+// StorageReply 对象存储操作结果
+type StorageReply struct {
+	Data     []byte
+	Size     int64
+	NotExist bool // 文件不存在，插件端转换为 os.ErrNotExist
+	Paths    []string
+}
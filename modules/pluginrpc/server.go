@@ -0,0 +1,289 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pluginrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Serve 在插件进程中运行 RPC 服务，直到 Gitea 关闭连接。
+// 插件的 main 函数应调用此方法，插件日志只能写到 stderr。
+func Serve(p plugin_model.IPlugin) error {
+	if v := os.Getenv(EnvProtocolVersion); v != "" && v != strconv.Itoa(ProtocolVersion) {
+		return fmt.Errorf("unsupported protocol version %s, plugin speaks %d", v, ProtocolVersion)
+	}
+
+	var conn io.ReadWriteCloser
+	if socket := os.Getenv(EnvSocket); socket != "" {
+		c, err := net.Dial("unix", socket)
+		if err != nil {
+			return fmt.Errorf("dial %s: %w", socket, err)
+		}
+		conn = c
+	} else {
+		conn = &stdioConn{in: os.Stdin, out: os.Stdout}
+	}
+
+	var pluginID string
+	if info := p.Info(); info != nil {
+		pluginID = info.ID
+	}
+	host := &remoteHost{pluginID: pluginID}
+	if socket := os.Getenv(EnvHostSocket); socket != "" {
+		h, err := DialHost(socket, pluginID)
+		if err != nil {
+			return fmt.Errorf("dial host %s: %w", socket, err)
+		}
+		host = h.(*remoteHost)
+	}
+	return ServeConn(conn, p, host)
+}
+
+// ServeConn 在指定连接上运行 RPC 服务，host 会在 Init 时交给插件
+func ServeConn(conn io.ReadWriteCloser, p plugin_model.IPlugin, host plugin_model.Host) error {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName(ServiceName, newService(p, host)); err != nil {
+		return err
+	}
+	srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+	return nil
+}
+
+// service 插件端 RPC 服务，将调用转发给 IPlugin 实现
+type service struct {
+	impl      plugin_model.IPlugin
+	host      plugin_model.Host
+	webRouter chi.Router
+	apiRouter chi.Router
+	slots     slotRegistry
+	cronTasks []*plugin_model.CronTask
+}
+
+// slotRegistry 插件在 RegisterSlots 中注册的插槽渲染函数
+type slotRegistry map[string][]plugin_model.SlotRenderer
+
+func (r slotRegistry) RegisterSlot(slot string, render plugin_model.SlotRenderer) {
+	r[slot] = append(r[slot], render)
+}
+
+func newService(p plugin_model.IPlugin, host plugin_model.Host) *service {
+	s := &service{
+		impl:      p,
+		host:      host,
+		webRouter: chi.NewRouter(),
+		apiRouter: chi.NewRouter(),
+		slots:     make(slotRegistry),
+	}
+	p.RegisterRoutes(s.webRouter)
+	p.RegisterAPIRoutes(s.apiRouter)
+	if sp, ok := p.(plugin_model.SlotProvider); ok {
+		sp.RegisterSlots(s.slots)
+	}
+	if cp, ok := p.(plugin_model.CronProvider); ok {
+		s.cronTasks = cp.CronTasks()
+	}
+	return s
+}
+
+// Handshake 协商协议版本并返回插件信息和路由
+func (s *service) Handshake(args HandshakeArgs, reply *HandshakeReply) error {
+	if args.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("protocol version mismatch: host %d, plugin %d", args.ProtocolVersion, ProtocolVersion)
+	}
+
+	reply.ProtocolVersion = ProtocolVersion
+	reply.Info = s.impl.Info()
+	if sub, ok := s.impl.(plugin_model.EventSubscriber); ok {
+		reply.Events = sub.SubscribedEvents()
+	}
+	for slot := range s.slots {
+		reply.Slots = append(reply.Slots, slot)
+	}
+	slices.Sort(reply.Slots)
+	reply.CronTasks = s.cronTasks
+
+	for _, r := range []struct {
+		router chi.Router
+		api    bool
+	}{{s.webRouter, false}, {s.apiRouter, true}} {
+		err := chi.Walk(r.router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			reply.Routes = append(reply.Routes, Route{Method: method, Pattern: route, API: r.api})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Info 获取插件信息
+func (s *service) Info(_ Empty, reply *plugin_model.PluginInfo) error {
+	if info := s.impl.Info(); info != nil {
+		*reply = *info
+	}
+	return nil
+}
+
+// Init 初始化插件
+func (s *service) Init(_ Empty, _ *Empty) error {
+	return s.impl.Init(s.host)
+}
+
+// Enable 启用插件
+func (s *service) Enable(_ Empty, _ *Empty) error {
+	return s.impl.Enable()
+}
+
+// Disable 禁用插件
+func (s *service) Disable(_ Empty, _ *Empty) error {
+	return s.impl.Disable()
+}
+
+// Uninstall 卸载插件
+func (s *service) Uninstall(_ Empty, _ *Empty) error {
+	return s.impl.Uninstall()
+}
+
+// GetConfig 获取配置
+func (s *service) GetConfig(_ Empty, reply *ConfigReply) error {
+	reply.Config = s.impl.GetConfig()
+	return nil
+}
+
+// SetConfig 设置配置
+func (s *service) SetConfig(args ConfigArgs, _ *Empty) error {
+	return s.impl.SetConfig(args.Config)
+}
+
+// HandleEvent 处理 Gitea 投递的事件
+func (s *service) HandleEvent(args plugin_model.Event, _ *Empty) (err error) {
+	defer recoverError(&err)
+	sub, ok := s.impl.(plugin_model.EventSubscriber)
+	if !ok {
+		return errors.New("plugin does not subscribe to events")
+	}
+	return sub.HandleEvent(context.Background(), &args)
+}
+
+// HealthCheck 检查插件运行状态，未实现 HealthChecker 的插件只要能响应即视为正常
+func (s *service) HealthCheck(_ Empty, _ *Empty) (err error) {
+	defer recoverError(&err)
+	if checker, ok := s.impl.(plugin_model.HealthChecker); ok {
+		return checker.HealthCheck(context.Background())
+	}
+	return nil
+}
+
+// RunCronTask 执行定时任务
+func (s *service) RunCronTask(args CronTaskArgs, _ *Empty) (err error) {
+	defer recoverError(&err)
+	for _, task := range s.cronTasks {
+		if task != nil && task.Name == args.Name && task.Run != nil {
+			return task.Run(context.Background())
+		}
+	}
+	return fmt.Errorf("unknown cron task: %s", args.Name)
+}
+
+// RenderSlot 渲染插槽，插件在同一插槽注册的多个渲染函数的结果依次拼接
+func (s *service) RenderSlot(args plugin_model.SlotContext, reply *SlotReply) (err error) {
+	defer recoverError(&err)
+	var sb strings.Builder
+	for _, render := range s.slots[args.Slot] {
+		content, err := render(context.Background(), &args)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(string(content))
+	}
+	reply.HTML = sb.String()
+	return nil
+}
+
+// ServeHTTP 处理 Gitea 转发的 HTTP 请求
+func (s *service) ServeHTTP(args HTTPRequest, reply *HTTPResponse) error {
+	req, err := http.NewRequest(args.Method, args.URL, bytes.NewReader(args.Body))
+	if err != nil {
+		return err
+	}
+	req.Header = args.Header
+	req.RemoteAddr = args.RemoteAddr
+	if args.Info != nil {
+		req = req.WithContext(plugin_model.WithRequestInfo(req.Context(), args.Info))
+	}
+
+	router := s.webRouter
+	if args.API {
+		router = s.apiRouter
+	} else {
+		// 模板由 Gitea 端使用原始请求的上下文渲染
+		req = req.WithContext(plugin_model.WithHTMLRenderer(req.Context(), plugin_model.WriteTemplateResponse))
+	}
+
+	rec := httptest.NewRecorder()
+	func() {
+		// 处理函数 panic 时返回 500，不让整个插件进程退出
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Fprintf(os.Stderr, "panic handling %s %s: %v\n", args.Method, args.URL, r)
+				rec = httptest.NewRecorder()
+				rec.WriteHeader(http.StatusInternalServerError)
+			}
+		}()
+		router.ServeHTTP(rec, req)
+	}()
+
+	reply.StatusCode = rec.Code
+	reply.Header = rec.Header()
+	reply.Body = rec.Body.Bytes()
+	return nil
+}
+
+// recoverError 将插件方法的 panic 转换为 RPC 错误返回给 Gitea
+func recoverError(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("panic: %v", r)
+	}
+}
+
+// stdioConn 将 stdin/stdout 组合为一个连接
+type stdioConn struct {
+	in  io.ReadCloser
+	out io.WriteCloser
+}
+
+func (c *stdioConn) Read(p []byte) (int, error) {
+	return c.in.Read(p)
+}
+
+func (c *stdioConn) Write(p []byte) (int, error) {
+	return c.out.Write(p)
+}
+
+func (c *stdioConn) Close() error {
+	errIn := c.in.Close()
+	errOut := c.out.Close()
+	if errIn != nil {
+		return errIn
+	}
+	return errOut
+}
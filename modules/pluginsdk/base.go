@@ -0,0 +1,154 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package pluginsdk 插件开发工具包。
+//
+// 插件结构体嵌入 Base 即获得 IPlugin 除 Info 以外所有方法的默认实现，只需覆盖用到的方法；
+// 路由处理函数通过 Base.Handle 包装后可以使用 Context 访问当前用户、仓库并输出 JSON 或 HTML。
+// native 和 process 运行时的插件使用方式相同。
+package pluginsdk
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"strconv"
+	"sync"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Base 插件的基础实现，各方法均为空操作，配置保存在内存中
+type Base struct {
+	Host plugin_model.Host // Init 时由宿主传入
+
+	TemplatePath string // 模板目录，为空表示没有模板
+	AssetsPath   string // 静态资源目录，为空表示没有静态资源
+	LocalePath   string // 国际化文件目录，为空表示没有国际化文件
+
+	mu     sync.RWMutex
+	config map[string]any
+}
+
+// Init 保存宿主能力接口，覆盖此方法时应先调用 Base.Init
+func (b *Base) Init(host plugin_model.Host) error {
+	b.Host = host
+	return nil
+}
+
+// RegisterRoutes 注册 Web 路由
+func (b *Base) RegisterRoutes(chi.Router) {}
+
+// RegisterAPIRoutes 注册 API 路由
+func (b *Base) RegisterAPIRoutes(chi.Router) {}
+
+// RegisterModels 注册数据库模型
+func (b *Base) RegisterModels() []any {
+	return nil
+}
+
+// GetTemplatePath 获取模板路径
+func (b *Base) GetTemplatePath() string {
+	return b.TemplatePath
+}
+
+// GetAssetsPath 获取静态资源路径
+func (b *Base) GetAssetsPath() string {
+	return b.AssetsPath
+}
+
+// GetLocalePath 获取国际化文件路径
+func (b *Base) GetLocalePath() string {
+	return b.LocalePath
+}
+
+// Enable 启用插件
+func (b *Base) Enable() error {
+	return nil
+}
+
+// Disable 禁用插件
+func (b *Base) Disable() error {
+	return nil
+}
+
+// Uninstall 卸载插件
+func (b *Base) Uninstall() error {
+	return nil
+}
+
+// GetConfig 获取配置的副本
+func (b *Base) GetConfig() map[string]any {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return maps.Clone(b.config)
+}
+
+// SetConfig 保存宿主下发的配置（已包含 config_schema 中的默认值）
+func (b *Base) SetConfig(config map[string]any) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.config = maps.Clone(config)
+	return nil
+}
+
+// ConfigString 获取字符串配置项，不存在或类型不符时返回 def
+func (b *Base) ConfigString(name, def string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if s, ok := b.config[name].(string); ok {
+		return s
+	}
+	return def
+}
+
+// ConfigInt 获取整数配置项，兼容 JSON 解码得到的浮点数和字符串，不存在或无法转换时返回 def
+func (b *Base) ConfigInt(name string, def int64) int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	switch v := b.config[name].(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	}
+	return def
+}
+
+// ConfigBool 获取布尔配置项，不存在或无法转换时返回 def
+func (b *Base) ConfigBool(name string, def bool) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	switch v := b.config[name].(type) {
+	case bool:
+		return v
+	case string:
+		if ok, err := strconv.ParseBool(v); err == nil {
+			return ok
+		}
+	}
+	return def
+}
+
+// Handle 将使用 Context 的处理函数包装为 http.HandlerFunc，用于 RegisterRoutes 和 RegisterAPIRoutes
+func (b *Base) Handle(h HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := NewContext(b.Host, w, r)
+		if !ctx.loadRepo() {
+			return
+		}
+		h(ctx)
+	}
+}
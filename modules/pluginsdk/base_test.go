@@ -0,0 +1,31 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pluginsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseConfig(t *testing.T) {
+	var b Base
+	config := map[string]any{"name": "x", "count": float64(3), "limit": "7", "on": true, "off": "false"}
+	require.NoError(t, b.SetConfig(config))
+	config["name"] = "changed"
+
+	assert.Equal(t, "x", b.ConfigString("name", ""))
+	assert.Equal(t, "def", b.ConfigString("count", "def"))
+	assert.EqualValues(t, 3, b.ConfigInt("count", 0))
+	assert.EqualValues(t, 7, b.ConfigInt("limit", 0))
+	assert.EqualValues(t, 5, b.ConfigInt("name", 5))
+	assert.True(t, b.ConfigBool("on", false))
+	assert.False(t, b.ConfigBool("off", true))
+	assert.True(t, b.ConfigBool("missing", true))
+
+	got := b.GetConfig()
+	got["name"] = "changed"
+	assert.Equal(t, "x", b.ConfigString("name", ""))
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pluginsdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandlerFunc 使用 Context 的路由处理函数
+type HandlerFunc func(ctx *Context)
+
+// Context 插件路由请求的上下文，用法与 Gitea 的 context.Context 相近
+type Context struct {
+	Req  *http.Request
+	Resp http.ResponseWriter
+	Host plugin_model.Host
+
+	Doer *plugin_model.HostUser // 当前登录的用户，未登录时为 nil
+	Lang string                 // 当前界面语言
+	Repo *plugin_model.HostRepo // 路由包含 {owner} 和 {repo} 参数时自动加载，需要 repo.read 权限
+}
+
+var _ context.Context = (*Context)(nil)
+
+// NewContext 根据宿主传入的请求信息创建上下文，一般通过 Base.Handle 使用
+func NewContext(host plugin_model.Host, w http.ResponseWriter, r *http.Request) *Context {
+	ctx := &Context{Req: r, Resp: w, Host: host}
+	if info := plugin_model.GetRequestInfo(r.Context()); info != nil {
+		ctx.Doer = info.Doer
+		ctx.Lang = info.Lang
+	}
+	return ctx
+}
+
+// loadRepo 按路由中的 {owner} 和 {repo} 参数加载当前用户可以访问的仓库，失败时已写入响应
+func (ctx *Context) loadRepo() bool {
+	ownerName, repoName := ctx.PathParam("owner"), ctx.PathParam("repo")
+	if ownerName == "" || repoName == "" {
+		return true
+	}
+	if ctx.Host == nil {
+		ctx.ServerError("GetRepository", errors.New("plugin is not initialized"))
+		return false
+	}
+	var doerID int64
+	if ctx.Doer != nil {
+		doerID = ctx.Doer.ID
+	}
+	repo, err := ctx.Host.GetRepository(ctx, doerID, ownerName, repoName)
+	if errors.Is(err, util.ErrNotExist) {
+		ctx.NotFound()
+		return false
+	} else if err != nil {
+		ctx.ServerError("GetRepository", err)
+		return false
+	}
+	ctx.Repo = repo
+	return true
+}
+
+// Deadline 实现 context.Context
+func (ctx *Context) Deadline() (time.Time, bool) {
+	return ctx.Req.Context().Deadline()
+}
+
+// Done 实现 context.Context
+func (ctx *Context) Done() <-chan struct{} {
+	return ctx.Req.Context().Done()
+}
+
+// Err 实现 context.Context
+func (ctx *Context) Err() error {
+	return ctx.Req.Context().Err()
+}
+
+// Value 实现 context.Context
+func (ctx *Context) Value(key any) any {
+	return ctx.Req.Context().Value(key)
+}
+
+// IsSigned 当前用户是否已登录
+func (ctx *Context) IsSigned() bool {
+	return ctx.Doer != nil
+}
+
+// IsAPI 是否为 API 路由的请求，API 请求不能渲染模板
+func (ctx *Context) IsAPI() bool {
+	return plugin_model.GetHTMLRenderer(ctx.Req.Context()) == nil
+}
+
+// PathParam 获取路由参数
+func (ctx *Context) PathParam(name string) string {
+	return chi.URLParam(ctx.Req, name)
+}
+
+// PathParamInt64 获取整数路由参数，无法转换时返回 0
+func (ctx *Context) PathParamInt64(name string) int64 {
+	v, _ := strconv.ParseInt(ctx.PathParam(name), 10, 64)
+	return v
+}
+
+// FormString 获取去除首尾空白的表单或查询参数
+func (ctx *Context) FormString(name string) string {
+	return strings.TrimSpace(ctx.Req.FormValue(name))
+}
+
+// FormInt64 获取整数表单或查询参数，无法转换时返回 0
+func (ctx *Context) FormInt64(name string) int64 {
+	v, _ := strconv.ParseInt(ctx.FormString(name), 10, 64)
+	return v
+}
+
+// FormBool 获取布尔表单或查询参数，复选框的 on 视为 true
+func (ctx *Context) FormBool(name string) bool {
+	s := ctx.FormString(name)
+	v, _ := strconv.ParseBool(s)
+	return v || s == "on"
+}
+
+// DecodeJSON 解析 JSON 请求体
+func (ctx *Context) DecodeJSON(v any) error {
+	return json.NewDecoder(ctx.Req.Body).Decode(v)
+}
+
+// JSON 输出 JSON 响应
+func (ctx *Context) JSON(status int, v any) {
+	ctx.Resp.Header().Set("Content-Type", "application/json;charset=utf-8")
+	ctx.Resp.WriteHeader(status)
+	if err := json.NewEncoder(ctx.Resp).Encode(v); err != nil {
+		ctx.logError("Render JSON", err)
+	}
+}
+
+// HTML 使用 Gitea 的页面布局渲染插件模板，name 为插件 templates 目录下不含扩展名的模板名
+func (ctx *Context) HTML(status int, name string, data map[string]any) {
+	render := plugin_model.GetHTMLRenderer(ctx.Req.Context())
+	if render == nil {
+		ctx.ServerError("HTML", fmt.Errorf("cannot render template %s for an API request", name))
+		return
+	}
+	if data == nil {
+		data = map[string]any{}
+	}
+	render(ctx.Resp, status, name, data)
+}
+
+// APIError 输出与 Gitea API 相同格式的错误，msg 可以是字符串或 error
+func (ctx *Context) APIError(status int, msg any) {
+	var message string
+	switch v := msg.(type) {
+	case error:
+		message = v.Error()
+	case string:
+		message = v
+	default:
+		message = http.StatusText(status)
+	}
+	ctx.JSON(status, map[string]string{"message": message})
+}
+
+// NotFound 输出 404，API 请求返回 JSON 格式的错误
+func (ctx *Context) NotFound() {
+	if ctx.IsAPI() {
+		ctx.APIError(http.StatusNotFound, nil)
+		return
+	}
+	http.Error(ctx.Resp, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+}
+
+// ServerError 记录错误并输出 500，错误详情不返回给客户端
+func (ctx *Context) ServerError(logMsg string, err error) {
+	ctx.logError(logMsg, err)
+	if ctx.IsAPI() {
+		ctx.APIError(http.StatusInternalServerError, nil)
+		return
+	}
+	http.Error(ctx.Resp, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// Redirect 提交表单后跳转到指定地址
+func (ctx *Context) Redirect(location string) {
+	http.Redirect(ctx.Resp, ctx.Req, location, http.StatusSeeOther)
+}
+
+// Status 只输出状态码
+func (ctx *Context) Status(status int) {
+	ctx.Resp.WriteHeader(status)
+}
+
+// logError 写到 stderr：native 插件输出到 Gitea 的标准错误，process 插件由宿主转发到 Gitea 日志
+func (ctx *Context) logError(msg string, err error) {
+	pluginID := ""
+	if ctx.Host != nil {
+		pluginID = ctx.Host.PluginID()
+	}
+	fmt.Fprintf(os.Stderr, "plugin %s: %s %s: %s: %v\n", pluginID, ctx.Req.Method, ctx.Req.URL.Path, msg, err)
+}
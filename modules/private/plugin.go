@@ -0,0 +1,107 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+)
+
+// PluginUpgradeOptions represents the options for the plugin upgrade call
+type PluginUpgradeOptions struct {
+	Version string
+}
+
+// UpgradePlugin calls the internal plugin upgrade function
+func UpgradePlugin(ctx context.Context, pluginID, version string) ResponseExtra {
+	req := newInternalRequestAPI(ctx, pluginURL(pluginID, "upgrade"), "POST", PluginUpgradeOptions{Version: version})
+	// downloading and building the new version may take a while
+	req.SetReadWriteTimeout(10 * time.Minute)
+	_, extra := requestJSONResp(req, &Response{})
+	return extra
+}
+
+// RollbackPlugin calls the internal plugin rollback function
+func RollbackPlugin(ctx context.Context, pluginID string) ResponseExtra {
+	req := newInternalRequestAPI(ctx, pluginURL(pluginID, "rollback"), "POST")
+	req.SetReadWriteTimeout(10 * time.Minute)
+	_, extra := requestJSONResp(req, &Response{})
+	return extra
+}
+
+// PluginInstallOptions represents the options for the plugin install call
+type PluginInstallOptions struct {
+	ID      string
+	Version string
+}
+
+// PluginEnableOptions represents the options for the plugin enable call
+type PluginEnableOptions struct {
+	ApprovePermissions bool
+}
+
+// PluginUninstallOptions represents the options for the plugin uninstall call
+type PluginUninstallOptions struct {
+	PurgeData bool
+}
+
+func pluginURL(pluginID, action string) string {
+	return setting.LocalURL + "api/internal/plugins/" + url.PathEscape(pluginID) + "/" + action
+}
+
+// ListPlugins calls the internal plugin list function
+func ListPlugins(ctx context.Context) ([]*structs.Plugin, ResponseExtra) {
+	req := newInternalRequestAPI(ctx, setting.LocalURL+"api/internal/plugins", "GET")
+	plugins, extra := requestJSONResp(req, &[]*structs.Plugin{})
+	if extra.HasError() {
+		return nil, extra
+	}
+	return *plugins, extra
+}
+
+// InstallPlugin calls the internal plugin install function
+func InstallPlugin(ctx context.Context, pluginID, version string) ResponseExtra {
+	req := newInternalRequestAPI(ctx, setting.LocalURL+"api/internal/plugins/install", "POST", PluginInstallOptions{ID: pluginID, Version: version})
+	req.SetReadWriteTimeout(10 * time.Minute)
+	_, extra := requestJSONResp(req, &Response{})
+	return extra
+}
+
+// EnablePlugin calls the internal plugin enable function
+func EnablePlugin(ctx context.Context, pluginID string, approvePermissions bool) ResponseExtra {
+	req := newInternalRequestAPI(ctx, pluginURL(pluginID, "enable"), "POST", PluginEnableOptions{ApprovePermissions: approvePermissions})
+	_, extra := requestJSONResp(req, &Response{})
+	return extra
+}
+
+// DisablePlugin calls the internal plugin disable function
+func DisablePlugin(ctx context.Context, pluginID string) ResponseExtra {
+	req := newInternalRequestAPI(ctx, pluginURL(pluginID, "disable"), "POST")
+	_, extra := requestJSONResp(req, &Response{})
+	return extra
+}
+
+// GetPluginConfig calls the internal plugin config function
+func GetPluginConfig(ctx context.Context, pluginID string) (*structs.PluginConfig, ResponseExtra) {
+	req := newInternalRequestAPI(ctx, pluginURL(pluginID, "config"), "GET")
+	return requestJSONResp(req, &structs.PluginConfig{})
+}
+
+// EditPluginConfig calls the internal plugin config edit function
+func EditPluginConfig(ctx context.Context, pluginID string, config map[string]any) ResponseExtra {
+	req := newInternalRequestAPI(ctx, pluginURL(pluginID, "config"), "POST", structs.EditPluginConfigOption{Config: config})
+	_, extra := requestJSONResp(req, &Response{})
+	return extra
+}
+
+// UninstallPlugin calls the internal plugin uninstall function
+func UninstallPlugin(ctx context.Context, pluginID string, purgeData bool) ResponseExtra {
+	req := newInternalRequestAPI(ctx, pluginURL(pluginID, "uninstall"), "POST", PluginUninstallOptions{PurgeData: purgeData})
+	_, extra := requestJSONResp(req, &Response{})
+	return extra
+}
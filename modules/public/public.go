@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"code.gitea.io/gitea/modules/assetfs"
@@ -36,10 +37,47 @@ func FileHandlerFunc() http.HandlerFunc {
 			resp.WriteHeader(http.StatusMethodNotAllowed)
 			return
 		}
+		if overlayFS, file, ok := matchAssetOverlay(req.URL.Path); ok {
+			handleRequest(resp, req, overlayFS, file)
+			return
+		}
 		handleRequest(resp, req, assetFS, req.URL.Path)
 	}
 }
 
+var (
+	assetOverlaysMu sync.RWMutex
+	assetOverlays   = map[string]*assetfs.Layer{} // "/assets/{name}/" => layer
+)
+
+// AddAssetOverlay serves the files in dir under "/assets/{name}/", e.g.: "/assets/plugins/{id}/"
+func AddAssetOverlay(name, dir string) {
+	name = strings.Trim(name, "/")
+	assetOverlaysMu.Lock()
+	defer assetOverlaysMu.Unlock()
+	assetOverlays["/assets/"+name+"/"] = assetfs.Local(name, dir)
+}
+
+// RemoveAssetOverlay stops serving the files added by AddAssetOverlay
+func RemoveAssetOverlay(name string) {
+	name = strings.Trim(name, "/")
+	assetOverlaysMu.Lock()
+	defer assetOverlaysMu.Unlock()
+	delete(assetOverlays, "/assets/"+name+"/")
+}
+
+// matchAssetOverlay returns the overlay which serves the request path and the file path in the overlay
+func matchAssetOverlay(reqPath string) (http.FileSystem, string, bool) {
+	assetOverlaysMu.RLock()
+	defer assetOverlaysMu.RUnlock()
+	for prefix, layer := range assetOverlays {
+		if file, ok := strings.CutPrefix(reqPath, prefix); ok {
+			return layer, file, true
+		}
+	}
+	return nil, "", false
+}
+
 // parseAcceptEncoding parse Accept-Encoding: deflate, gzip;q=1.0, *;q=0.5 as compress methods
 func parseAcceptEncoding(val string) container.Set[string] {
 	parts := strings.Split(val, ";")
@@ -4,6 +4,10 @@
 package public
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"code.gitea.io/gitea/modules/container"
@@ -32,3 +36,27 @@ func TestParseAcceptEncoding(t *testing.T) {
 		})
 	}
 }
+
+func TestAssetOverlay(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "css"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "css", "style.css"), []byte("body{}"), 0o644))
+
+	AddAssetOverlay("plugins/test", dir)
+	handler := FileHandlerFunc()
+
+	resp := httptest.NewRecorder()
+	handler(resp, httptest.NewRequest(http.MethodGet, "/assets/plugins/test/css/style.css", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "body{}", resp.Body.String())
+	assert.NotEmpty(t, resp.Header().Get("Cache-Control"))
+
+	resp = httptest.NewRecorder()
+	handler(resp, httptest.NewRequest(http.MethodGet, "/assets/plugins/test/../../../etc/passwd", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	RemoveAssetOverlay("plugins/test")
+	resp = httptest.NewRecorder()
+	handler(resp, httptest.NewRequest(http.MethodGet, "/assets/plugins/test/css/style.css", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
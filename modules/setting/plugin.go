@@ -3,18 +3,108 @@
 
 package setting
 
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// PluginRegistry 插件市场注册表
+type PluginRegistry struct {
+	Name  string
+	URL   string // 索引文件地址：http(s)://、file:// 或本地路径
+	Token string // 访问私有注册表（如私有仓库中的索引）时使用的令牌，只发送给索引所在的主机
+}
+
 // Plugin settings
 var (
 	PluginsDir           string
 	PluginMarketplaceURL string
 	PluginEnabled        bool
 	AllowMarketInstall   bool
+
+	// PluginRequireSignature 安装插件时是否要求包由受信任的发布者签名
+	PluginRequireSignature bool
+
+	// PluginMaxUploadSize 上传插件包的最大大小（MB）
+	PluginMaxUploadSize int64
+
+	// PluginQuarantineFailures 插件在 PluginQuarantineWindow 内失败达到此次数时被自动隔离，0 表示不隔离
+	PluginQuarantineFailures int
+	PluginQuarantineWindow   time.Duration
+
+	// PluginAllowedHostList 插件通过宿主 HTTP 客户端可以访问的主机，格式与 webhook 的 ALLOWED_HOST_LIST 相同
+	PluginAllowedHostList string
+	// PluginMaxHTTPResponseSize process 插件通过宿主 HTTP 客户端读取的响应体的最大大小（MB）
+	PluginMaxHTTPResponseSize int64
+	// PluginDataDir 插件专属数据目录的根目录，每个插件使用其中以插件 ID 命名的子目录
+	PluginDataDir string
+	// PluginStorage 插件对象存储，每个插件使用以插件 ID 为前缀的命名空间
+	PluginStorage *Storage
+
+	// PluginRegistries 插件市场注册表，按优先级排列，多个注册表中存在同一插件时使用靠前的注册表
+	PluginRegistries []PluginRegistry
 )
 
-func loadPluginFrom(rootCfg ConfigProvider) {
+var validPluginRegistryName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func loadPluginFrom(rootCfg ConfigProvider) (err error) {
 	sec := rootCfg.Section("plugin")
 	PluginsDir = sec.Key("PLUGINS_DIR").MustString("./plugins")
 	PluginMarketplaceURL = sec.Key("MARKETPLACE_URL").MustString("https://plugins.gitea.io")
 	PluginEnabled = sec.Key("ENABLED").MustBool(true)
 	AllowMarketInstall = sec.Key("ALLOW_MARKETPLACE_INSTALL").MustBool(true)
+	PluginRequireSignature = sec.Key("REQUIRE_SIGNATURE").MustBool(true)
+	PluginMaxUploadSize = sec.Key("MAX_UPLOAD_SIZE").MustInt64(100)
+	PluginQuarantineFailures = sec.Key("QUARANTINE_FAILURES").MustInt(10)
+	PluginQuarantineWindow = sec.Key("QUARANTINE_WINDOW").MustDuration(5 * time.Minute)
+	PluginAllowedHostList = sec.Key("ALLOWED_HOST_LIST").MustString("")
+	PluginMaxHTTPResponseSize = sec.Key("MAX_HTTP_RESPONSE_SIZE").MustInt64(32)
+	PluginDataDir = sec.Key("DATA_DIR").MustString(filepath.Join(AppDataPath, "plugin-data"))
+	if !filepath.IsAbs(PluginDataDir) {
+		PluginDataDir = filepath.Join(AppWorkPath, PluginDataDir)
+	}
+
+	PluginStorage, err = getStorage(rootCfg, "plugins", "", sec)
+	if err != nil {
+		return err
+	}
+
+	PluginRegistries = loadPluginRegistries(rootCfg, sec)
+	return nil
+}
+
+// loadPluginRegistries 读取 REGISTRIES 中列出的 [plugin.registry.<名称>]，未配置时使用 MARKETPLACE_URL
+func loadPluginRegistries(rootCfg ConfigProvider, sec ConfigSection) []PluginRegistry {
+	names := sec.Key("REGISTRIES").Strings(",")
+	if len(names) == 0 {
+		indexURL := strings.TrimSuffix(PluginMarketplaceURL, "/")
+		if !strings.HasSuffix(indexURL, ".json") {
+			indexURL += "/index.json"
+		}
+		return []PluginRegistry{{Name: "default", URL: indexURL}}
+	}
+
+	registries := make([]PluginRegistry, 0, len(names))
+	for _, name := range names {
+		if !validPluginRegistryName.MatchString(name) {
+			log.Error("Invalid plugin registry name %q in [plugin] REGISTRIES", name)
+			continue
+		}
+		regSec := rootCfg.Section("plugin.registry." + name)
+		registry := PluginRegistry{
+			Name:  name,
+			URL:   regSec.Key("URL").String(),
+			Token: regSec.Key("TOKEN").String(),
+		}
+		if registry.URL == "" {
+			log.Error("Plugin registry %s has no URL, check [plugin.registry.%s]", name, name)
+			continue
+		}
+		registries = append(registries, registry)
+	}
+	return registries
 }
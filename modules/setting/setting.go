@@ -148,7 +148,9 @@ func loadCommonSettingsFrom(cfg ConfigProvider) error {
 	loadMirrorFrom(cfg)
 	loadMarkupFrom(cfg)
 	loadGlobalLockFrom(cfg)
-	loadPluginFrom(cfg)
+	if err := loadPluginFrom(cfg); err != nil {
+		return err
+	}
 	loadOtherFrom(cfg)
 	return nil
 }
@@ -133,6 +133,9 @@ var (
 	Actions ObjectStorage = uninitializedStorage
 	// Actions Artifacts represents actions artifacts storage
 	ActionsArtifacts ObjectStorage = uninitializedStorage
+
+	// Plugins represents plugins storage, each plugin uses its own path prefix
+	Plugins ObjectStorage = uninitializedStorage
 )
 
 // Init init the storage
@@ -145,6 +148,7 @@ func Init() error {
 		initRepoArchives,
 		initPackages,
 		initActions,
+		initPlugins,
 	} {
 		if err := f(); err != nil {
 			return err
@@ -228,3 +232,13 @@ func initActions() (err error) {
 	ActionsArtifacts, err = NewStorage(setting.Actions.ArtifactStorage.Type, setting.Actions.ArtifactStorage)
 	return err
 }
+
+func initPlugins() (err error) {
+	if !setting.PluginEnabled {
+		Plugins = discardStorage("Plugins aren't enabled")
+		return nil
+	}
+	log.Info("Initialising Plugins storage with type: %s", setting.PluginStorage.Type)
+	Plugins, err = NewStorage(setting.PluginStorage.Type, setting.PluginStorage)
+	return err
+}
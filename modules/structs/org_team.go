@@ -25,6 +25,8 @@ type Team struct {
 	UnitsMap map[string]string `json:"units_map"`
 	// Whether the team can create repositories in the organization
 	CanCreateOrgRepo bool `json:"can_create_org_repo"`
+	// Whether the team can manage the licenses owned by the organization
+	CanManageLicenses bool `json:"can_manage_licenses"`
 }
 
 // CreateTeamOption options for creating a team
@@ -44,6 +46,8 @@ type CreateTeamOption struct {
 	UnitsMap map[string]string `json:"units_map"`
 	// Whether the team can create repositories in the organization
 	CanCreateOrgRepo bool `json:"can_create_org_repo"`
+	// Whether the team can manage the licenses owned by the organization
+	CanManageLicenses bool `json:"can_manage_licenses"`
 }
 
 // EditTeamOption options for editing a team
@@ -63,4 +67,6 @@ type EditTeamOption struct {
 	UnitsMap map[string]string `json:"units_map"`
 	// Whether the team can create repositories in the organization
 	CanCreateOrgRepo *bool `json:"can_create_org_repo"`
+	// Whether the team can manage the licenses owned by the organization
+	CanManageLicenses *bool `json:"can_manage_licenses"`
 }
@@ -0,0 +1,89 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// Plugin represents an installed plugin
+type Plugin struct {
+	// The plugin identifier declared in plugin.json
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Author      string `json:"author"`
+	Homepage    string `json:"homepage"`
+	License     string `json:"license"`
+	Enabled     bool   `json:"enabled"`
+	// Where the plugin was installed from: marketplace, upload or release:{owner}/{repo}@{tag}
+	Source string `json:"source"`
+	// Fingerprint of the trusted publisher key that signed the package, empty if unsigned
+	Signer string `json:"signer"`
+	// Permissions approved by an administrator
+	Permissions []string `json:"permissions"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// InstallPluginFromReleaseOption options for installing a plugin from a repository release
+type InstallPluginFromReleaseOption struct {
+	// required: true
+	Owner string `json:"owner" binding:"Required"`
+	// required: true
+	Repo string `json:"repo" binding:"Required"`
+	// required: true
+	TagName string `json:"tag_name" binding:"Required"`
+	// Name of the release attachment, defaults to the only .zip attachment of the release
+	Attachment string `json:"attachment"`
+}
+
+// InstallPluginOption options for installing a plugin from the marketplace
+type InstallPluginOption struct {
+	// required: true
+	ID string `json:"id" binding:"Required"`
+	// Version to install, defaults to the latest version
+	Version string `json:"version"`
+}
+
+// PluginConfig represents the configuration of a plugin
+type PluginConfig struct {
+	// Values of the non-secret fields, including the defaults of the config schema
+	Config map[string]any `json:"config"`
+	// Names of the secret fields which have a value, secret values are never returned
+	SecretsSet []string `json:"secrets_set"`
+}
+
+// EditPluginConfigOption options for editing the configuration of a plugin
+type EditPluginConfigOption struct {
+	// Fields to change, fields not present are kept and fields set to null are cleared
+	// required: true
+	Config map[string]any `json:"config" binding:"Required"`
+}
+
+// PluginConfigChange represents the change of one configuration field, secret values are redacted
+type PluginConfigChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// PluginAudit represents an entry of the plugin audit log
+type PluginAudit struct {
+	ID       int64  `json:"id"`
+	PluginID string `json:"plugin_id"`
+	// install, uninstall, enable, disable, quarantine, upgrade, rollback, config or approve_permissions
+	Action string `json:"action"`
+	// ID of the user who performed the action, 0 for the system (command line or automatic quarantine)
+	DoerID int64 `json:"doer_id"`
+	// Name of the user at the time of the action
+	DoerName   string                `json:"doer_name"`
+	OldVersion string                `json:"old_version"`
+	NewVersion string                `json:"new_version"`
+	ConfigDiff []*PluginConfigChange `json:"config_diff"`
+	Detail     string                `json:"detail"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -41,6 +41,7 @@ func NewFuncMap() template.FuncMap {
 		"SanitizeHTML": SanitizeHTML,
 		"URLJoin":      util.URLJoin,
 		"DotEscape":    dotEscape,
+		"RenderSlot":   renderSlot,
 
 		"PathEscape":         url.PathEscape,
 		"PathEscapeSegments": util.PathEscapeSegments,
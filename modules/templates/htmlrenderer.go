@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html/template"
 	"io"
 	"net/http"
 	"path/filepath"
@@ -18,6 +19,7 @@ import (
 	"sync"
 	"sync/atomic"
 	texttemplate "text/template"
+	"text/template/parse"
 
 	"code.gitea.io/gitea/modules/assetfs"
 	"code.gitea.io/gitea/modules/graceful"
@@ -68,13 +70,26 @@ func (h *HTMLRender) TemplateLookup(name string, ctx context.Context) (TemplateE
 }
 
 func (h *HTMLRender) CompileTemplates() error {
+	templateOverlaysMu.Lock()
+	defer templateOverlaysMu.Unlock()
+
+	trees, err := parseCoreTemplates()
+	if err != nil || trees == nil {
+		return err
+	}
+	coreTemplateTrees = trees
+	h.templates.Store(buildTemplates())
+	return nil
+}
+
+// parseCoreTemplates parses the builtin/custom templates, the parse trees are kept so that the templates can be rebuilt with the overlays without parsing them again
+func parseCoreTemplates() (map[string]*parse.Tree, error) {
 	assets := AssetFS()
 	extSuffix := ".tmpl"
-	tmpls := scopedtmpl.NewScopedTemplate()
-	tmpls.Funcs(NewFuncMap())
+	tmpls := template.New("").Funcs(NewFuncMap())
 	files, err := ListWebTemplateAssetNames(assets)
 	if err != nil {
-		return nil
+		return nil, nil
 	}
 	for _, file := range files {
 		if !strings.HasSuffix(file, extSuffix) {
@@ -84,15 +99,13 @@ func (h *HTMLRender) CompileTemplates() error {
 		tmpl := tmpls.New(filepath.ToSlash(name))
 		buf, err := assets.ReadFile(file)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if _, err = tmpl.Parse(string(buf)); err != nil {
-			return err
+			return nil, err
 		}
 	}
-	tmpls.Freeze()
-	h.templates.Store(tmpls)
-	return nil
+	return templateTrees(tmpls), nil
 }
 
 // HTMLRenderer init once and returns the globally shared html renderer
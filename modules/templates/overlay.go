@@ -0,0 +1,119 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"text/template/parse"
+
+	"code.gitea.io/gitea/modules/assetfs"
+	"code.gitea.io/gitea/modules/templates/scopedtmpl"
+)
+
+var (
+	templateOverlaysMu sync.Mutex
+	coreTemplateTrees  map[string]*parse.Tree                // the parsed builtin/custom templates, nil before the renderer is initialized
+	templateOverlays   = map[string]map[string]*parse.Tree{} // namespace => name => parsed overlay template
+)
+
+// AddTemplateOverlay makes the "*.tmpl" files in dir available as "{namespace}/{name}" templates.
+// Only the files in dir are parsed, the overlay is not added if any of them fails to parse.
+func AddTemplateOverlay(namespace, dir string) error {
+	namespace = strings.Trim(namespace, "/")
+	if namespace == "" {
+		return fmt.Errorf("invalid template namespace for %q", dir)
+	}
+	trees, err := parseTemplateOverlay(namespace, dir)
+	if err != nil {
+		return err
+	}
+
+	templateOverlaysMu.Lock()
+	defer templateOverlaysMu.Unlock()
+	templateOverlays[namespace] = trees
+	reloadTemplateOverlays()
+	return nil
+}
+
+// RemoveTemplateOverlay removes the templates added by AddTemplateOverlay
+func RemoveTemplateOverlay(namespace string) {
+	namespace = strings.Trim(namespace, "/")
+
+	templateOverlaysMu.Lock()
+	defer templateOverlaysMu.Unlock()
+
+	if _, ok := templateOverlays[namespace]; !ok {
+		return
+	}
+	delete(templateOverlays, namespace)
+	reloadTemplateOverlays()
+}
+
+// reloadTemplateOverlays replaces the renderer's templates with the parsed templates and the current overlays if it has been initialized.
+// The caller must hold templateOverlaysMu
+func reloadTemplateOverlays() {
+	if htmlRender != nil && coreTemplateTrees != nil {
+		htmlRender.templates.Store(buildTemplates())
+	}
+}
+
+// buildTemplates builds the template set from copies of the parsed core and overlay templates, the caller must hold templateOverlaysMu.
+// The parse trees are copied because the template set escapes them in place when they are executed.
+func buildTemplates() *scopedtmpl.ScopedTemplate {
+	tmpls := scopedtmpl.NewScopedTemplate()
+	tmpls.Funcs(NewFuncMap())
+	addTrees := func(trees map[string]*parse.Tree) {
+		for name, tree := range trees {
+			// AddParseTree only fails if the template has been executed, the new set has not
+			_, _ = tmpls.New(name).AddParseTree(name, tree.Copy())
+		}
+	}
+	addTrees(coreTemplateTrees)
+	for _, namespace := range slices.Sorted(maps.Keys(templateOverlays)) {
+		addTrees(templateOverlays[namespace])
+	}
+	tmpls.Freeze()
+	return tmpls
+}
+
+// parseTemplateOverlay parses the "*.tmpl" files in dir as "{namespace}/{name}" templates
+func parseTemplateOverlay(namespace, dir string) (map[string]*parse.Tree, error) {
+	assets := assetfs.Layered(assetfs.Local(namespace, dir))
+	files, err := assets.ListAllFiles(".", true)
+	if err != nil {
+		return nil, fmt.Errorf("list templates of %q: %w", namespace, err)
+	}
+	tmpls := template.New("").Funcs(NewFuncMap())
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".tmpl") {
+			continue
+		}
+		buf, err := assets.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		name := path.Join(namespace, strings.TrimSuffix(file, ".tmpl"))
+		if _, err = tmpls.New(name).Parse(string(buf)); err != nil {
+			return nil, fmt.Errorf("template %q: %w", name, err)
+		}
+	}
+	return templateTrees(tmpls), nil
+}
+
+// templateTrees returns the parse trees of the parsed templates (including the "define" templates) associated with tmpls
+func templateTrees(tmpls *template.Template) map[string]*parse.Tree {
+	trees := map[string]*parse.Tree{}
+	for _, tmpl := range tmpls.Templates() {
+		if tmpl.Tree != nil {
+			trees[tmpl.Name()] = tmpl.Tree
+		}
+	}
+	return trees
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package templates
+
+import (
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template/parse"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateOverlays(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "page.tmpl"), []byte(`{{template "base/head"}}page {{.}}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "item.tmpl"), []byte(`item`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "readme.md"), []byte(`{{`), 0o644))
+
+	core := template.Must(template.New("base/head").Parse(`<head>`))
+	oldCore, oldOverlays := coreTemplateTrees, templateOverlays
+	defer func() { coreTemplateTrees, templateOverlays = oldCore, oldOverlays }()
+	coreTemplateTrees = templateTrees(core)
+
+	trees, err := parseTemplateOverlay("plugins/test", dir)
+	require.NoError(t, err)
+	templateOverlays = map[string]map[string]*parse.Tree{"plugins/test": trees}
+
+	execute := func(name string) string {
+		executor, err := buildTemplates().Executor(name, nil)
+		require.NoError(t, err, name)
+		var sb strings.Builder
+		require.NoError(t, executor.Execute(&sb, 1))
+		return sb.String()
+	}
+	assert.Equal(t, "<head>page 1", execute("plugins/test/page"))
+	assert.Equal(t, "item", execute("plugins/test/sub/item"))
+	// executing a built set must not change the kept parse trees
+	assert.Equal(t, "<head>page 1", execute("plugins/test/page"))
+	assert.Equal(t, "<head>", execute("base/head"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.tmpl"), []byte(`{{if}}`), 0o644))
+	_, err = parseTemplateOverlay("plugins/test", dir)
+	assert.ErrorContains(t, err, "plugins/test/broken")
+}
@@ -0,0 +1,28 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package templates
+
+import (
+	"context"
+	"html/template"
+	"sync/atomic"
+)
+
+// SlotRenderer renders the extension content of a named UI slot, data is the page's template data
+type SlotRenderer func(ctx context.Context, slot string, data map[string]any) template.HTML
+
+var slotRenderer atomic.Pointer[SlotRenderer]
+
+// SetSlotRenderer sets the renderer used by the "RenderSlot" template function
+func SetSlotRenderer(r SlotRenderer) {
+	slotRenderer.Store(&r)
+}
+
+// renderSlot is used in templates: {{RenderSlot ctx "repo.header.tab" $}}
+func renderSlot(ctx context.Context, slot string, data map[string]any) template.HTML {
+	if r := slotRenderer.Load(); r != nil {
+		return (*r)(ctx, slot, data)
+	}
+	return ""
+}
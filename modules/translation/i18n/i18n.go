@@ -33,6 +33,12 @@ type LocaleStore interface {
 	HasLang(langName string) bool
 	// AddLocaleByIni adds a new language to the store
 	AddLocaleByJSON(langName, langDesc string, source, moreSource []byte) error
+	// MergeLocaleByINI merges INI messages into an added language, existing messages are kept
+	MergeLocaleByINI(langName string, source []byte) error
+	// SetLocaleOverlay sets the INI messages (lang => INI content) of a named overlay, it can be called at any time
+	SetLocaleOverlay(name string, sources map[string][]byte) error
+	// RemoveLocaleOverlay removes the messages added by SetLocaleOverlay
+	RemoveLocaleOverlay(name string)
 }
 
 // ResetDefaultLocales resets the current default locales
@@ -153,3 +153,48 @@ func TestLocaleWithTemplate(t *testing.T) {
 		assert.Equal(t, c.want, buf.String())
 	}
 }
+
+func TestMergeLocaleByINI(t *testing.T) {
+	ls := NewLocaleStore()
+	assert.NoError(t, ls.AddLocaleByJSON("lang1", "Lang1", []byte(`{"admin.title": "Admin"}`), nil))
+	assert.NoError(t, ls.MergeLocaleByINI("lang1", []byte("top = Top\n[admin]\ntitle = Changed\nplugins.title = Plugins\n")))
+	assert.Error(t, ls.MergeLocaleByINI("none", []byte("k = v")))
+
+	lang1, _ := ls.Locale("lang1")
+	assert.Equal(t, "Top", lang1.TrString("top"))
+	assert.Equal(t, "Admin", lang1.TrString("admin.title"))
+	assert.Equal(t, "Plugins", lang1.TrString("admin.plugins.title"))
+}
+
+func TestLocaleOverlay(t *testing.T) {
+	ls := NewLocaleStore()
+	assert.NoError(t, ls.AddLocaleByJSON("lang1", "Lang1", []byte(`{"admin.title": "Admin"}`), nil))
+	assert.NoError(t, ls.AddLocaleByJSON("lang2", "Lang2", nil, nil))
+	ls.SetDefaultLang("lang1")
+	lang1, _ := ls.Locale("lang1")
+	lang2, _ := ls.Locale("lang2")
+
+	assert.NoError(t, ls.SetLocaleOverlay("b", map[string][]byte{"lang1": []byte("[admin]\ntitle = Changed\nplugin = B\n")}))
+	assert.NoError(t, ls.SetLocaleOverlay("a", map[string][]byte{
+		"lang1": []byte("[admin]\nplugin = A1\n"),
+		"lang2": []byte("[admin]\nplugin = A2\nother = Other\n"),
+	}))
+	assert.Error(t, ls.SetLocaleOverlay("c", map[string][]byte{"lang1": []byte("[admin")}))
+
+	// existing messages are kept, the overlay sorted first wins
+	assert.Equal(t, "Admin", lang1.TrString("admin.title"))
+	assert.Equal(t, "A1", lang1.TrString("admin.plugin"))
+	assert.Equal(t, "A2", lang2.TrString("admin.plugin"))
+	assert.Equal(t, "Admin", lang2.TrString("admin.title"))
+	assert.True(t, lang2.HasKey("admin.other"))
+	assert.False(t, lang1.HasKey("admin.other"))
+
+	// replacing or removing an overlay does not affect the others
+	assert.NoError(t, ls.SetLocaleOverlay("a", map[string][]byte{"lang2": []byte("[admin]\nplugin = A2\n")}))
+	assert.Equal(t, "B", lang1.TrString("admin.plugin"))
+	assert.False(t, lang2.HasKey("admin.other"))
+	ls.RemoveLocaleOverlay("a")
+	assert.Equal(t, "B", lang2.TrString("admin.plugin"))
+	ls.RemoveLocaleOverlay("b")
+	assert.Equal(t, "admin.plugin", lang1.TrString("admin.plugin"))
+}
@@ -8,10 +8,13 @@ import (
 	"fmt"
 	"html"
 	"html/template"
+	"maps"
 	"slices"
+	"sync/atomic"
 
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 )
 
 // This file implements the static LocaleStore that will not watch for changes
@@ -33,6 +36,15 @@ type localeStore struct {
 	trKeyToIdxMap map[string]int
 
 	defaultLang string
+
+	// overlays can be changed at any time, each change replaces the whole snapshot
+	overlays atomic.Pointer[localeOverlays]
+}
+
+// localeOverlays is an immutable snapshot of the overlay messages
+type localeOverlays struct {
+	byName map[string]map[string]map[string]string // overlay name => lang => trKey => message
+	merged map[string]map[string]string            // lang => trKey => message, the overlay sorted first wins
 }
 
 // NewLocaleStore creates a static locale store
@@ -95,6 +107,111 @@ func (store *localeStore) AddLocaleByJSON(langName, langDesc string, source, mor
 	return nil
 }
 
+// MergeLocaleByINI merges INI messages into an added language, keys are "section.key" ("key" for the default section).
+// Messages which already exist in the language are kept, so the merged files can only add new messages.
+func (store *localeStore) MergeLocaleByINI(langName string, source []byte) error {
+	l, ok := store.localeMap[langName]
+	if !ok {
+		return fmt.Errorf("lang %q has not been added", langName)
+	}
+
+	messages, err := parseLocaleINI(source)
+	if err != nil {
+		return err
+	}
+	for trKey, msg := range messages {
+		idx, ok := store.trKeyToIdxMap[trKey]
+		if !ok {
+			idx = len(store.trKeyToIdxMap)
+			store.trKeyToIdxMap[trKey] = idx
+		}
+		if _, ok := l.idxToMsgMap[idx]; !ok {
+			l.idxToMsgMap[idx] = msg
+		}
+	}
+	return nil
+}
+
+// parseLocaleINI parses INI messages, keys are "section.key" ("key" for the default section)
+func parseLocaleINI(source []byte) (map[string]string, error) {
+	cfg, err := setting.NewConfigProviderForLocale(source)
+	if err != nil {
+		return nil, err
+	}
+	messages := map[string]string{}
+	for _, section := range cfg.Sections() {
+		prefix := ""
+		if name := section.Name(); name != "" && name != "DEFAULT" {
+			prefix = name + "."
+		}
+		for _, key := range section.Keys() {
+			messages[prefix+key.Name()] = key.Value()
+		}
+	}
+	return messages, nil
+}
+
+// SetLocaleOverlay sets the INI messages (lang => INI content) of a named overlay, replacing its previous messages.
+// Overlay messages are only used when the key has no message in the added languages, so an overlay can only add new messages.
+// Only the changed overlay is parsed, the messages of the languages and the other overlays are kept as they are.
+func (store *localeStore) SetLocaleOverlay(name string, sources map[string][]byte) error {
+	langs := make(map[string]map[string]string, len(sources))
+	for lang, source := range sources {
+		messages, err := parseLocaleINI(source)
+		if err != nil {
+			return fmt.Errorf("lang %q: %w", lang, err)
+		}
+		langs[lang] = messages
+	}
+	store.updateOverlays(func(byName map[string]map[string]map[string]string) { byName[name] = langs })
+	return nil
+}
+
+// RemoveLocaleOverlay removes the messages added by SetLocaleOverlay
+func (store *localeStore) RemoveLocaleOverlay(name string) {
+	store.updateOverlays(func(byName map[string]map[string]map[string]string) { delete(byName, name) })
+}
+
+// updateOverlays replaces the overlay snapshot, the callers must not change the overlays concurrently
+func (store *localeStore) updateOverlays(change func(byName map[string]map[string]map[string]string)) {
+	byName := map[string]map[string]map[string]string{}
+	if old := store.overlays.Load(); old != nil {
+		maps.Copy(byName, old.byName)
+	}
+	change(byName)
+
+	merged := map[string]map[string]string{}
+	for _, name := range slices.Sorted(maps.Keys(byName)) {
+		for lang, messages := range byName[name] {
+			if merged[lang] == nil {
+				merged[lang] = map[string]string{}
+			}
+			for trKey, msg := range messages {
+				if _, ok := merged[lang][trKey]; !ok {
+					merged[lang][trKey] = msg
+				}
+			}
+		}
+	}
+	store.overlays.Store(&localeOverlays{byName: byName, merged: merged})
+}
+
+// message returns the message of the key in the language, the language's own messages take precedence over the overlays
+func (store *localeStore) message(lang, trKey string) (string, bool) {
+	if idx, ok := store.trKeyToIdxMap[trKey]; ok {
+		if l, ok := store.localeMap[lang]; ok {
+			if msg, ok := l.idxToMsgMap[idx]; ok {
+				return msg, true
+			}
+		}
+	}
+	if overlays := store.overlays.Load(); overlays != nil {
+		msg, ok := overlays.merged[lang][trKey]
+		return msg, ok
+	}
+	return "", false
+}
+
 func (store *localeStore) HasLang(langName string) bool {
 	_, ok := store.localeMap[langName]
 	return ok
@@ -128,16 +245,9 @@ func (store *localeStore) Close() error {
 }
 
 func (l *locale) TrString(trKey string, trArgs ...any) string {
-	var format string
-	idx, ok := l.store.trKeyToIdxMap[trKey]
-	if ok {
-		format = l.idxToMsgMap[idx]
-		if format == "" { // missing translation in this locale, fallback to default
-			if def, ok := l.store.localeMap[l.store.defaultLang]; ok {
-				// try to use default locale's translation
-				format = def.idxToMsgMap[idx]
-			}
-		}
+	format, _ := l.store.message(l.langName, trKey)
+	if format == "" { // missing translation in this locale, fallback to default
+		format, _ = l.store.message(l.store.defaultLang, trKey)
 	}
 	if format == "" { // still missing, use the key itself
 		format = html.EscapeString(trKey)
@@ -168,10 +278,6 @@ func (l *locale) TrHTML(trKey string, trArgs ...any) template.HTML {
 
 // HasKey returns whether a key is present in this locale or not
 func (l *locale) HasKey(trKey string) bool {
-	idx, ok := l.store.trKeyToIdxMap[trKey]
-	if !ok {
-		return false
-	}
-	_, ok = l.idxToMsgMap[idx]
+	_, ok := l.store.message(l.langName, trKey)
 	return ok
 }
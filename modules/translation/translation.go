@@ -5,7 +5,12 @@ package translation
 
 import (
 	"context"
+	"errors"
 	"html/template"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -49,6 +54,8 @@ var (
 
 	matcher       language.Matcher
 	supportedTags []language.Tag
+
+	localeOverlays = map[string]string{} // name => dir, see AddLocaleOverlay
 )
 
 // AllLangs returns all supported languages sorted by name
@@ -61,58 +68,11 @@ func InitLocales(ctx context.Context) {
 	if lock != nil {
 		lock.Lock()
 		defer lock.Unlock()
-	} else if !setting.IsProd && lock == nil {
+	} else {
+		// locale overlays can be added or removed at runtime, so the lock is also needed in production
 		lock = &sync.RWMutex{}
 	}
 
-	refreshLocales := func() {
-		i18n.ResetDefaultLocales()
-		localeNames, err := options.AssetFS().ListFiles("locale", true)
-		if err != nil {
-			log.Fatal("Failed to list locale files: %v", err)
-		}
-
-		localeData := make(map[string][]byte, len(localeNames))
-		for _, name := range localeNames {
-			if !strings.HasPrefix(name, "locale_") || !strings.HasSuffix(name, ".json") {
-				continue
-			}
-			localeData[name], err = options.Locale(name)
-			if err != nil {
-				log.Fatal("Failed to load %s locale file. %v", name, err)
-			}
-		}
-
-		supportedTags = make([]language.Tag, len(setting.Langs))
-		for i, lang := range setting.Langs {
-			supportedTags[i] = language.Raw.Make(lang)
-		}
-
-		matcher = language.NewMatcher(supportedTags)
-		for i := range setting.Names {
-			var localeDataBase []byte
-			if i == 0 && setting.Langs[0] != "en-US" {
-				// Only en-US has complete translations. When use other language as default, the en-US should still be used as fallback.
-				localeDataBase = localeData["locale_en-US.json"]
-				if localeDataBase == nil {
-					log.Fatal("Failed to load locale_en-US.json file.")
-				}
-			}
-
-			key := "locale_" + setting.Langs[i] + ".json"
-			if err = i18n.DefaultLocales.AddLocaleByJSON(setting.Langs[i], setting.Names[i], localeDataBase, localeData[key]); err != nil {
-				log.Error("Failed to set messages to %s: %v", setting.Langs[i], err)
-			}
-		}
-		if len(setting.Langs) != 0 {
-			defaultLangName := setting.Langs[0]
-			if defaultLangName != "en-US" {
-				log.Info("Use the first locale (%s) in LANGS setting option as default", defaultLangName)
-			}
-			i18n.DefaultLocales.SetDefaultLang(defaultLangName)
-		}
-	}
-
 	refreshLocales()
 
 	langs, descs := i18n.DefaultLocales.ListLangNameDesc()
@@ -138,6 +98,123 @@ func InitLocales(ctx context.Context) {
 	}
 }
 
+func refreshLocales() {
+	i18n.ResetDefaultLocales()
+	localeNames, err := options.AssetFS().ListFiles("locale", true)
+	if err != nil {
+		log.Fatal("Failed to list locale files: %v", err)
+	}
+
+	localeData := make(map[string][]byte, len(localeNames))
+	for _, name := range localeNames {
+		if !strings.HasPrefix(name, "locale_") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		localeData[name], err = options.Locale(name)
+		if err != nil {
+			log.Fatal("Failed to load %s locale file. %v", name, err)
+		}
+	}
+
+	supportedTags = make([]language.Tag, len(setting.Langs))
+	for i, lang := range setting.Langs {
+		supportedTags[i] = language.Raw.Make(lang)
+	}
+
+	matcher = language.NewMatcher(supportedTags)
+	for i := range setting.Names {
+		var localeDataBase []byte
+		if i == 0 && setting.Langs[0] != "en-US" {
+			// Only en-US has complete translations. When use other language as default, the en-US should still be used as fallback.
+			localeDataBase = localeData["locale_en-US.json"]
+			if localeDataBase == nil {
+				log.Fatal("Failed to load locale_en-US.json file.")
+			}
+		}
+
+		key := "locale_" + setting.Langs[i] + ".json"
+		if err = i18n.DefaultLocales.AddLocaleByJSON(setting.Langs[i], setting.Names[i], localeDataBase, localeData[key]); err != nil {
+			log.Error("Failed to set messages to %s: %v", setting.Langs[i], err)
+		}
+		mergeLocaleINIs(setting.Langs[i], localeNames)
+	}
+	for _, name := range slices.Sorted(maps.Keys(localeOverlays)) {
+		if err := i18n.DefaultLocales.SetLocaleOverlay(name, readLocaleOverlay(localeOverlays[name])); err != nil {
+			log.Error("Failed to load locale overlay %s: %v", name, err)
+		}
+	}
+	if len(setting.Langs) != 0 {
+		defaultLangName := setting.Langs[0]
+		if defaultLangName != "en-US" {
+			log.Info("Use the first locale (%s) in LANGS setting option as default", defaultLangName)
+		}
+		i18n.DefaultLocales.SetDefaultLang(defaultLangName)
+	}
+}
+
+// mergeLocaleINIs merges the extra "locale_{lang}_*.ini" files into the language
+func mergeLocaleINIs(lang string, localeNames []string) {
+	prefix := "locale_" + lang + "_"
+	for _, name := range localeNames {
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".ini") {
+			continue
+		}
+		data, err := options.Locale(name)
+		if err == nil {
+			err = i18n.DefaultLocales.MergeLocaleByINI(lang, data)
+		}
+		if err != nil {
+			log.Error("Failed to merge %s locale file: %v", name, err)
+		}
+	}
+}
+
+// readLocaleOverlay reads the "locale_{lang}.ini" files of the supported languages in dir
+func readLocaleOverlay(dir string) map[string][]byte {
+	sources := map[string][]byte{}
+	for _, lang := range setting.Langs {
+		file := filepath.Join(dir, "locale_"+lang+".ini")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				log.Error("Failed to read locale file %s: %v", file, err)
+			}
+			continue
+		}
+		sources[lang] = data
+	}
+	return sources
+}
+
+// AddLocaleOverlay adds the messages of the "locale_{lang}.ini" files in dir to the loaded languages.
+// Existing messages are not overridden, so an overlay can only add new messages.
+// Only the files in dir are read, the other locales are not reloaded.
+func AddLocaleOverlay(name, dir string) {
+	if lock == nil { // locales are not initialized yet, InitLocales will load the overlays
+		localeOverlays[name] = dir
+		return
+	}
+	sources := readLocaleOverlay(dir)
+	lock.Lock()
+	defer lock.Unlock()
+	localeOverlays[name] = dir
+	if err := i18n.DefaultLocales.SetLocaleOverlay(name, sources); err != nil {
+		log.Error("Failed to load locale overlay %s: %v", name, err)
+	}
+}
+
+// RemoveLocaleOverlay removes the messages added by AddLocaleOverlay
+func RemoveLocaleOverlay(name string) {
+	if lock == nil {
+		delete(localeOverlays, name)
+		return
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	delete(localeOverlays, name)
+	i18n.DefaultLocales.RemoveLocaleOverlay(name)
+}
+
 // Match matches accept languages
 func Match(tags ...language.Tag) language.Tag {
 	_, i, _ := matcher.Match(tags...)
@@ -4,18 +4,38 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"html/template"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"code.gitea.io/gitea/models/db"
+	license_model "code.gitea.io/gitea/models/license"
 	"code.gitea.io/gitea/models/plugin"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/htmlutil"
+	"code.gitea.io/gitea/modules/pluginsdk"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	license_service "code.gitea.io/gitea/services/license"
 	"github.com/go-chi/chi/v5"
 )
 
 // Plugin 插件实例（必须导出）
-var Plugin LicenseManagerPlugin
+var Plugin = LicenseManagerPlugin{
+	Base: pluginsdk.Base{
+		TemplatePath: "./plugins/installed/license-manager/templates",
+		AssetsPath:   "./plugins/installed/license-manager/assets",
+		LocalePath:   "./plugins/installed/license-manager/locales",
+	},
+}
 
-// LicenseManagerPlugin 授权管理插件
+// LicenseManagerPlugin 授权管理插件，配置和未用到的 IPlugin 方法由 pluginsdk.Base 提供
 type LicenseManagerPlugin struct {
-	config map[string]interface{}
+	pluginsdk.Base
 }
 
 // Info 获取插件信息
@@ -34,54 +54,95 @@ func (p *LicenseManagerPlugin) Info() *plugin.PluginInfo {
 			"api.create",
 			"ui.modify",
 		},
-		HasRoutes:    true,
+		Scopes: []*plugin.APIScope{{
+			Name:        "license",
+			Description: "授权与设备管理",
+			Paths:       []string{"/api/v1/license", "/api/v1/user/license", "/api/v1/orgs/{org}/license"},
+		}},
 		HasAPI:       true,
 		HasModels:    true,
 		HasTemplates: true,
 	}
 }
 
-// Init 初始化插件
-func (p *LicenseManagerPlugin) Init() error {
-	// 初始化配置
-	p.config = map[string]interface{}{
-		"max_devices_per_user":     10,
-		"default_expiry_days":      365,
-		"allow_permanent_license":  true,
-	}
+// RegisterAPIRoutes 注册 API 路由
+func (p *LicenseManagerPlugin) RegisterAPIRoutes(r chi.Router) {
+	r.Route("/api/v1/license", func(r chi.Router) {
+		r.Post("/verify", p.Handle(p.verifyLicense))
+		r.Post("/register", p.Handle(p.registerDevice))
+		r.Get("/public-key", p.Handle(p.publicKey))
+		// 以下路由使用 API 密钥验证，供无法登录 Gitea 的客户端程序使用
+		r.Post("/validate", p.Handle(p.validateLicense))
+		r.Get("/revocations", p.Handle(p.revocations))
+		r.Post("/activate", p.Handle(p.activate))
+		r.Post("/heartbeat", p.Handle(p.heartbeat))
+		r.Post("/deactivate", p.Handle(p.deactivate))
+	})
 
-	// 初始化数据库表
-	// TODO: 注册数据库模型
+	r.Route("/api/v1/user/license", func(r chi.Router) {
+		p.registerLicenseAPIRoutes(r)
+		// 组织分配给当前用户的席位
+		r.Get("/seats", p.Handle(p.listAssignedLicenses))
+		r.Post("/seats/{id}/activate", p.Handle(p.activateSeat))
+		r.Post("/seats/{id}/deactivate", p.Handle(p.deactivateSeat))
+	})
 
-	return nil
+	// 组织的授权，路由与个人授权相同，另外可以把席位分配给组织成员
+	r.Route("/api/v1/orgs/{org}/license", func(r chi.Router) {
+		p.registerLicenseAPIRoutes(r)
+		r.Get("/licenses/{id}/assignments", p.Handle(p.listAssignments))
+		r.Post("/licenses/{id}/assignments", p.Handle(p.assignSeat))
+		r.Delete("/licenses/{id}/assignments/{username}", p.Handle(p.unassignSeat))
+	})
 }
 
-// RegisterRoutes 注册 Web 路由
-func (p *LicenseManagerPlugin) RegisterRoutes(r chi.Router) {
-	r.Route("/user/settings/license", func(r chi.Router) {
-		r.Get("/", p.licenseList)
-		r.Get("/new", p.licenseNew)
-		r.Post("/new", p.licenseCreate)
-		r.Get("/{id}/edit", p.licenseEdit)
-		r.Post("/{id}/edit", p.licenseUpdate)
-		r.Post("/{id}/delete", p.licenseDelete)
-		r.Post("/{id}/toggle", p.licenseToggle)
-	})
+// registerLicenseAPIRoutes 注册授权管理路由，个人和组织共用，授权所有者由 licenseOwner 确定
+func (p *LicenseManagerPlugin) registerLicenseAPIRoutes(r chi.Router) {
+	r.Get("/devices", p.Handle(p.listDevices))
+	r.Post("/devices", p.Handle(p.createDevice))
+	r.Delete("/devices/{id}", p.Handle(p.deleteDevice))
+	r.Get("/devices/{id}/token", p.Handle(p.deviceToken))
+	r.Post("/devices/toggle", p.Handle(p.toggleDevice))
+	r.Get("/api-keys", p.Handle(p.listAPIKeys))
+	r.Post("/api-keys", p.Handle(p.createAPIKey))
+	r.Delete("/api-keys/{id}", p.Handle(p.deleteAPIKey))
+	r.Get("/products", p.Handle(p.listProducts))
+	r.Post("/products", p.Handle(p.createProduct))
+	r.Delete("/products/{id}", p.Handle(p.deleteProduct))
+	r.Get("/products/{id}/editions", p.Handle(p.listEditions))
+	r.Post("/products/{id}/editions", p.Handle(p.createEdition))
+	r.Patch("/products/{id}/editions/{edition_id}", p.Handle(p.editEdition))
+	r.Delete("/products/{id}/editions/{edition_id}", p.Handle(p.deleteEdition))
+	r.Get("/licenses", p.Handle(p.listLicenses))
+	r.Post("/licenses", p.Handle(p.createLicense))
+	r.Delete("/licenses/{id}", p.Handle(p.deleteLicense))
+	r.Post("/licenses/{id}/toggle", p.Handle(p.toggleLicense))
+	r.Get("/licenses/{id}/activations", p.Handle(p.listActivations))
+	r.Delete("/licenses/{id}/activations/{activation_id}", p.Handle(p.releaseActivation))
 }
 
-// RegisterAPIRoutes 注册 API 路由
-func (p *LicenseManagerPlugin) RegisterAPIRoutes(r chi.Router) {
-	r.Route("/api/v1/license", func(r chi.Router) {
-		r.Post("/verify", p.verifyLicense)
-		r.Post("/register", p.registerDevice)
+// RegisterSlots 在用户设置菜单中添加授权管理入口
+func (p *LicenseManagerPlugin) RegisterSlots(r plugin.SlotRegistry) {
+	r.RegisterSlot(plugin.SlotUserSettingsMenu, func(_ context.Context, sc *plugin.SlotContext) (template.HTML, error) {
+		link := setting.AppSubURL + "/user/settings/license"
+		return htmlutil.HTMLFormat(`<a class="%sitem" href="%s">授权管理</a>`,
+			util.Iif(strings.HasPrefix(sc.Link, link), "active ", ""), link), nil
 	})
+}
 
-	r.Route("/api/v1/user/license", func(r chi.Router) {
-		r.Get("/devices", p.listDevices)
-		r.Post("/devices", p.createDevice)
-		r.Delete("/devices/{id}", p.deleteDevice)
-		r.Post("/devices/toggle", p.toggleDevice)
-	})
+// CronTasks 定期清理过期的授权和浮动授权的过期租约
+func (p *LicenseManagerPlugin) CronTasks() []*plugin.CronTask {
+	return []*plugin.CronTask{{
+		Name:     "expire_licenses",
+		Title:    "清理过期授权",
+		Schedule: "@every 1h",
+		Run:      p.expireLicenses,
+	}, {
+		Name:     "expire_leases",
+		Title:    "回收浮动授权的过期租约",
+		Schedule: "@every 1m",
+		Run:      p.expireLeases,
+	}}
 }
 
 // RegisterModels 注册数据库模型
@@ -91,101 +152,896 @@ func (p *LicenseManagerPlugin) RegisterModels() []interface{} {
 	}
 }
 
-// GetTemplatePath 获取模板路径
-func (p *LicenseManagerPlugin) GetTemplatePath() string {
-	return "./plugins/installed/license-manager/templates"
+// verifyLicense 验证当前用户设备的机器码和授权码，授权有效时返回授权的功能和新的离线授权令牌
+func (p *LicenseManagerPlugin) verifyLicense(ctx *pluginsdk.Context) {
+	if !ctx.IsSigned() {
+		ctx.APIError(http.StatusUnauthorized, "需要登录")
+		return
+	}
+	var form struct {
+		MachineCode string `json:"machine_code"`
+		LicenseKey  string `json:"license_key"`
+	}
+	if err := ctx.DecodeJSON(&form); err != nil || form.MachineCode == "" || form.LicenseKey == "" {
+		ctx.APIError(http.StatusBadRequest, "machine_code and license_key are required")
+		return
+	}
+
+	valid, device, err := license_service.VerifyLicense(ctx, ctx.Doer.ID, form.MachineCode, form.LicenseKey)
+	if err != nil {
+		ctx.ServerError("VerifyLicense", err)
+		return
+	}
+	resp := map[string]any{
+		"is_authorized": valid,
+		"server_time":   time.Now(),
+	}
+	switch {
+	case device == nil:
+		resp["message"] = "设备未授权"
+	case !valid && device.LicenseKey != license_service.NormalizeLicenseKey(form.LicenseKey):
+		resp["message"] = "授权码无效"
+	case !valid && !device.IsEnabled:
+		resp["message"] = "授权已被禁用"
+	case !valid:
+		resp["message"] = "授权已过期"
+	default:
+		entitlements, err := license_service.GetEntitlements(ctx, device)
+		if err != nil {
+			ctx.ServerError("GetEntitlements", err)
+			return
+		}
+		// 每次在线验证都刷新离线授权令牌
+		token, err := license_service.IssueToken(device, ctx.Doer.Name, entitlements)
+		if err != nil {
+			ctx.ServerError("IssueToken", err)
+			return
+		}
+		resp["message"] = "授权验证成功"
+		resp["token"] = token
+		resp["product"], resp["edition"], resp["features"] = entitlements.Product, entitlements.Edition, entitlements.Features
+		if !device.ExpiryDate.IsZero() {
+			resp["expiry_date"] = device.ExpiryDate.AsTime()
+		}
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// registerDevice 客户端上报机器码，授权需要用户在授权管理中为该机器码创建
+func (p *LicenseManagerPlugin) registerDevice(ctx *pluginsdk.Context) {
+	if !ctx.IsSigned() {
+		ctx.APIError(http.StatusUnauthorized, "需要登录")
+		return
+	}
+	var form struct {
+		MachineCode string `json:"machine_code"`
+	}
+	if err := ctx.DecodeJSON(&form); err != nil || form.MachineCode == "" {
+		ctx.APIError(http.StatusBadRequest, "machine_code is required")
+		return
+	}
+
+	_, err := license_model.GetDeviceByUserAndMachineCode(ctx, ctx.Doer.ID, form.MachineCode)
+	if err != nil && !license_model.IsErrDeviceNotExist(err) {
+		ctx.ServerError("GetDeviceByUserAndMachineCode", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]any{
+		"registered":   err == nil,
+		"machine_code": form.MachineCode,
+		"message":      util.Iif(err == nil, "设备已注册，请在个人设置中查看授权码", "请在个人设置的授权管理中为此机器码创建授权"),
+	})
+}
+
+// listDevices 分页列出授权设备，总数通过 X-Total-Count 响应头返回
+func (p *LicenseManagerPlugin) listDevices(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	opts := &license_model.SearchDevicesOptions{
+		ListOptions: db.ListOptions{
+			Page:     int(ctx.FormInt64("page")),
+			PageSize: int(ctx.FormInt64("limit")),
+		},
+		UserID:  owner.ID,
+		Keyword: ctx.FormString("q"),
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 20
+	}
+	devices, count, err := license_model.SearchDevices(ctx, opts)
+	if err != nil {
+		ctx.ServerError("SearchDevices", err)
+		return
+	}
+	ctx.Resp.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+	ctx.JSON(http.StatusOK, devices)
+}
+
+// createDevice 为机器码创建授权并生成授权码，可以关联产品版本和额外的功能
+func (p *LicenseManagerPlugin) createDevice(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	var form struct {
+		MachineCode string                       `json:"machine_code"`
+		MachineName string                       `json:"machine_name"`
+		ExpiryDays  int                          `json:"expiry_days"` // 0 表示永久
+		Remarks     string                       `json:"remarks"`
+		ProductID   int64                        `json:"product_id"`
+		EditionID   int64                        `json:"edition_id"`
+		Features    []*license_model.Entitlement `json:"features"`
+	}
+	if err := ctx.DecodeJSON(&form); err != nil || form.MachineCode == "" {
+		ctx.APIError(http.StatusBadRequest, "machine_code is required")
+		return
+	}
+
+	device, err := license_service.CreateDevice(ctx, &license_service.CreateDeviceOptions{
+		UserID:      owner.ID,
+		MachineCode: form.MachineCode,
+		MachineName: form.MachineName,
+		ExpiryDays:  form.ExpiryDays,
+		Remarks:     form.Remarks,
+		ProductID:   form.ProductID,
+		EditionID:   form.EditionID,
+		Features:    form.Features,
+	})
+	if license_model.IsErrDeviceAlreadyExist(err) {
+		ctx.APIError(http.StatusConflict, "该机器码已存在授权")
+		return
+	} else if err != nil {
+		p.productError(ctx, "CreateDevice", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, map[string]any{
+		"id":          device.ID,
+		"device_id":   device.DeviceID,
+		"license_key": license_service.FormatLicenseKey(device.LicenseKey),
+	})
+}
+
+// deleteDevice 删除授权设备，之前签发的离线授权令牌进入吊销列表
+func (p *LicenseManagerPlugin) deleteDevice(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	device, err := license_model.GetDeviceByUserAndID(ctx, owner.ID, ctx.PathParamInt64("id"))
+	if license_model.IsErrDeviceNotExist(err) {
+		ctx.NotFound()
+		return
+	} else if err != nil {
+		ctx.ServerError("GetDeviceByUserAndID", err)
+		return
+	}
+	if err := license_model.DeleteDevice(ctx, device.ID); err != nil {
+		ctx.ServerError("DeleteDevice", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// toggleDevice 启用或禁用授权设备
+func (p *LicenseManagerPlugin) toggleDevice(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	var form struct {
+		ID int64 `json:"id"`
+	}
+	if err := ctx.DecodeJSON(&form); err != nil || form.ID == 0 {
+		ctx.APIError(http.StatusBadRequest, "id is required")
+		return
+	}
+	err := license_service.ToggleDevice(ctx, owner.ID, form.ID)
+	if license_model.IsErrDeviceNotExist(err) {
+		ctx.NotFound()
+		return
+	} else if err != nil {
+		ctx.ServerError("ToggleDevice", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// publicKey 返回离线验证授权令牌所需的公钥，无需登录
+func (p *LicenseManagerPlugin) publicKey(ctx *pluginsdk.Context) {
+	key, err := license_service.GetPublicKey()
+	if err != nil {
+		ctx.ServerError("GetPublicKey", err)
+		return
+	}
+	pemKey, err := key.PEM()
+	if err != nil {
+		ctx.ServerError("PEM", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]any{
+		"algorithm":  "EdDSA",
+		"key_id":     key.KeyID,
+		"public_key": pemKey,
+		"jwk":        key.JWK(),
+	})
+}
+
+// deviceToken 为当前用户的有效授权设备签发离线授权令牌
+func (p *LicenseManagerPlugin) deviceToken(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	device, err := license_model.GetDeviceByUserAndID(ctx, owner.ID, ctx.PathParamInt64("id"))
+	if license_model.IsErrDeviceNotExist(err) {
+		ctx.NotFound()
+		return
+	} else if err != nil {
+		ctx.ServerError("GetDeviceByUserAndID", err)
+		return
+	}
+	if !device.IsValid() {
+		ctx.APIError(http.StatusForbidden, "授权已被禁用或已过期")
+		return
+	}
+
+	entitlements, err := license_service.GetEntitlements(ctx, device)
+	if err != nil {
+		ctx.ServerError("GetEntitlements", err)
+		return
+	}
+	token, err := license_service.IssueToken(device, owner.Name, entitlements)
+	if err != nil {
+		ctx.ServerError("IssueToken", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]string{"token": token})
+}
+
+// licenseOwner 返回本次请求管理的授权所有者，失败时已写入响应。/api/v1/orgs/{org}/license 下的路由
+// 管理组织的授权，需要当前用户属于所有者团队或拥有授权管理权限的团队；其他路由管理当前用户自己的授权
+func (p *LicenseManagerPlugin) licenseOwner(ctx *pluginsdk.Context) (*user_model.User, bool) {
+	if !ctx.IsSigned() {
+		ctx.APIError(http.StatusUnauthorized, "需要登录")
+		return nil, false
+	}
+	doer, err := user_model.GetUserByID(ctx, ctx.Doer.ID)
+	if err != nil {
+		ctx.ServerError("GetUserByID", err)
+		return nil, false
+	}
+	orgName := ctx.PathParam("org")
+	if orgName == "" {
+		return doer, true
+	}
+
+	org, err := user_model.GetUserByName(ctx, orgName)
+	if user_model.IsErrUserNotExist(err) || (err == nil && !org.IsOrganization()) {
+		ctx.NotFound()
+		return nil, false
+	} else if err != nil {
+		ctx.ServerError("GetUserByName", err)
+		return nil, false
+	}
+	canManage, err := license_service.CanManageLicenses(ctx, doer, org)
+	if err != nil {
+		ctx.ServerError("CanManageLicenses", err)
+		return nil, false
+	}
+	if !canManage {
+		ctx.APIError(http.StatusForbidden, "没有管理该组织授权的权限")
+		return nil, false
+	}
+	return org, true
+}
+
+// apiKeyOwner 验证请求头中的 API 密钥，返回密钥所属的用户，验证失败时已写入响应
+func (p *LicenseManagerPlugin) apiKeyOwner(ctx *pluginsdk.Context) (*user_model.User, bool) {
+	key, err := license_service.AuthenticateAPIKey(ctx, ctx.Req.Header.Get(license_service.APIKeyHeader))
+	if license_model.IsErrAPIKeyNotExist(err) {
+		ctx.APIError(http.StatusUnauthorized, "API 密钥无效")
+		return nil, false
+	} else if err != nil {
+		ctx.ServerError("AuthenticateAPIKey", err)
+		return nil, false
+	}
+	owner, err := user_model.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		ctx.ServerError("GetUserByID", err)
+		return nil, false
+	}
+	// 用户被禁用后其 API 密钥同时失效
+	if !owner.IsActive || owner.ProhibitLogin {
+		ctx.APIError(http.StatusForbidden, "API 密钥所属用户已被禁用")
+		return nil, false
+	}
+	return owner, true
+}
+
+// validateLicense 使用 API 密钥验证授权，授权有效时返回新的离线授权令牌
+func (p *LicenseManagerPlugin) validateLicense(ctx *pluginsdk.Context) {
+	owner, ok := p.apiKeyOwner(ctx)
+	if !ok {
+		return
+	}
+	var form struct {
+		MachineCode string `json:"machine_code"`
+		LicenseKey  string `json:"license_key"`
+	}
+	if err := ctx.DecodeJSON(&form); err != nil {
+		ctx.APIError(http.StatusBadRequest, err)
+		return
+	}
+
+	valid, device, err := license_service.VerifyLicense(ctx, owner.ID, form.MachineCode, form.LicenseKey)
+	if err != nil {
+		ctx.ServerError("VerifyLicense", err)
+		return
+	}
+	resp := map[string]any{"is_authorized": valid}
+	if valid {
+		entitlements, err := license_service.GetEntitlements(ctx, device)
+		if err != nil {
+			ctx.ServerError("GetEntitlements", err)
+			return
+		}
+		resp["product"], resp["edition"], resp["features"] = entitlements.Product, entitlements.Edition, entitlements.Features
+		if resp["token"], err = license_service.IssueToken(device, owner.Name, entitlements); err != nil {
+			ctx.ServerError("IssueToken", err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// revocations 返回 API 密钥所属用户的签名吊销列表
+func (p *LicenseManagerPlugin) revocations(ctx *pluginsdk.Context) {
+	owner, ok := p.apiKeyOwner(ctx)
+	if !ok {
+		return
+	}
+	signed, claims, err := license_service.IssueRevocationList(ctx, owner.ID)
+	if err != nil {
+		ctx.ServerError("IssueRevocationList", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]any{
+		"revocation_list": signed,
+		"revoked":         claims.Revoked,
+		"next_update":     claims.ExpiresAt.Time,
+	})
+}
+
+func (p *LicenseManagerPlugin) listAPIKeys(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	keys, err := license_model.ListAPIKeys(ctx, owner.ID)
+	if err != nil {
+		ctx.ServerError("ListAPIKeys", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, keys)
 }
 
-// GetAssetsPath 获取静态资源路径
-func (p *LicenseManagerPlugin) GetAssetsPath() string {
-	return "./plugins/installed/license-manager/assets"
+func (p *LicenseManagerPlugin) createAPIKey(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	var form struct {
+		Name string `json:"name"`
+	}
+	if err := ctx.DecodeJSON(&form); err != nil || form.Name == "" {
+		ctx.APIError(http.StatusBadRequest, "name is required")
+		return
+	}
+	key, plain, err := license_service.CreateAPIKey(ctx, owner.ID, form.Name)
+	if err != nil {
+		ctx.ServerError("CreateAPIKey", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, map[string]any{"id": key.ID, "name": key.Name, "key": plain})
 }
 
-// GetLocalePath 获取国际化文件路径
-func (p *LicenseManagerPlugin) GetLocalePath() string {
-	return "./plugins/installed/license-manager/locales"
+func (p *LicenseManagerPlugin) deleteAPIKey(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	err := license_model.DeleteAPIKey(ctx, owner.ID, ctx.PathParamInt64("id"))
+	if license_model.IsErrAPIKeyNotExist(err) {
+		ctx.NotFound()
+		return
+	} else if err != nil {
+		ctx.ServerError("DeleteAPIKey", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
 }
 
-// Enable 启用插件
-func (p *LicenseManagerPlugin) Enable() error {
-	// 启用插件时的逻辑
-	return nil
+// productError 将产品和版本相关的错误转换为 API 响应
+func (p *LicenseManagerPlugin) productError(ctx *pluginsdk.Context, name string, err error) {
+	switch {
+	case license_model.IsErrProductNotExist(err), license_model.IsErrEditionNotExist(err):
+		ctx.NotFound()
+	case license_model.IsErrProductAlreadyExist(err), license_model.IsErrEditionAlreadyExist(err), license_model.IsErrLicenseInUse(err):
+		ctx.APIError(http.StatusConflict, err)
+	case license_model.IsErrInvalidEntitlement(err), errors.Is(err, util.ErrInvalidArgument):
+		ctx.APIError(http.StatusUnprocessableEntity, err)
+	default:
+		ctx.ServerError(name, err)
+	}
 }
 
-// Disable 禁用插件
-func (p *LicenseManagerPlugin) Disable() error {
-	// 禁用插件时的逻辑
-	return nil
+func (p *LicenseManagerPlugin) listProducts(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	products, err := license_model.ListProducts(ctx, owner.ID)
+	if err != nil {
+		ctx.ServerError("ListProducts", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, products)
 }
 
-// Uninstall 卸载插件
-func (p *LicenseManagerPlugin) Uninstall() error {
-	// 卸载插件时的清理逻辑
-	// 例如：删除数据库表、清理配置等
-	return nil
+func (p *LicenseManagerPlugin) createProduct(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	opts := &license_service.CreateProductOptions{}
+	if err := ctx.DecodeJSON(opts); err != nil {
+		ctx.APIError(http.StatusBadRequest, err)
+		return
+	}
+	opts.UserID = owner.ID
+	product, err := license_service.CreateProduct(ctx, opts)
+	if err != nil {
+		p.productError(ctx, "CreateProduct", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, product)
 }
 
-// GetConfig 获取配置
-func (p *LicenseManagerPlugin) GetConfig() map[string]interface{} {
-	return p.config
+func (p *LicenseManagerPlugin) deleteProduct(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	if err := license_service.DeleteProduct(ctx, owner.ID, ctx.PathParamInt64("id")); err != nil {
+		p.productError(ctx, "DeleteProduct", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
 }
 
-// SetConfig 设置配置
-func (p *LicenseManagerPlugin) SetConfig(config map[string]interface{}) error {
-	p.config = config
-	return nil
+func (p *LicenseManagerPlugin) listEditions(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	product, err := license_model.GetProductByUserAndID(ctx, owner.ID, ctx.PathParamInt64("id"))
+	if err != nil {
+		p.productError(ctx, "GetProductByUserAndID", err)
+		return
+	}
+	editions, err := license_model.ListEditions(ctx, product.ID)
+	if err != nil {
+		ctx.ServerError("ListEditions", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, editions)
 }
 
-// 以下是路由处理函数的占位实现
+func (p *LicenseManagerPlugin) createEdition(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	opts := &license_service.CreateEditionOptions{}
+	if err := ctx.DecodeJSON(opts); err != nil {
+		ctx.APIError(http.StatusBadRequest, err)
+		return
+	}
+	opts.UserID, opts.ProductID = owner.ID, ctx.PathParamInt64("id")
+	edition, err := license_service.CreateEdition(ctx, opts)
+	if err != nil {
+		p.productError(ctx, "CreateEdition", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, edition)
+}
 
-func (p *LicenseManagerPlugin) licenseList(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现授权列表
+func (p *LicenseManagerPlugin) editEdition(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	opts := &license_service.UpdateEditionOptions{}
+	if err := ctx.DecodeJSON(opts); err != nil {
+		ctx.APIError(http.StatusBadRequest, err)
+		return
+	}
+	opts.UserID, opts.ProductID, opts.ID = owner.ID, ctx.PathParamInt64("id"), ctx.PathParamInt64("edition_id")
+	edition, err := license_service.UpdateEdition(ctx, opts)
+	if err != nil {
+		p.productError(ctx, "UpdateEdition", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, edition)
 }
 
-func (p *LicenseManagerPlugin) licenseNew(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现新建授权页面
+func (p *LicenseManagerPlugin) deleteEdition(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	err := license_service.DeleteEdition(ctx, owner.ID, ctx.PathParamInt64("id"), ctx.PathParamInt64("edition_id"))
+	if err != nil {
+		p.productError(ctx, "DeleteEdition", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
 }
 
-func (p *LicenseManagerPlugin) licenseCreate(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现创建授权
+// seatError 将多席位授权相关的错误转换为 API 响应
+func (p *LicenseManagerPlugin) seatError(ctx *pluginsdk.Context, name string, err error) {
+	switch {
+	case license_model.IsErrLicenseNotExist(err), license_model.IsErrActivationNotExist(err), license_model.IsErrAssignmentNotExist(err):
+		ctx.NotFound()
+	case license_model.IsErrLicenseNotActive(err):
+		ctx.APIError(http.StatusForbidden, err)
+	case license_model.IsErrNoSeatsAvailable(err):
+		ctx.APIError(http.StatusConflict, err)
+	default:
+		p.productError(ctx, name, err)
+	}
 }
 
-func (p *LicenseManagerPlugin) licenseEdit(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现编辑授权页面
+// activationForm 激活、心跳和停用请求
+type activationForm struct {
+	LicenseKey  string `json:"license_key"`
+	MachineCode string `json:"machine_code"`
+	MachineName string `json:"machine_name"`
 }
 
-func (p *LicenseManagerPlugin) licenseUpdate(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现更新授权
+// activationResponse 返回激活结果和新的离线授权令牌
+func (p *LicenseManagerPlugin) activationResponse(ctx *pluginsdk.Context, owner *user_model.User, l *license_model.License, a *license_model.LicenseActivation) {
+	entitlements, err := license_service.GetLicenseEntitlements(ctx, l)
+	if err != nil {
+		ctx.ServerError("GetLicenseEntitlements", err)
+		return
+	}
+	token, err := license_service.IssueActivationToken(l, a, owner.Name, entitlements)
+	if err != nil {
+		ctx.ServerError("IssueActivationToken", err)
+		return
+	}
+	resp := map[string]any{
+		"type":       l.Type,
+		"seats":      l.Seats,
+		"seats_used": l.SeatsUsed,
+		"token":      token,
+		"product":    entitlements.Product,
+		"edition":    entitlements.Edition,
+		"features":   entitlements.Features,
+	}
+	if !l.ExpiryDate.IsZero() {
+		resp["expiry_date"] = l.ExpiryDate.AsTime()
+	}
+	if !a.LeaseExpiresUnix.IsZero() {
+		resp["lease_expires_at"] = a.LeaseExpiresUnix.AsTime()
+	}
+	ctx.JSON(http.StatusOK, resp)
 }
 
-func (p *LicenseManagerPlugin) licenseDelete(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现删除授权
+// activate 使用 API 密钥在机器上激活多席位授权
+func (p *LicenseManagerPlugin) activate(ctx *pluginsdk.Context) {
+	owner, ok := p.apiKeyOwner(ctx)
+	if !ok {
+		return
+	}
+	var form activationForm
+	if err := ctx.DecodeJSON(&form); err != nil {
+		ctx.APIError(http.StatusBadRequest, err)
+		return
+	}
+	l, a, err := license_service.Activate(ctx, owner.ID, form.LicenseKey, form.MachineCode, form.MachineName)
+	if err != nil {
+		p.seatError(ctx, "Activate", err)
+		return
+	}
+	p.activationResponse(ctx, owner, l, a)
 }
 
-func (p *LicenseManagerPlugin) licenseToggle(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现切换授权状态
+// heartbeat 续租浮动授权
+func (p *LicenseManagerPlugin) heartbeat(ctx *pluginsdk.Context) {
+	owner, ok := p.apiKeyOwner(ctx)
+	if !ok {
+		return
+	}
+	var form activationForm
+	if err := ctx.DecodeJSON(&form); err != nil {
+		ctx.APIError(http.StatusBadRequest, err)
+		return
+	}
+	l, a, err := license_service.Heartbeat(ctx, owner.ID, form.LicenseKey, form.MachineCode)
+	if err != nil {
+		p.seatError(ctx, "Heartbeat", err)
+		return
+	}
+	p.activationResponse(ctx, owner, l, a)
 }
 
-func (p *LicenseManagerPlugin) verifyLicense(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现授权验证
+// deactivate 停用机器并释放席位
+func (p *LicenseManagerPlugin) deactivate(ctx *pluginsdk.Context) {
+	owner, ok := p.apiKeyOwner(ctx)
+	if !ok {
+		return
+	}
+	var form activationForm
+	if err := ctx.DecodeJSON(&form); err != nil {
+		ctx.APIError(http.StatusBadRequest, err)
+		return
+	}
+	if err := license_service.Deactivate(ctx, owner.ID, form.LicenseKey, form.MachineCode); err != nil {
+		p.seatError(ctx, "Deactivate", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
 }
 
-func (p *LicenseManagerPlugin) registerDevice(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现设备注册
+func (p *LicenseManagerPlugin) listLicenses(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	licenses, _, err := license_model.SearchLicenses(ctx, &license_model.SearchLicensesOptions{UserID: owner.ID})
+	if err != nil {
+		ctx.ServerError("SearchLicenses", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, licenses)
 }
 
-func (p *LicenseManagerPlugin) listDevices(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现设备列表
+func (p *LicenseManagerPlugin) createLicense(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	var form struct {
+		Name         string                       `json:"name"`
+		Type         string                       `json:"type"`
+		Seats        int                          `json:"seats"`
+		LeaseMinutes int                          `json:"lease_minutes"`
+		ExpiryDays   int                          `json:"expiry_days"`
+		ProductID    int64                        `json:"product_id"`
+		EditionID    int64                        `json:"edition_id"`
+		Features     []*license_model.Entitlement `json:"features"`
+		Remarks      string                       `json:"remarks"`
+	}
+	if err := ctx.DecodeJSON(&form); err != nil {
+		ctx.APIError(http.StatusBadRequest, err)
+		return
+	}
+	l, err := license_service.CreateLicense(ctx, &license_service.CreateLicenseOptions{
+		UserID:        owner.ID,
+		Name:          form.Name,
+		Type:          form.Type,
+		Seats:         form.Seats,
+		LeaseDuration: time.Duration(form.LeaseMinutes) * time.Minute,
+		ExpiryDays:    form.ExpiryDays,
+		ProductID:     form.ProductID,
+		EditionID:     form.EditionID,
+		Features:      form.Features,
+		Remarks:       form.Remarks,
+	})
+	if err != nil {
+		p.seatError(ctx, "CreateLicense", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, l)
 }
 
-func (p *LicenseManagerPlugin) createDevice(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现创建设备
+func (p *LicenseManagerPlugin) deleteLicense(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	if err := license_service.DeleteLicense(ctx, owner.ID, ctx.PathParamInt64("id")); err != nil {
+		p.seatError(ctx, "DeleteLicense", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (p *LicenseManagerPlugin) toggleLicense(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	if err := license_service.ToggleLicense(ctx, owner.ID, ctx.PathParamInt64("id")); err != nil {
+		p.seatError(ctx, "ToggleLicense", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (p *LicenseManagerPlugin) listActivations(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	l, err := license_model.GetLicenseByUserAndID(ctx, owner.ID, ctx.PathParamInt64("id"))
+	if err != nil {
+		p.seatError(ctx, "GetLicenseByUserAndID", err)
+		return
+	}
+	activations, err := license_model.ListActivations(ctx, l.ID)
+	if err != nil {
+		ctx.ServerError("ListActivations", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, activations)
+}
+
+func (p *LicenseManagerPlugin) releaseActivation(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	err := license_service.ReleaseActivation(ctx, owner.ID, ctx.PathParamInt64("id"), ctx.PathParamInt64("activation_id"))
+	if err != nil {
+		p.seatError(ctx, "ReleaseActivation", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (p *LicenseManagerPlugin) listAssignments(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	l, err := license_model.GetLicenseByUserAndID(ctx, owner.ID, ctx.PathParamInt64("id"))
+	if err != nil {
+		p.seatError(ctx, "GetLicenseByUserAndID", err)
+		return
+	}
+	assignments, err := license_model.ListAssignments(ctx, l.ID)
+	if err != nil {
+		ctx.ServerError("ListAssignments", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, assignments)
+}
+
+// assignSeat 将组织授权的席位分配给组织成员
+func (p *LicenseManagerPlugin) assignSeat(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	var form struct {
+		UserName string `json:"username"`
+	}
+	if err := ctx.DecodeJSON(&form); err != nil || form.UserName == "" {
+		ctx.APIError(http.StatusBadRequest, "username is required")
+		return
+	}
+	member, err := user_model.GetUserByName(ctx, form.UserName)
+	if user_model.IsErrUserNotExist(err) {
+		ctx.APIError(http.StatusUnprocessableEntity, err)
+		return
+	} else if err != nil {
+		ctx.ServerError("GetUserByName", err)
+		return
+	}
+	doer, err := user_model.GetUserByID(ctx, ctx.Doer.ID)
+	if err != nil {
+		ctx.ServerError("GetUserByID", err)
+		return
+	}
+	a, err := license_service.AssignSeat(ctx, doer, owner.ID, ctx.PathParamInt64("id"), member.ID)
+	if err != nil {
+		p.seatError(ctx, "AssignSeat", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, a)
+}
+
+// unassignSeat 收回成员的席位，成员激活的机器同时被释放
+func (p *LicenseManagerPlugin) unassignSeat(ctx *pluginsdk.Context) {
+	owner, ok := p.licenseOwner(ctx)
+	if !ok {
+		return
+	}
+	member, err := user_model.GetUserByName(ctx, ctx.PathParam("username"))
+	if user_model.IsErrUserNotExist(err) {
+		ctx.NotFound()
+		return
+	} else if err != nil {
+		ctx.ServerError("GetUserByName", err)
+		return
+	}
+	if err := license_service.UnassignSeat(ctx, owner.ID, ctx.PathParamInt64("id"), member.ID); err != nil {
+		p.seatError(ctx, "UnassignSeat", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// listAssignedLicenses 列出组织分配给当前用户席位的授权
+func (p *LicenseManagerPlugin) listAssignedLicenses(ctx *pluginsdk.Context) {
+	if !ctx.IsSigned() {
+		ctx.APIError(http.StatusUnauthorized, "需要登录")
+		return
+	}
+	licenses, err := license_model.ListAssignedLicenses(ctx, ctx.Doer.ID)
+	if err != nil {
+		ctx.ServerError("ListAssignedLicenses", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, licenses)
+}
+
+// activateSeat 组织成员使用分配的席位在机器上激活授权
+func (p *LicenseManagerPlugin) activateSeat(ctx *pluginsdk.Context) {
+	if !ctx.IsSigned() {
+		ctx.APIError(http.StatusUnauthorized, "需要登录")
+		return
+	}
+	var form activationForm
+	if err := ctx.DecodeJSON(&form); err != nil {
+		ctx.APIError(http.StatusBadRequest, err)
+		return
+	}
+	l, a, err := license_service.ActivateSeat(ctx, ctx.Doer.ID, ctx.PathParamInt64("id"), form.MachineCode, form.MachineName)
+	if err != nil {
+		p.seatError(ctx, "ActivateSeat", err)
+		return
+	}
+	// 令牌的 user 为授权所有者，与使用组织 API 密钥激活时一致
+	owner, err := user_model.GetUserByID(ctx, l.UserID)
+	if err != nil {
+		ctx.ServerError("GetUserByID", err)
+		return
+	}
+	p.activationResponse(ctx, owner, l, a)
+}
+
+// deactivateSeat 组织成员停用自己激活的机器
+func (p *LicenseManagerPlugin) deactivateSeat(ctx *pluginsdk.Context) {
+	if !ctx.IsSigned() {
+		ctx.APIError(http.StatusUnauthorized, "需要登录")
+		return
+	}
+	var form activationForm
+	if err := ctx.DecodeJSON(&form); err != nil {
+		ctx.APIError(http.StatusBadRequest, err)
+		return
+	}
+	if err := license_service.DeactivateSeat(ctx, ctx.Doer.ID, ctx.PathParamInt64("id"), form.MachineCode); err != nil {
+		p.seatError(ctx, "DeactivateSeat", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
 }
 
-func (p *LicenseManagerPlugin) deleteDevice(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现删除设备
+func (p *LicenseManagerPlugin) expireLicenses(ctx context.Context) error {
+	// TODO: 实现过期授权清理
+	// 原授权已过期的吊销记录不再需要
+	return license_model.DeleteExpiredRevocations(ctx)
 }
 
-func (p *LicenseManagerPlugin) toggleDevice(w http.ResponseWriter, r *http.Request) {
-	// TODO: 实现切换设备状态
+// expireLeases 回收浮动授权已过期的租约，释放占用的席位
+func (p *LicenseManagerPlugin) expireLeases(ctx context.Context) error {
+	_, err := license_service.ExpireStaleLeases(ctx)
+	return err
 }
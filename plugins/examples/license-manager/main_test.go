@@ -0,0 +1,196 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	license_model "code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	license_service "code.gitea.io/gitea/services/license"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	unittest.MainTest(m)
+}
+
+// apiRequest 通过插件注册的 API 路由发送请求，doer 为 nil 时不登录
+func apiRequest(t *testing.T, method, url string, doer *user_model.User, body any, header http.Header) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	Plugin.RegisterAPIRoutes(r)
+
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req := httptest.NewRequest(method, url, &buf)
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if doer != nil {
+		req = req.WithContext(plugin.WithRequestInfo(req.Context(), &plugin.RequestInfo{
+			Doer: &plugin.HostUser{ID: doer.ID, Name: doer.Name, IsActive: true},
+		}))
+	}
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+	return resp
+}
+
+func decodeResponse(t *testing.T, resp *httptest.ResponseRecorder) map[string]any {
+	var v map[string]any
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &v), resp.Body.String())
+	return v
+}
+
+func TestVerifyLicense(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.AppDataPath, t.TempDir())()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	resp := apiRequest(t, "POST", "/api/v1/user/license/devices", user, map[string]any{
+		"machine_code": "MACHINE-1",
+		"features":     []*license_model.Entitlement{{Name: "export"}, {Name: "seats", Quantity: 5}},
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	licenseKey := decodeResponse(t, resp)["license_key"].(string)
+
+	resp = apiRequest(t, "POST", "/api/v1/user/license/devices", user, map[string]any{"machine_code": "MACHINE-1"}, nil)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = apiRequest(t, "POST", "/api/v1/license/verify", nil, map[string]any{"machine_code": "MACHINE-1", "license_key": licenseKey}, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = apiRequest(t, "POST", "/api/v1/license/verify", user, map[string]any{"machine_code": "MACHINE-1", "license_key": "WRONG"}, nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	result := decodeResponse(t, resp)
+	assert.Equal(t, false, result["is_authorized"])
+	assert.Equal(t, "授权码无效", result["message"])
+	assert.NotContains(t, result, "token")
+
+	resp = apiRequest(t, "POST", "/api/v1/license/verify", user, map[string]any{"machine_code": "MACHINE-1", "license_key": licenseKey}, nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	result = decodeResponse(t, resp)
+	assert.Equal(t, true, result["is_authorized"])
+	assert.Len(t, result["features"], 2)
+
+	// 返回的令牌使用实例的 ed25519 私钥签名，包含授权的功能
+	claims, err := license_service.ParseToken(result["token"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, "MACHINE-1", claims.MachineCode)
+	assert.Equal(t, user.Name, claims.UserName)
+	assert.Equal(t, []*license_model.Entitlement{{Name: "export"}, {Name: "seats", Quantity: 5}}, claims.Features)
+}
+
+func TestAPIKeyRoutes(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.AppDataPath, t.TempDir())()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	resp := apiRequest(t, "POST", "/api/v1/user/license/api-keys", user, map[string]any{"name": "client"}, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	apiKey := http.Header{license_service.APIKeyHeader: {decodeResponse(t, resp)["key"].(string)}}
+
+	resp = apiRequest(t, "POST", "/api/v1/user/license/devices", user, map[string]any{"machine_code": "MACHINE-2"}, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	device := decodeResponse(t, resp)
+	verifyForm := map[string]any{"machine_code": "MACHINE-2", "license_key": device["license_key"]}
+
+	// 客户端程序不登录 Gitea，只使用 API 密钥
+	resp = apiRequest(t, "POST", "/api/v1/license/validate", nil, verifyForm, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	resp = apiRequest(t, "POST", "/api/v1/license/validate", nil, verifyForm, http.Header{license_service.APIKeyHeader: {"invalid"}})
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = apiRequest(t, "POST", "/api/v1/license/validate", nil, verifyForm, apiKey)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	result := decodeResponse(t, resp)
+	assert.Equal(t, true, result["is_authorized"])
+	claims, err := license_service.ParseToken(result["token"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, user.Name, claims.UserName)
+
+	// 删除的设备出现在签名的吊销列表中
+	require.NoError(t, license_model.DeleteDevice(t.Context(), int64(device["id"].(float64))))
+	resp = apiRequest(t, "GET", "/api/v1/license/revocations", nil, nil, apiKey)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	list, err := license_service.ParseRevocationList(decodeResponse(t, resp)["revocation_list"].(string))
+	require.NoError(t, err)
+	assert.True(t, list.IsRevoked(device["device_id"].(string)))
+
+	// 用户被禁用后其 API 密钥不能再使用
+	user.ProhibitLogin = true
+	require.NoError(t, user_model.UpdateUserCols(t.Context(), user, "prohibit_login"))
+	resp = apiRequest(t, "GET", "/api/v1/license/revocations", nil, nil, apiKey)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+func TestDeviceRoutes(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.AppDataPath, t.TempDir())()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	resp := apiRequest(t, "POST", "/api/v1/user/license/products", user, map[string]any{"key": "app", "name": "App"}, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	productID := decodeResponse(t, resp)["ID"]
+	resp = apiRequest(t, "POST", fmt.Sprintf("/api/v1/user/license/products/%v/editions", productID), user, map[string]any{
+		"key":      "pro",
+		"name":     "Pro",
+		"features": []*license_model.Entitlement{{Name: "export"}, {Name: "seats", Quantity: 5}},
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	editionID := decodeResponse(t, resp)["ID"]
+
+	// 设备关联版本，单个授权的功能覆盖版本的同名功能
+	resp = apiRequest(t, "POST", "/api/v1/user/license/devices", user, map[string]any{
+		"machine_code": "MACHINE-3",
+		"product_id":   productID,
+		"edition_id":   editionID,
+		"features":     []*license_model.Entitlement{{Name: "seats", Quantity: 10}},
+	}, nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	device := decodeResponse(t, resp)
+	resp = apiRequest(t, "POST", "/api/v1/user/license/devices", user, map[string]any{"machine_code": "MACHINE-4", "product_id": productID, "edition_id": 999}, nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	verifyForm := map[string]any{"machine_code": "MACHINE-3", "license_key": device["license_key"]}
+	resp = apiRequest(t, "POST", "/api/v1/license/verify", user, verifyForm, nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	claims, err := license_service.ParseToken(decodeResponse(t, resp)["token"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, "app", claims.Product)
+	assert.Equal(t, "pro", claims.Edition)
+	assert.ElementsMatch(t, []*license_model.Entitlement{{Name: "export"}, {Name: "seats", Quantity: 10}}, claims.Features)
+
+	resp = apiRequest(t, "GET", "/api/v1/user/license/devices?limit=1", user, nil, nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "1", resp.Header().Get("X-Total-Count"))
+
+	resp = apiRequest(t, "POST", "/api/v1/user/license/devices/toggle", user, map[string]any{"id": device["id"]}, nil)
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	resp = apiRequest(t, "POST", "/api/v1/license/verify", user, verifyForm, nil)
+	assert.Equal(t, "授权已被禁用", decodeResponse(t, resp)["message"])
+
+	// 其他用户不能删除该设备
+	resp = apiRequest(t, "DELETE", fmt.Sprintf("/api/v1/user/license/devices/%v", device["id"]), unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4}), nil, nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	resp = apiRequest(t, "DELETE", fmt.Sprintf("/api/v1/user/license/devices/%v", device["id"]), user, nil, nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	unittest.AssertNotExistsBean(t, &license_model.AuthorizedDevice{MachineCode: "MACHINE-3"})
+}
@@ -0,0 +1,470 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	plugin_service "code.gitea.io/gitea/services/plugin"
+)
+
+// ListPlugins api for listing installed plugins
+func ListPlugins(ctx *context.APIContext) {
+	// swagger:operation GET /admin/plugins admin adminListPlugins
+	// ---
+	// summary: List installed plugins
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PluginList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	plugins, err := plugin_service.GetManager().ListInstalled(ctx)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	count := len(plugins)
+
+	listOpts := utils.GetListOptions(ctx)
+	plugins = util.PaginateSlice(plugins, listOpts.Page, listOpts.PageSize).([]*plugin_model.Plugin)
+
+	res := make([]*api.Plugin, len(plugins))
+	for i, p := range plugins {
+		res[i] = convert.ToPlugin(p)
+	}
+
+	ctx.SetTotalCountHeader(int64(count))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetPlugin api for getting an installed plugin
+func GetPlugin(ctx *context.APIContext) {
+	// swagger:operation GET /admin/plugins/{id} admin adminGetPlugin
+	// ---
+	// summary: Get an installed plugin
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the plugin
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Plugin"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	p := getPluginByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPlugin(p))
+}
+
+// InstallPlugin api for installing a plugin from the marketplace
+func InstallPlugin(ctx *context.APIContext) {
+	// swagger:operation POST /admin/plugins admin adminInstallPlugin
+	// ---
+	// summary: Install a plugin from the marketplace
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/InstallPluginOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Plugin"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.InstallPluginOption)
+	version := form.Version
+	if version == "" {
+		version = "latest"
+	}
+
+	if err := plugin_service.GetManager().Install(ctx, ctx.Doer, form.ID, version); err != nil {
+		handleInstallPluginError(ctx, err)
+		return
+	}
+	p, err := plugin_model.GetPluginByID(ctx, form.ID)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToPlugin(p))
+}
+
+// EnablePlugin api for enabling a plugin
+func EnablePlugin(ctx *context.APIContext) {
+	// swagger:operation POST /admin/plugins/{id}/enable admin adminEnablePlugin
+	// ---
+	// summary: Enable a plugin
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the plugin
+	//   type: string
+	//   required: true
+	// - name: approve_permissions
+	//   in: query
+	//   description: approve the permissions declared by the plugin before enabling it
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Plugin"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	p := getPluginByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	manager := plugin_service.GetManager()
+	if ctx.FormBool("approve_permissions") {
+		if err := manager.ApprovePermissions(ctx, ctx.Doer, p.PluginID); err != nil {
+			handlePluginError(ctx, err)
+			return
+		}
+	}
+	if err := manager.Enable(ctx, ctx.Doer, p.PluginID); err != nil {
+		handlePluginError(ctx, err)
+		return
+	}
+	respondPlugin(ctx, p.PluginID)
+}
+
+// DisablePlugin api for disabling a plugin
+func DisablePlugin(ctx *context.APIContext) {
+	// swagger:operation POST /admin/plugins/{id}/disable admin adminDisablePlugin
+	// ---
+	// summary: Disable a plugin
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the plugin
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Plugin"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	p := getPluginByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := plugin_service.GetManager().Disable(ctx, ctx.Doer, p.PluginID); err != nil {
+		handlePluginError(ctx, err)
+		return
+	}
+	respondPlugin(ctx, p.PluginID)
+}
+
+// GetPluginConfig api for getting the configuration of a plugin
+func GetPluginConfig(ctx *context.APIContext) {
+	// swagger:operation GET /admin/plugins/{id}/config admin adminGetPluginConfig
+	// ---
+	// summary: Get the configuration of a plugin, secret values are not returned
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the plugin
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PluginConfig"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	cfg, err := plugin_service.GetManager().GetConfig(ctx, ctx.PathParam("id"))
+	if err != nil {
+		handlePluginError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toPluginConfig(cfg))
+}
+
+// EditPluginConfig api for editing the configuration of a plugin
+func EditPluginConfig(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/plugins/{id}/config admin adminEditPluginConfig
+	// ---
+	// summary: Edit the configuration of a plugin, the result is validated against the plugin's config schema
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the plugin
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditPluginConfigOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PluginConfig"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.EditPluginConfigOption)
+	manager := plugin_service.GetManager()
+	if err := manager.PatchConfig(ctx, ctx.Doer, ctx.PathParam("id"), form.Config); err != nil {
+		handlePluginError(ctx, err)
+		return
+	}
+	cfg, err := manager.GetConfig(ctx, ctx.PathParam("id"))
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toPluginConfig(cfg))
+}
+
+// UninstallPlugin api for uninstalling a plugin
+func UninstallPlugin(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/plugins/{id} admin adminUninstallPlugin
+	// ---
+	// summary: Uninstall a plugin
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the plugin
+	//   type: string
+	//   required: true
+	// - name: purge_data
+	//   in: query
+	//   description: also drop the plugin's database tables and schema version
+	//   type: boolean
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	p := getPluginByPathParam(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := plugin_service.GetManager().Uninstall(ctx, ctx.Doer, p.PluginID, ctx.FormBool("purge_data")); err != nil {
+		handlePluginError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getPluginByPathParam(ctx *context.APIContext) *plugin_model.Plugin {
+	p, err := plugin_model.GetPluginByID(ctx, ctx.PathParam("id"))
+	if err != nil {
+		handlePluginError(ctx, err)
+		return nil
+	}
+	return p
+}
+
+func respondPlugin(ctx *context.APIContext, pluginID string) {
+	p, err := plugin_model.GetPluginByID(ctx, pluginID)
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPlugin(p))
+}
+
+func toPluginConfig(cfg *plugin_service.PluginConfig) *api.PluginConfig {
+	res := &api.PluginConfig{Config: cfg.Config, SecretsSet: []string{}}
+	for _, f := range cfg.Fields {
+		if cfg.SecretSet[f.Name] {
+			res.SecretsSet = append(res.SecretsSet, f.Name)
+		}
+	}
+	return res
+}
+
+func handlePluginError(ctx *context.APIContext, err error) {
+	switch {
+	case plugin_model.IsErrPluginNotExist(err):
+		ctx.APIErrorNotFound(err)
+	case plugin_model.IsErrPermissionsNotApproved(err),
+		plugin_model.IsErrPluginHasDependents(err),
+		plugin_model.IsErrPluginIncompatible(err),
+		plugin_model.IsErrPluginDependencyNotSatisfied(err),
+		plugin_model.IsErrPluginDependencyInvalid(err):
+		ctx.APIError(http.StatusConflict, err)
+	case plugin_model.IsErrPluginConfigInvalid(err):
+		ctx.APIError(http.StatusUnprocessableEntity, err)
+	default:
+		ctx.APIErrorInternal(err)
+	}
+}
+
+// InstallPluginFromUpload api for installing an uploaded plugin package
+func InstallPluginFromUpload(ctx *context.APIContext) {
+	// swagger:operation POST /admin/plugins/upload admin adminInstallPluginFromUpload
+	// ---
+	// summary: Install a plugin from an uploaded package
+	// consumes:
+	// - multipart/form-data
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: file
+	//   in: formData
+	//   description: signed plugin package (.zip)
+	//   type: file
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Plugin"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	ctx.Req.Body = http.MaxBytesReader(ctx.Resp, ctx.Req.Body, setting.PluginMaxUploadSize<<20)
+	file, _, err := ctx.Req.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.APIError(http.StatusRequestEntityTooLarge, err)
+		} else {
+			ctx.APIError(http.StatusBadRequest, err)
+		}
+		return
+	}
+	defer file.Close()
+
+	p, err := plugin_service.GetManager().InstallFromFile(ctx, ctx.Doer, file)
+	if err != nil {
+		handleInstallPluginError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToPlugin(p))
+}
+
+// InstallPluginFromRelease api for installing a plugin from a repository release attachment
+func InstallPluginFromRelease(ctx *context.APIContext) {
+	// swagger:operation POST /admin/plugins/release admin adminInstallPluginFromRelease
+	// ---
+	// summary: Install a plugin from a release attachment of a repository on this instance
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/InstallPluginFromReleaseOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Plugin"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.InstallPluginFromReleaseOption)
+
+	p, err := plugin_service.GetManager().InstallFromRelease(ctx, ctx.Doer, plugin_service.InstallFromReleaseOptions{
+		Owner:      form.Owner,
+		Repo:       form.Repo,
+		TagName:    form.TagName,
+		Attachment: form.Attachment,
+	})
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) || repo_model.IsErrReleaseNotExist(err) {
+			ctx.APIErrorNotFound(err)
+			return
+		}
+		handleInstallPluginError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToPlugin(p))
+}
+
+func handleInstallPluginError(ctx *context.APIContext, err error) {
+	switch {
+	case plugin_model.IsErrPluginAlreadyExist(err):
+		ctx.APIError(http.StatusConflict, err)
+	case plugin_model.IsErrPackageVerification(err),
+		plugin_model.IsErrPluginInvalidID(err),
+		plugin_model.IsErrPluginIncompatible(err),
+		plugin_model.IsErrPluginDependencyInvalid(err),
+		plugin_model.IsErrPluginDependencyNotSatisfied(err),
+		errors.Is(err, util.ErrInvalidArgument):
+		ctx.APIError(http.StatusUnprocessableEntity, err)
+	case plugin_model.IsErrMarketPluginNotExist(err), errors.Is(err, util.ErrNotExist):
+		ctx.APIErrorNotFound(err)
+	default:
+		ctx.APIErrorInternal(err)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListPluginAudits api for listing the plugin audit log
+func ListPluginAudits(ctx *context.APIContext) {
+	// swagger:operation GET /admin/plugins/audit admin adminListPluginAudits
+	// ---
+	// summary: List the plugin audit log, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: plugin
+	//   in: query
+	//   description: only show entries of this plugin
+	//   type: string
+	// - name: action
+	//   in: query
+	//   description: only show entries of this action
+	//   type: string
+	//   enum: [install, uninstall, enable, disable, quarantine, upgrade, rollback, config, approve_permissions]
+	// - name: doer
+	//   in: query
+	//   description: only show entries of this user name
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: only show entries created at or after the given time (RFC 3339 format)
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: only show entries created before the given time (RFC 3339 format)
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PluginAuditList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	before, since, err := context.GetQueryBeforeSince(ctx.Base)
+	if err != nil {
+		ctx.APIError(http.StatusUnprocessableEntity, err)
+		return
+	}
+	action := ctx.FormString("action")
+	if action != "" && !slices.Contains(plugin_model.AuditActions, action) {
+		ctx.APIError(http.StatusUnprocessableEntity, "unknown action "+action)
+		return
+	}
+
+	audits, count, err := db.FindAndCount[plugin_model.Audit](ctx, plugin_model.FindAuditOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		PluginID:    ctx.FormTrim("plugin"),
+		Action:      action,
+		DoerName:    ctx.FormTrim("doer"),
+		Since:       timeutil.TimeStamp(since),
+		Before:      timeutil.TimeStamp(before),
+	})
+	if err != nil {
+		ctx.APIErrorInternal(err)
+		return
+	}
+
+	res := make([]*api.PluginAudit, len(audits))
+	for i, a := range audits {
+		res[i] = convert.ToPluginAudit(a)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, res)
+}
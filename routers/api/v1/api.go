@@ -83,6 +83,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/modules/web/routing"
 	"code.gitea.io/gitea/routers/api/v1/activitypub"
 	"code.gitea.io/gitea/routers/api/v1/admin"
 	"code.gitea.io/gitea/routers/api/v1/misc"
@@ -97,6 +98,7 @@ import (
 	"code.gitea.io/gitea/services/auth"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
+	plugin_service "code.gitea.io/gitea/services/plugin"
 
 	_ "code.gitea.io/gitea/routers/api/v1/swagger" // for swagger generation
 
@@ -399,6 +401,16 @@ func reqSiteAdmin() func(ctx *context.APIContext) {
 	}
 }
 
+// reqPluginManager the plugin manager should have been initialized
+func reqPluginManager() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if plugin_service.GetManager() == nil {
+			ctx.APIErrorInternal(errors.New("plugin manager not initialized"))
+			return
+		}
+	}
+}
+
 // reqOwner user should be the owner of the repo or site admin.
 func reqOwner() func(ctx *context.APIContext) {
 	return func(ctx *context.APIContext) {
@@ -1701,6 +1713,21 @@ func Routes() *web.Router {
 				m.Get("", admin.ListCronTasks)
 				m.Post("/{task}", admin.PostCronTask)
 			})
+			m.Group("/plugins", func() {
+				m.Combo("").Get(admin.ListPlugins).
+					Post(bind(api.InstallPluginOption{}), admin.InstallPlugin)
+				m.Post("/upload", admin.InstallPluginFromUpload)
+				m.Post("/release", bind(api.InstallPluginFromReleaseOption{}), admin.InstallPluginFromRelease)
+				m.Get("/audit", admin.ListPluginAudits)
+				m.Group("/{id}", func() {
+					m.Combo("").Get(admin.GetPlugin).
+						Delete(admin.UninstallPlugin)
+					m.Post("/enable", admin.EnablePlugin)
+					m.Post("/disable", admin.DisablePlugin)
+					m.Combo("/config").Get(admin.GetPluginConfig).
+						Patch(bind(api.EditPluginConfigOption{}), admin.EditPluginConfig)
+				})
+			}, reqPluginManager())
 			m.Get("/orgs", admin.GetAllOrgs)
 			m.Group("/users", func() {
 				m.Get("", admin.SearchUsers)
@@ -1757,6 +1784,13 @@ func Routes() *web.Router {
 		}, tokenRequiresScopes(auth_model.AccessTokenScopeCategoryRepository))
 	}, sudo())
 
+	// plugin API routes are mounted dynamically and only tried when no core route matches,
+	// the auth checks above have already been applied to them
+	m.NotFound(plugin_service.GetRouter().APIHandler(nil, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		defer routing.RecordFuncInfo(req.Context(), routing.GetFuncInfo(http.NotFound, "APINotFound"))()
+		http.NotFound(w, req)
+	})).ServeHTTP)
+
 	return m
 }
 
@@ -213,6 +213,7 @@ func CreateTeam(ctx *context.APIContext) {
 		Description:             form.Description,
 		IncludesAllRepositories: form.IncludesAllRepositories,
 		CanCreateOrgRepo:        form.CanCreateOrgRepo,
+		CanManageLicenses:       form.CanManageLicenses,
 		AccessMode:              teamPermission,
 	}
 
@@ -283,6 +284,10 @@ func EditTeam(ctx *context.APIContext) {
 		team.CanCreateOrgRepo = team.IsOwnerTeam() || *form.CanCreateOrgRepo
 	}
 
+	if form.CanManageLicenses != nil {
+		team.CanManageLicenses = team.IsOwnerTeam() || *form.CanManageLicenses
+	}
+
 	if len(form.Name) > 0 {
 		team.Name = form.Name
 	}
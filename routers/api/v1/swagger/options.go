@@ -227,4 +227,13 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	LockIssueOption api.LockIssueOption
+
+	// in:body
+	InstallPluginFromReleaseOption api.InstallPluginFromReleaseOption
+
+	// in:body
+	InstallPluginOption api.InstallPluginOption
+
+	// in:body
+	EditPluginConfigOption api.EditPluginConfigOption
 }
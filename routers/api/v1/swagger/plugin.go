@@ -0,0 +1,36 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// Plugin
+// swagger:response Plugin
+type swaggerResponsePlugin struct {
+	// in:body
+	Body api.Plugin `json:"body"`
+}
+
+// PluginList
+// swagger:response PluginList
+type swaggerResponsePluginList struct {
+	// in:body
+	Body []api.Plugin `json:"body"`
+}
+
+// PluginConfig
+// swagger:response PluginConfig
+type swaggerResponsePluginConfig struct {
+	// in:body
+	Body api.PluginConfig `json:"body"`
+}
+
+// PluginAuditList
+// swagger:response PluginAuditList
+type swaggerResponsePluginAuditList struct {
+	// in:body
+	Body []api.PluginAudit `json:"body"`
+}
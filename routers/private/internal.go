@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/services/context"
@@ -79,6 +80,17 @@ func Routes() *web.Router {
 	r.Post("/manager/add-logger", bind(private.LoggerOptions{}), AddLogger)
 	r.Post("/manager/remove-logger/{logger}/{writer}", RemoveLogger)
 	r.Get("/manager/processes", Processes)
+	r.Group("/plugins", func() {
+		r.Get("", ListPlugins)
+		r.Post("/install", bind(private.PluginInstallOptions{}), InstallPlugin)
+		r.Post("/{id}/enable", bind(private.PluginEnableOptions{}), EnablePlugin)
+		r.Post("/{id}/disable", DisablePlugin)
+		r.Get("/{id}/config", GetPluginConfig)
+		r.Post("/{id}/config", bind(structs.EditPluginConfigOption{}), EditPluginConfig)
+		r.Post("/{id}/uninstall", bind(private.PluginUninstallOptions{}), UninstallPlugin)
+		r.Post("/{id}/upgrade", bind(private.PluginUpgradeOptions{}), UpgradePlugin)
+		r.Post("/{id}/rollback", RollbackPlugin)
+	}, pluginManagerRequired)
 	r.Post("/mail/send", SendEmail)
 	r.Post("/restore_repo", RestoreRepo)
 	r.Post("/actions/generate_actions_runner_token", GenerateActionsRunnerToken)
@@ -0,0 +1,195 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	plugin_service "code.gitea.io/gitea/services/plugin"
+)
+
+// pluginManagerRequired rejects plugin commands when the plugin system is disabled or failed to start
+func pluginManagerRequired(ctx *context.PrivateContext) {
+	if plugin_service.GetManager() == nil {
+		ctx.JSON(http.StatusServiceUnavailable, private.Response{
+			UserMsg: "The plugin system is not running, check that [plugin] ENABLED is true and the server log for startup errors",
+		})
+	}
+}
+
+// ListPlugins lists all installed plugins
+func ListPlugins(ctx *context.PrivateContext) {
+	plugins, err := plugin_service.GetManager().ListInstalled(ctx)
+	if err != nil {
+		respondPluginError(ctx, err)
+		return
+	}
+	res := make([]*structs.Plugin, len(plugins))
+	for i, p := range plugins {
+		res[i] = convert.ToPlugin(p)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// InstallPlugin installs a plugin from the marketplace
+func InstallPlugin(ctx *context.PrivateContext) {
+	opts := web.GetForm(ctx).(*private.PluginInstallOptions)
+	version := opts.Version
+	if version == "" {
+		version = "latest"
+	}
+
+	if err := plugin_service.GetManager().Install(ctx, nil, opts.ID, version); err != nil {
+		respondPluginError(ctx, err)
+		return
+	}
+	p, err := plugin_model.GetPluginByID(ctx, opts.ID)
+	if err != nil {
+		respondPluginError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, private.Response{
+		UserMsg: fmt.Sprintf("Plugin %s %s installed, enable it with `gitea admin plugin enable --id %s`", p.PluginID, p.Version, p.PluginID),
+	})
+}
+
+// EnablePlugin enables an installed plugin
+func EnablePlugin(ctx *context.PrivateContext) {
+	opts := web.GetForm(ctx).(*private.PluginEnableOptions)
+	pluginID := ctx.PathParam("id")
+
+	manager := plugin_service.GetManager()
+	if opts.ApprovePermissions {
+		if err := manager.ApprovePermissions(ctx, nil, pluginID); err != nil {
+			respondPluginError(ctx, err)
+			return
+		}
+	}
+	if err := manager.Enable(ctx, nil, pluginID); err != nil {
+		respondPluginError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, private.Response{
+		UserMsg: fmt.Sprintf("Plugin %s enabled", pluginID),
+	})
+}
+
+// DisablePlugin disables an installed plugin
+func DisablePlugin(ctx *context.PrivateContext) {
+	pluginID := ctx.PathParam("id")
+	if err := plugin_service.GetManager().Disable(ctx, nil, pluginID); err != nil {
+		respondPluginError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, private.Response{
+		UserMsg: fmt.Sprintf("Plugin %s disabled", pluginID),
+	})
+}
+
+// GetPluginConfig returns the configuration of a plugin, secret values are never returned
+func GetPluginConfig(ctx *context.PrivateContext) {
+	cfg, err := plugin_service.GetManager().GetConfig(ctx, ctx.PathParam("id"))
+	if err != nil {
+		respondPluginError(ctx, err)
+		return
+	}
+	res := &structs.PluginConfig{Config: cfg.Config, SecretsSet: []string{}}
+	for _, f := range cfg.Fields {
+		if cfg.SecretSet[f.Name] {
+			res.SecretsSet = append(res.SecretsSet, f.Name)
+		}
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// EditPluginConfig patches the configuration of a plugin
+func EditPluginConfig(ctx *context.PrivateContext) {
+	opts := web.GetForm(ctx).(*structs.EditPluginConfigOption)
+	pluginID := ctx.PathParam("id")
+	if err := plugin_service.GetManager().PatchConfig(ctx, nil, pluginID, opts.Config); err != nil {
+		respondPluginError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, private.Response{
+		UserMsg: fmt.Sprintf("Configuration of plugin %s updated", pluginID),
+	})
+}
+
+// UninstallPlugin uninstalls a plugin, optionally dropping its data
+func UninstallPlugin(ctx *context.PrivateContext) {
+	opts := web.GetForm(ctx).(*private.PluginUninstallOptions)
+	pluginID := ctx.PathParam("id")
+	if err := plugin_service.GetManager().Uninstall(ctx, nil, pluginID, opts.PurgeData); err != nil {
+		respondPluginError(ctx, err)
+		return
+	}
+	msg := fmt.Sprintf("Plugin %s uninstalled", pluginID)
+	if opts.PurgeData {
+		msg += ", its data has been purged"
+	}
+	ctx.JSON(http.StatusOK, private.Response{UserMsg: msg})
+}
+
+// UpgradePlugin upgrades a plugin from the marketplace
+func UpgradePlugin(ctx *context.PrivateContext) {
+	opts := web.GetForm(ctx).(*private.PluginUpgradeOptions)
+	version := opts.Version
+	if version == "" {
+		version = "latest"
+	}
+
+	p, err := plugin_service.GetManager().Upgrade(ctx, nil, ctx.PathParam("id"), version)
+	if err != nil {
+		respondPluginError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, private.Response{
+		UserMsg: fmt.Sprintf("Plugin %s upgraded to %s, previous version %s kept for rollback", p.PluginID, p.Version, p.PreviousVersion),
+	})
+}
+
+// RollbackPlugin rolls a plugin back to the version kept by the last upgrade
+func RollbackPlugin(ctx *context.PrivateContext) {
+	p, err := plugin_service.GetManager().Rollback(ctx, nil, ctx.PathParam("id"))
+	if err != nil {
+		respondPluginError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, private.Response{
+		UserMsg: fmt.Sprintf("Plugin %s rolled back to %s", p.PluginID, p.Version),
+	})
+}
+
+func respondPluginError(ctx *context.PrivateContext, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case plugin_model.IsErrPluginNotExist(err),
+		plugin_model.IsErrPluginNoPreviousVersion(err),
+		plugin_model.IsErrMarketPluginNotExist(err):
+		status = http.StatusNotFound
+	case plugin_model.IsErrPluginVersionInstalled(err),
+		plugin_model.IsErrPluginHotUpgradeUnsupported(err),
+		plugin_model.IsErrPluginSchemaDowngrade(err),
+		plugin_model.IsErrPluginIncompatible(err),
+		plugin_model.IsErrPluginDependencyNotSatisfied(err),
+		plugin_model.IsErrPackageVerification(err),
+		plugin_model.IsErrPluginConfigInvalid(err):
+		status = http.StatusUnprocessableEntity
+	case plugin_model.IsErrPermissionsNotApproved(err),
+		plugin_model.IsErrPluginHasDependents(err),
+		plugin_model.IsErrPluginAlreadyExist(err):
+		status = http.StatusConflict
+	}
+	ctx.JSON(status, private.Response{
+		Err:     err.Error(),
+		UserMsg: err.Error(),
+	})
+}
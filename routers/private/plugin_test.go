@@ -0,0 +1,29 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/services/contexttest"
+	plugin_service "code.gitea.io/gitea/services/plugin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginManagerRequired(t *testing.T) {
+	require.Nil(t, plugin_service.GetManager())
+
+	ctx, resp := contexttest.MockPrivateContext(t, "/plugins")
+	pluginManagerRequired(ctx)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+
+	var res private.Response
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+	assert.Contains(t, res.UserMsg, "plugin system is not running")
+}
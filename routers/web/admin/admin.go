@@ -180,7 +180,7 @@ func DashboardPost(ctx *context.Context) {
 			task := cron.GetTask(form.Op)
 			if task != nil {
 				go task.RunWithUser(ctx.Doer, nil)
-				ctx.Flash.Success(ctx.Tr("admin.dashboard.task.started", ctx.Tr("admin.dashboard."+form.Op)))
+				ctx.Flash.Success(ctx.Tr("admin.dashboard.task.started", task.DisplayName(ctx.Locale)))
 			} else {
 				ctx.Flash.Error(ctx.Tr("admin.dashboard.task.unknown", form.Op))
 			}
@@ -0,0 +1,50 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/license"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/services/context"
+)
+
+const tplLicenseSeats templates.TplName = "admin/license/seats"
+
+// LicenseSeats 所有用户的多席位授权及席位使用情况
+func LicenseSeats(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.license.seats")
+	ctx.Data["PageIsAdminLicenseSeats"] = true
+
+	page := max(ctx.FormInt("page"), 1)
+	licenses, total, err := license.SearchLicenses(ctx, &license.SearchLicensesOptions{
+		ListOptions: db.ListOptions{Page: page, PageSize: setting.UI.Admin.UserPagingNum},
+	})
+	if err != nil {
+		ctx.ServerError("SearchLicenses", err)
+		return
+	}
+
+	ownerIDs := make([]int64, 0, len(licenses))
+	for _, l := range licenses {
+		ownerIDs = append(ownerIDs, l.UserID)
+	}
+	owners, err := user_model.GetUsersMapByIDs(ctx, ownerIDs)
+	if err != nil {
+		ctx.ServerError("GetUsersMapByIDs", err)
+		return
+	}
+
+	ctx.Data["Licenses"] = licenses
+	ctx.Data["Owners"] = owners
+	ctx.Data["Total"] = total
+
+	pager := context.NewPagination(int(total), setting.UI.Admin.UserPagingNum, page, 5)
+	ctx.Data["Page"] = pager
+	ctx.HTML(http.StatusOK, tplLicenseSeats)
+}
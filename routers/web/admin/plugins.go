@@ -4,30 +4,36 @@
 package admin
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
+	"strings"
 
 	plugin_model "code.gitea.io/gitea/models/plugin"
-	"code.gitea.io/gitea/modules/base"
-	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/services/context"
 	plugin_service "code.gitea.io/gitea/services/plugin"
 )
 
 const (
-	tplPluginsList   = base.TplName("admin/plugins/list")
-	tplPluginsMarket = base.TplName("admin/plugins/market")
+	tplPluginsList      templates.TplName = "admin/plugins/list"
+	tplPluginsMarket    templates.TplName = "admin/plugins/market"
+	tplPluginsUninstall templates.TplName = "admin/plugins/uninstall"
 )
 
+// PluginManagerRequired 插件管理器未初始化（如插件系统启动失败）时拒绝插件管理页面的请求
+func PluginManagerRequired(ctx *context.Context) {
+	if plugin_service.GetManager() == nil {
+		ctx.ServerError("GetManager", errors.New("plugin manager not initialized"))
+	}
+}
+
 // PluginsList 插件列表页面
 func PluginsList(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("admin.plugins.title")
 	ctx.Data["PageIsAdminPlugins"] = true
 
 	manager := plugin_service.GetManager()
-	if manager == nil {
-		ctx.ServerError("GetManager", fmt.Errorf("plugin manager not initialized"))
-		return
-	}
 
 	// 获取已安装插件
 	installed, err := manager.ListInstalled(ctx)
@@ -36,45 +42,63 @@ func PluginsList(ctx *context.Context) {
 		return
 	}
 
+	graph, err := manager.DependencyGraph(ctx)
+	if err != nil {
+		ctx.ServerError("DependencyGraph", err)
+		return
+	}
+
+	pending, err := manager.PendingPermissions(ctx)
+	if err != nil {
+		ctx.ServerError("PendingPermissions", err)
+		return
+	}
+
 	ctx.Data["Plugins"] = installed
+	ctx.Data["DependencyGraph"] = graph
+	ctx.Data["PendingPermissions"] = pending
+	ctx.Data["EventStats"] = plugin_service.GetEventStats()
+	ctx.Data["Health"] = plugin_service.GetHealth()
 	ctx.HTML(http.StatusOK, tplPluginsList)
 }
 
-// PluginsMarket 插件市场页面
+// PluginsMarket 插件市场页面，数据来自各注册表的缓存索引，支持搜索和筛选
 func PluginsMarket(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("admin.plugins.market")
 	ctx.Data["PageIsAdminPlugins"] = true
 
 	manager := plugin_service.GetManager()
-	if manager == nil {
-		ctx.ServerError("GetManager", fmt.Errorf("plugin manager not initialized"))
-		return
-	}
 
-	// 获取可用插件
-	available, err := manager.ListAvailable()
-	if err != nil {
-		ctx.Flash.Error(ctx.Tr("admin.plugins.market_error") + ": " + err.Error())
-		available = make([]*plugin_model.PluginMetadata, 0)
+	opts := plugin_service.MarketSearchOptions{
+		Keyword:     ctx.FormTrim("q"),
+		Category:    ctx.FormTrim("category"),
+		Registry:    ctx.FormTrim("registry"),
+		UpdatesOnly: ctx.FormBool("updates"),
 	}
-
-	// 获取已安装插件列表（用于标记）
-	installed, err := manager.ListInstalled(ctx)
+	result, err := manager.SearchMarket(ctx, opts)
 	if err != nil {
-		ctx.ServerError("ListInstalled", err)
+		ctx.ServerError("SearchMarket", err)
 		return
 	}
 
-	installedMap := make(map[string]bool)
-	for _, p := range installed {
-		installedMap[p.PluginID] = true
-	}
-
-	ctx.Data["AvailablePlugins"] = available
-	ctx.Data["InstalledMap"] = installedMap
+	ctx.Data["Keyword"] = opts.Keyword
+	ctx.Data["Category"] = opts.Category
+	ctx.Data["Registry"] = opts.Registry
+	ctx.Data["UpdatesOnly"] = opts.UpdatesOnly
+	ctx.Data["Market"] = result
 	ctx.HTML(http.StatusOK, tplPluginsMarket)
 }
 
+// PluginsMarketRefresh 立即刷新插件市场索引
+func PluginsMarketRefresh(ctx *context.Context) {
+	if err := plugin_service.GetManager().RefreshMarket(ctx); err != nil {
+		ctx.Flash.Error(ctx.Tr("admin.plugins.market_error", err.Error()))
+	} else {
+		ctx.Flash.Success(ctx.Tr("admin.plugins.market_refreshed"))
+	}
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/market")
+}
+
 // PluginInstall 安装插件
 func PluginInstall(ctx *context.Context) {
 	pluginID := ctx.FormString("plugin_id")
@@ -82,7 +106,7 @@ func PluginInstall(ctx *context.Context) {
 
 	if pluginID == "" {
 		ctx.Flash.Error(ctx.Tr("admin.plugins.plugin_id_required"))
-		ctx.Redirect("/admin/plugins/market")
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/market")
 		return
 	}
 
@@ -91,74 +115,179 @@ func PluginInstall(ctx *context.Context) {
 	}
 
 	manager := plugin_service.GetManager()
-	if manager == nil {
-		ctx.Flash.Error("Plugin manager not initialized")
-		ctx.Redirect("/admin/plugins/market")
-		return
-	}
 
-	if err := manager.Install(ctx, pluginID, version); err != nil {
+	if err := manager.Install(ctx, ctx.Doer, pluginID, version); err != nil {
 		if plugin_model.IsErrPluginAlreadyExist(err) {
 			ctx.Flash.Error(ctx.Tr("admin.plugins.already_installed"))
+		} else if plugin_model.IsErrPluginIncompatible(err) || plugin_model.IsErrPluginDependencyNotSatisfied(err) {
+			flashDependencyError(ctx, err)
+		} else if plugin_model.IsErrPackageVerification(err) {
+			ctx.Flash.Error(ctx.Tr("admin.plugins.verification_failed", err.(plugin_model.ErrPackageVerification).Reason))
+		} else if plugin_model.IsErrMarketPluginNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("admin.plugins.market_not_found", pluginID, version))
 		} else {
-			ctx.Flash.Error(ctx.Tr("admin.plugins.install_failed") + ": " + err.Error())
+			ctx.Flash.Error(ctx.Tr("admin.plugins.install_failed", err.Error()))
 		}
-		ctx.Redirect("/admin/plugins/market")
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/market")
 		return
 	}
 
 	ctx.Flash.Success(ctx.Tr("admin.plugins.install_success"))
-	ctx.Redirect("/admin/plugins")
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
+}
+
+// PluginUninstallPage 卸载确认页面，列出可一并删除的插件数据表
+func PluginUninstallPage(ctx *context.Context) {
+	p, err := plugin_model.GetPluginByID(ctx, ctx.PathParam("id"))
+	if err != nil {
+		if plugin_model.IsErrPluginNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.ServerError("GetPluginByID", err)
+		}
+		return
+	}
+
+	tables, err := plugin_service.GetManager().PluginTables(ctx, p.PluginID)
+	if err != nil {
+		ctx.ServerError("PluginTables", err)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("admin.plugins.uninstall_title", p.Name)
+	ctx.Data["PageIsAdminPlugins"] = true
+	ctx.Data["Plugin"] = p
+	ctx.Data["Tables"] = tables
+	ctx.HTML(http.StatusOK, tplPluginsUninstall)
 }
 
-// PluginUninstall 卸载插件
+// PluginUninstall 卸载插件，删除数据表须输入插件 ID 确认
 func PluginUninstall(ctx *context.Context) {
-	pluginID := ctx.Params("id")
+	pluginID := ctx.PathParam("id")
 
 	manager := plugin_service.GetManager()
-	if manager == nil {
-		ctx.Flash.Error("Plugin manager not initialized")
-		ctx.Redirect("/admin/plugins")
+
+	purgeData := ctx.FormBool("purge_data")
+	if purgeData && ctx.FormTrim("confirm_plugin_id") != pluginID {
+		ctx.Flash.Error(ctx.Tr("admin.plugins.purge_confirm_mismatch"))
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/" + pluginID + "/uninstall")
 		return
 	}
 
-	if err := manager.Uninstall(ctx, pluginID); err != nil {
-		ctx.Flash.Error(ctx.Tr("admin.plugins.uninstall_failed") + ": " + err.Error())
+	if err := manager.Uninstall(ctx, ctx.Doer, pluginID, purgeData); err != nil {
+		if plugin_model.IsErrPluginHasDependents(err) {
+			ctx.Flash.Error(ctx.Tr("admin.plugins.has_dependents", strings.Join(err.(plugin_model.ErrPluginHasDependents).Dependents, ", ")))
+		} else {
+			ctx.Flash.Error(ctx.Tr("admin.plugins.uninstall_failed", err.Error()))
+		}
 	} else {
 		ctx.Flash.Success(ctx.Tr("admin.plugins.uninstall_success"))
 	}
 
-	ctx.Redirect("/admin/plugins")
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
 }
 
 // PluginToggle 启用/禁用插件
 func PluginToggle(ctx *context.Context) {
-	pluginID := ctx.Params("id")
+	pluginID := ctx.PathParam("id")
 	action := ctx.FormString("action")
 
 	manager := plugin_service.GetManager()
-	if manager == nil {
-		ctx.Flash.Error("Plugin manager not initialized")
-		ctx.Redirect("/admin/plugins")
-		return
-	}
 
 	var err error
 	if action == "enable" {
-		err = manager.Enable(ctx, pluginID)
+		err = manager.Enable(ctx, ctx.Doer, pluginID)
 	} else if action == "disable" {
-		err = manager.Disable(ctx, pluginID)
+		err = manager.Disable(ctx, ctx.Doer, pluginID)
 	} else {
 		ctx.Flash.Error(ctx.Tr("admin.plugins.invalid_action"))
-		ctx.Redirect("/admin/plugins")
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
+		return
+	}
+
+	if plugin_model.IsErrPermissionsNotApproved(err) {
+		// 启用前须由管理员确认插件申请的权限
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/" + pluginID + "/permissions")
 		return
 	}
 
 	if err != nil {
-		ctx.Flash.Error(ctx.Tr("admin.plugins.toggle_failed") + ": " + err.Error())
+		switch {
+		case plugin_model.IsErrPluginHasDependents(err):
+			ctx.Flash.Error(ctx.Tr("admin.plugins.has_dependents", strings.Join(err.(plugin_model.ErrPluginHasDependents).Dependents, ", ")))
+		case plugin_model.IsErrPluginIncompatible(err), plugin_model.IsErrPluginDependencyNotSatisfied(err), plugin_model.IsErrPluginDependencyInvalid(err):
+			flashDependencyError(ctx, err)
+		default:
+			ctx.Flash.Error(ctx.Tr("admin.plugins.toggle_failed", err.Error()))
+		}
 	} else {
 		ctx.Flash.Success(ctx.Tr("admin.plugins.toggle_success"))
 	}
 
-	ctx.Redirect("/admin/plugins")
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
+}
+
+// flashDependencyError 提示 Gitea 版本或依赖插件不满足，或依赖声明无效
+func flashDependencyError(ctx *context.Context, err error) {
+	switch e := err.(type) {
+	case plugin_model.ErrPluginIncompatible:
+		ctx.Flash.Error(ctx.Tr("admin.plugins.incompatible_gitea", e.Constraint, e.GiteaVersion))
+	case plugin_model.ErrPluginDependencyNotSatisfied:
+		ctx.Flash.Error(ctx.Tr("admin.plugins.dependency_not_satisfied", strings.TrimSpace(e.DependencyID+" "+e.Constraint), e.Reason))
+	case plugin_model.ErrPluginDependencyInvalid:
+		ctx.Flash.Error(ctx.Tr("admin.plugins.dependency_invalid", e.Dependency))
+	}
+}
+
+// PluginUpgrade 从插件市场升级插件
+func PluginUpgrade(ctx *context.Context) {
+	pluginID := ctx.PathParam("id")
+	version := ctx.FormTrim("version")
+	if version == "" {
+		version = "latest"
+	}
+
+	p, err := plugin_service.GetManager().Upgrade(ctx, ctx.Doer, pluginID, version)
+	if err != nil {
+		flashVersionSwitchError(ctx, "admin.plugins.upgrade_failed", err)
+	} else {
+		ctx.Flash.Success(ctx.Tr("admin.plugins.upgrade_success", p.Name, p.Version))
+	}
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
+}
+
+// PluginRollback 回滚到升级前的版本
+func PluginRollback(ctx *context.Context) {
+	pluginID := ctx.PathParam("id")
+
+	p, err := plugin_service.GetManager().Rollback(ctx, ctx.Doer, pluginID)
+	if err != nil {
+		flashVersionSwitchError(ctx, "admin.plugins.rollback_failed", err)
+	} else {
+		ctx.Flash.Success(ctx.Tr("admin.plugins.rollback_success", p.Name, p.Version))
+	}
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
+}
+
+func flashVersionSwitchError(ctx *context.Context, failedKey string, err error) {
+	switch {
+	case plugin_model.IsErrPluginIncompatible(err), plugin_model.IsErrPluginDependencyNotSatisfied(err):
+		flashDependencyError(ctx, err)
+	case plugin_model.IsErrPluginVersionInstalled(err):
+		ctx.Flash.Error(ctx.Tr("admin.plugins.version_installed", err.(plugin_model.ErrPluginVersionInstalled).Version))
+	case plugin_model.IsErrPluginNoPreviousVersion(err):
+		ctx.Flash.Error(ctx.Tr("admin.plugins.no_previous_version"))
+	case plugin_model.IsErrPluginHotUpgradeUnsupported(err):
+		ctx.Flash.Error(ctx.Tr("admin.plugins.hot_upgrade_unsupported"))
+	case plugin_model.IsErrPluginSchemaDowngrade(err):
+		e := err.(plugin_model.ErrPluginSchemaDowngrade)
+		ctx.Flash.Error(ctx.Tr("admin.plugins.schema_downgrade", e.SchemaVersion, e.Migrations))
+	case plugin_model.IsErrPackageVerification(err):
+		ctx.Flash.Error(ctx.Tr("admin.plugins.verification_failed", err.(plugin_model.ErrPackageVerification).Reason))
+	case plugin_model.IsErrMarketPluginNotExist(err):
+		e := err.(plugin_model.ErrMarketPluginNotExist)
+		ctx.Flash.Error(ctx.Tr("admin.plugins.market_not_found", e.PluginID, e.Version))
+	default:
+		ctx.Flash.Error(ctx.Tr(failedKey, err.Error()))
+	}
 }
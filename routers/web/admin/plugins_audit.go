@@ -0,0 +1,59 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/context"
+)
+
+const tplPluginsAudit templates.TplName = "admin/plugins/audit"
+
+// PluginAudit 插件审计日志页面，可按插件、操作、操作者和日期筛选
+func PluginAudit(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.plugins.audit")
+	ctx.Data["PageIsAdminPlugins"] = true
+
+	page := max(ctx.FormInt("page"), 1)
+	opts := plugin_model.FindAuditOptions{
+		ListOptions: db.ListOptions{Page: page, PageSize: setting.UI.Admin.NoticePagingNum},
+		PluginID:    ctx.FormTrim("plugin"),
+		Action:      ctx.FormTrim("action"),
+		DoerName:    ctx.FormTrim("doer"),
+	}
+	// 日期按服务器时区解析，结束日期包含当天
+	if since, err := time.ParseInLocation(time.DateOnly, ctx.FormTrim("since"), setting.DefaultUILocation); err == nil {
+		opts.Since = timeutil.TimeStamp(since.Unix())
+	}
+	if until, err := time.ParseInLocation(time.DateOnly, ctx.FormTrim("until"), setting.DefaultUILocation); err == nil {
+		opts.Before = timeutil.TimeStamp(until.AddDate(0, 0, 1).Unix())
+	}
+
+	audits, total, err := db.FindAndCount[plugin_model.Audit](ctx, opts)
+	if err != nil {
+		ctx.ServerError("FindAudits", err)
+		return
+	}
+
+	ctx.Data["Audits"] = audits
+	ctx.Data["Total"] = total
+	ctx.Data["Actions"] = plugin_model.AuditActions
+	ctx.Data["FilterPlugin"] = opts.PluginID
+	ctx.Data["FilterAction"] = opts.Action
+	ctx.Data["FilterDoer"] = opts.DoerName
+	ctx.Data["FilterSince"] = ctx.FormTrim("since")
+	ctx.Data["FilterUntil"] = ctx.FormTrim("until")
+
+	pager := context.NewPagination(int(total), setting.UI.Admin.NoticePagingNum, page, 5)
+	pager.AddParamFromRequest(ctx.Req)
+	ctx.Data["Page"] = pager
+	ctx.HTML(http.StatusOK, tplPluginsAudit)
+}
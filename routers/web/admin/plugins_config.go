@@ -0,0 +1,71 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/services/context"
+	plugin_service "code.gitea.io/gitea/services/plugin"
+)
+
+const tplPluginsConfig templates.TplName = "admin/plugins/config"
+
+// PluginConfig 由 config_schema 生成的插件配置页面
+func PluginConfig(ctx *context.Context) {
+	cfg, err := plugin_service.GetManager().GetConfig(ctx, ctx.PathParam("id"))
+	if err != nil {
+		if plugin_model.IsErrPluginNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.ServerError("GetConfig", err)
+		}
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("admin.plugins.config_title", cfg.Plugin.Name)
+	ctx.Data["PageIsAdminPlugins"] = true
+	ctx.Data["Config"] = cfg
+	ctx.HTML(http.StatusOK, tplPluginsConfig)
+}
+
+// PluginConfigPost 校验并保存插件配置
+func PluginConfigPost(ctx *context.Context) {
+	pluginID := ctx.PathParam("id")
+	redirect := setting.AppSubURL + "/-/admin/plugins/" + pluginID + "/config"
+
+	cfg, err := plugin_service.GetManager().GetConfig(ctx, pluginID)
+	if err != nil {
+		if plugin_model.IsErrPluginNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.ServerError("GetConfig", err)
+		}
+		return
+	}
+
+	opts := plugin_service.UpdateConfigOptions{Values: make(map[string]string, len(cfg.Fields))}
+	for _, f := range cfg.Fields {
+		opts.Values[f.Name] = ctx.FormString("cfg_" + f.Name)
+		if f.Secret && ctx.FormBool("clear_"+f.Name) {
+			opts.ClearSecrets = append(opts.ClearSecrets, f.Name)
+		}
+	}
+
+	if err := plugin_service.GetManager().UpdateConfig(ctx, ctx.Doer, pluginID, opts); err != nil {
+		if e, ok := err.(plugin_model.ErrPluginConfigInvalid); ok && e.Field != "" {
+			ctx.Flash.Error(ctx.Tr("admin.plugins.config_invalid", e.Field, e.Reason))
+		} else {
+			ctx.Flash.Error(ctx.Tr("admin.plugins.config_failed", err.Error()))
+		}
+		ctx.Redirect(redirect)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("admin.plugins.config_success"))
+	ctx.Redirect(redirect)
+}
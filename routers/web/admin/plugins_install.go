@@ -0,0 +1,100 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/services/context"
+	plugin_service "code.gitea.io/gitea/services/plugin"
+)
+
+const tplPluginsInstall templates.TplName = "admin/plugins/install"
+
+// PluginInstallPage 从上传文件或仓库发布版本安装插件的页面
+func PluginInstallPage(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.plugins.install_local")
+	ctx.Data["PageIsAdminPlugins"] = true
+	ctx.Data["MaxUploadSize"] = setting.PluginMaxUploadSize
+	ctx.HTML(http.StatusOK, tplPluginsInstall)
+}
+
+// PluginInstallUpload 安装上传的插件包
+func PluginInstallUpload(ctx *context.Context) {
+	ctx.Req.Body = http.MaxBytesReader(ctx.Resp, ctx.Req.Body, setting.PluginMaxUploadSize<<20)
+	file, _, err := ctx.Req.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.Flash.Error(ctx.Tr("admin.plugins.upload_too_large", setting.PluginMaxUploadSize))
+		} else {
+			ctx.Flash.Error(ctx.Tr("admin.plugins.upload_required"))
+		}
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/install")
+		return
+	}
+	defer file.Close()
+
+	p, err := plugin_service.GetManager().InstallFromFile(ctx, ctx.Doer, file)
+	if err != nil {
+		flashInstallError(ctx, err)
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/install")
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("admin.plugins.install_local_success", p.Name, p.Version))
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
+}
+
+// PluginInstallRelease 从仓库发布版本的附件安装插件
+func PluginInstallRelease(ctx *context.Context) {
+	opts := plugin_service.InstallFromReleaseOptions{
+		Owner:      ctx.FormTrim("owner"),
+		Repo:       ctx.FormTrim("repo"),
+		TagName:    ctx.FormTrim("tag_name"),
+		Attachment: ctx.FormTrim("attachment"),
+	}
+	if opts.Owner == "" || opts.Repo == "" || opts.TagName == "" {
+		ctx.Flash.Error(ctx.Tr("admin.plugins.release_required"))
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/install")
+		return
+	}
+
+	p, err := plugin_service.GetManager().InstallFromRelease(ctx, ctx.Doer, opts)
+	if err != nil {
+		switch {
+		case repo_model.IsErrRepoNotExist(err):
+			ctx.Flash.Error(ctx.Tr("admin.plugins.release_repo_not_exist", opts.Owner+"/"+opts.Repo))
+		case repo_model.IsErrReleaseNotExist(err):
+			ctx.Flash.Error(ctx.Tr("admin.plugins.release_not_exist", opts.TagName))
+		default:
+			flashInstallError(ctx, err)
+		}
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/install")
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("admin.plugins.install_local_success", p.Name, p.Version))
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
+}
+
+func flashInstallError(ctx *context.Context, err error) {
+	switch {
+	case plugin_model.IsErrPluginAlreadyExist(err):
+		ctx.Flash.Error(ctx.Tr("admin.plugins.already_installed"))
+	case plugin_model.IsErrPackageVerification(err):
+		ctx.Flash.Error(ctx.Tr("admin.plugins.verification_failed", err.(plugin_model.ErrPackageVerification).Reason))
+	case plugin_model.IsErrPluginIncompatible(err), plugin_model.IsErrPluginDependencyNotSatisfied(err), plugin_model.IsErrPluginDependencyInvalid(err):
+		flashDependencyError(ctx, err)
+	case plugin_model.IsErrPluginInvalidID(err):
+		ctx.Flash.Error(ctx.Tr("admin.plugins.invalid_plugin_id", err.(plugin_model.ErrPluginInvalidID).PluginID))
+	default:
+		ctx.Flash.Error(ctx.Tr("admin.plugins.install_failed", err.Error()))
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/services/context"
+)
+
+const tplPluginsKeys templates.TplName = "admin/plugins/keys"
+
+// PluginKeys 受信任的插件发布者密钥页面
+func PluginKeys(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.plugins.keys")
+	ctx.Data["PageIsAdminPlugins"] = true
+
+	keys, err := plugin_model.ListTrustedKeys(ctx)
+	if err != nil {
+		ctx.ServerError("ListTrustedKeys", err)
+		return
+	}
+
+	ctx.Data["Keys"] = keys
+	ctx.Data["RequireSignature"] = setting.PluginRequireSignature
+	ctx.HTML(http.StatusOK, tplPluginsKeys)
+}
+
+// PluginKeysAdd 添加受信任的发布者密钥
+func PluginKeysAdd(ctx *context.Context) {
+	name := ctx.FormTrim("name")
+	keyType := ctx.FormString("key_type")
+	content := ctx.FormString("content")
+
+	if name == "" || content == "" {
+		ctx.Flash.Error(ctx.Tr("admin.plugins.key_required"))
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/keys")
+		return
+	}
+
+	if _, err := plugin_model.AddTrustedKey(ctx, name, keyType, content); err != nil {
+		switch {
+		case plugin_model.IsErrTrustedKeyAlreadyExist(err):
+			ctx.Flash.Error(ctx.Tr("admin.plugins.key_already_exists"))
+		case plugin_model.IsErrTrustedKeyInvalid(err):
+			ctx.Flash.Error(ctx.Tr("admin.plugins.key_invalid", err.(plugin_model.ErrTrustedKeyInvalid).Reason))
+		default:
+			ctx.ServerError("AddTrustedKey", err)
+			return
+		}
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/keys")
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("admin.plugins.key_add_success"))
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/keys")
+}
+
+// PluginKeysDelete 删除受信任的发布者密钥
+func PluginKeysDelete(ctx *context.Context) {
+	if err := plugin_model.DeleteTrustedKey(ctx, ctx.PathParamInt64("id")); err != nil {
+		ctx.ServerError("DeleteTrustedKey", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("admin.plugins.key_delete_success"))
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins/keys")
+}
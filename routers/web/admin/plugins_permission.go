@@ -0,0 +1,80 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+	"slices"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/services/context"
+	plugin_service "code.gitea.io/gitea/services/plugin"
+)
+
+const tplPluginsPermissions templates.TplName = "admin/plugins/permissions"
+
+// pluginPermissionItem 授权页面中的一项权限
+type pluginPermissionItem struct {
+	Name     string
+	Known    bool
+	Approved bool
+}
+
+// PluginPermissions 插件权限授权页面，列出插件申请的宿主能力
+func PluginPermissions(ctx *context.Context) {
+	req, err := plugin_service.GetManager().PermissionRequest(ctx, ctx.PathParam("id"))
+	if err != nil {
+		if plugin_model.IsErrPluginNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.ServerError("PermissionRequest", err)
+		}
+		return
+	}
+
+	items := make([]pluginPermissionItem, 0, len(req.Declared))
+	for _, name := range req.Declared {
+		items = append(items, pluginPermissionItem{
+			Name:     name,
+			Known:    slices.Contains(plugin_model.KnownPermissions, name),
+			Approved: !slices.Contains(req.Unapproved, name),
+		})
+	}
+
+	ctx.Data["Title"] = ctx.Tr("admin.plugins.permissions_title", req.Plugin.Name)
+	ctx.Data["PageIsAdminPlugins"] = true
+	ctx.Data["Plugin"] = req.Plugin
+	ctx.Data["PermissionItems"] = items
+	ctx.Data["HasUnapproved"] = len(req.Unapproved) > 0
+	ctx.HTML(http.StatusOK, tplPluginsPermissions)
+}
+
+// PluginPermissionsPost 批准插件申请的权限，并按需启用插件
+func PluginPermissionsPost(ctx *context.Context) {
+	pluginID := ctx.PathParam("id")
+	manager := plugin_service.GetManager()
+
+	if err := manager.ApprovePermissions(ctx, ctx.Doer, pluginID); err != nil {
+		ctx.Flash.Error(ctx.Tr("admin.plugins.permissions_approve_failed", err.Error()))
+		ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
+		return
+	}
+
+	if ctx.FormBool("enable") {
+		if err := manager.Enable(ctx, ctx.Doer, pluginID); err != nil {
+			if plugin_model.IsErrPluginIncompatible(err) || plugin_model.IsErrPluginDependencyNotSatisfied(err) {
+				flashDependencyError(ctx, err)
+			} else {
+				ctx.Flash.Error(ctx.Tr("admin.plugins.toggle_failed", err.Error()))
+			}
+			ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
+			return
+		}
+	}
+
+	ctx.Flash.Success(ctx.Tr("admin.plugins.permissions_approved"))
+	ctx.Redirect(setting.AppSubURL + "/-/admin/plugins")
+}
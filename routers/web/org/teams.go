@@ -335,6 +335,7 @@ func NewTeamPost(ctx *context.Context) {
 		AccessMode:              teamPermission,
 		IncludesAllRepositories: includesAllRepositories,
 		CanCreateOrgRepo:        form.CanCreateOrgRepo,
+		CanManageLicenses:       form.CanManageLicenses,
 	}
 
 	units := make([]*org_model.TeamUnit, 0, len(unitPerms))
@@ -514,8 +515,10 @@ func EditTeamPost(ctx *context.Context) {
 			t.IncludesAllRepositories = includesAllRepositories
 		}
 		t.CanCreateOrgRepo = form.CanCreateOrgRepo
+		t.CanManageLicenses = form.CanManageLicenses
 	} else {
 		t.CanCreateOrgRepo = true
+		t.CanManageLicenses = true
 	}
 
 	t.Description = form.Description
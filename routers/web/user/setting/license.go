@@ -0,0 +1,212 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/services/context"
+	license_service "code.gitea.io/gitea/services/license"
+)
+
+const (
+	tplSettingsLicense      templates.TplName = "user/settings/license"
+	tplSettingsLicenseNew   templates.TplName = "user/settings/license_new"
+	tplSettingsLicenseEdit  templates.TplName = "user/settings/license_edit"
+	tplSettingsLicenseSeats templates.TplName = "user/settings/license_seats"
+)
+
+// License 授权设备列表页面
+func License(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings.license.title")
+	ctx.Data["PageIsSettingsLicense"] = true
+
+	page := max(ctx.FormInt("page"), 1)
+	devices, count, err := license.SearchDevices(ctx, &license.SearchDevicesOptions{
+		ListOptions: db.ListOptions{Page: page, PageSize: setting.UI.User.RepoPagingNum},
+		UserID:      ctx.Doer.ID, // 只查询当前用户的设备
+	})
+	if err != nil {
+		ctx.ServerError("SearchDevices", err)
+		return
+	}
+
+	ctx.Data["Devices"] = devices
+	ctx.Data["Total"] = count
+
+	pager := context.NewPagination(int(count), setting.UI.User.RepoPagingNum, page, 5)
+	ctx.Data["Page"] = pager
+	ctx.HTML(http.StatusOK, tplSettingsLicense)
+}
+
+// LicenseNew 新建授权设备页面
+func LicenseNew(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings.license.new")
+	ctx.Data["PageIsSettingsLicense"] = true
+	ctx.HTML(http.StatusOK, tplSettingsLicenseNew)
+}
+
+// LicenseNewPost 处理新建授权设备
+func LicenseNewPost(ctx *context.Context) {
+	machineCode := ctx.FormString("machine_code")
+	if machineCode == "" {
+		ctx.Flash.Error(ctx.Tr("settings.license.machine_code_required"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/license/new")
+		return
+	}
+
+	device, err := license_service.CreateDevice(ctx, &license_service.CreateDeviceOptions{
+		UserID:      ctx.Doer.ID,
+		MachineCode: machineCode,
+		MachineName: ctx.FormString("machine_name"),
+		ExpiryDays:  ctx.FormInt("expiry_days"),
+		Remarks:     ctx.FormString("remarks"),
+	})
+	if license.IsErrDeviceAlreadyExist(err) {
+		ctx.Flash.Error(ctx.Tr("settings.license.device_already_exists"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/license/new")
+		return
+	} else if err != nil {
+		ctx.ServerError("CreateDevice", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.license.create_success"))
+	ctx.Flash.Info(fmt.Sprintf("%s: %s", ctx.Locale.TrString("settings.license.license_key"), license_service.FormatLicenseKey(device.LicenseKey)))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/license")
+}
+
+// LicenseEdit 编辑授权设备页面
+func LicenseEdit(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings.license.edit")
+	ctx.Data["PageIsSettingsLicense"] = true
+
+	device, err := license.GetDeviceByUserAndID(ctx, ctx.Doer.ID, ctx.PathParamInt64("id"))
+	if license.IsErrDeviceNotExist(err) {
+		ctx.NotFound(err)
+		return
+	} else if err != nil {
+		ctx.ServerError("GetDeviceByUserAndID", err)
+		return
+	}
+
+	ctx.Data["Device"] = device
+	ctx.HTML(http.StatusOK, tplSettingsLicenseEdit)
+}
+
+// LicenseEditPost 处理编辑授权设备
+func LicenseEditPost(ctx *context.Context) {
+	id := ctx.PathParamInt64("id")
+	isEnabled := ctx.FormBool("is_enabled")
+
+	var expiryDays *int
+	if days, err := strconv.Atoi(ctx.FormString("expiry_days")); err == nil {
+		expiryDays = &days
+	}
+
+	err := license_service.UpdateDevice(ctx, &license_service.UpdateDeviceOptions{
+		UserID:      ctx.Doer.ID,
+		ID:          id,
+		MachineName: ctx.FormString("machine_name"),
+		IsEnabled:   &isEnabled,
+		ExpiryDays:  expiryDays,
+		Remarks:     ctx.FormString("remarks"),
+	})
+	if license.IsErrDeviceNotExist(err) {
+		ctx.NotFound(err)
+		return
+	} else if err != nil {
+		ctx.ServerError("UpdateDevice", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.license.update_success"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/license")
+}
+
+// LicenseDelete 删除授权设备
+func LicenseDelete(ctx *context.Context) {
+	device, err := license.GetDeviceByUserAndID(ctx, ctx.Doer.ID, ctx.PathParamInt64("id"))
+	if license.IsErrDeviceNotExist(err) {
+		ctx.Flash.Error(ctx.Tr("settings.license.device_not_found"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/license")
+		return
+	} else if err != nil {
+		ctx.ServerError("GetDeviceByUserAndID", err)
+		return
+	}
+
+	if err := license.DeleteDevice(ctx, device.ID); err != nil {
+		ctx.ServerError("DeleteDevice", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("settings.license.delete_success"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/license")
+}
+
+// LicenseToggle 切换设备启用状态
+func LicenseToggle(ctx *context.Context) {
+	err := license_service.ToggleDevice(ctx, ctx.Doer.ID, ctx.PathParamInt64("id"))
+	if license.IsErrDeviceNotExist(err) {
+		ctx.Flash.Error(ctx.Tr("settings.license.device_not_found"))
+	} else if err != nil {
+		ctx.ServerError("ToggleDevice", err)
+		return
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.license.toggle_success"))
+	}
+	ctx.Redirect(setting.AppSubURL + "/user/settings/license")
+}
+
+// LicenseSeats 多席位授权及席位使用情况页面
+func LicenseSeats(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings.license.seats")
+	ctx.Data["PageIsSettingsLicense"] = true
+
+	page := max(ctx.FormInt("page"), 1)
+	licenses, count, err := license.SearchLicenses(ctx, &license.SearchLicensesOptions{
+		ListOptions: db.ListOptions{Page: page, PageSize: setting.UI.User.RepoPagingNum},
+		UserID:      ctx.Doer.ID, // 只查询当前用户的授权
+	})
+	if err != nil {
+		ctx.ServerError("SearchLicenses", err)
+		return
+	}
+
+	activations := make(map[int64][]*license.LicenseActivation, len(licenses))
+	for _, l := range licenses {
+		if activations[l.ID], err = license.ListActivations(ctx, l.ID); err != nil {
+			ctx.ServerError("ListActivations", err)
+			return
+		}
+	}
+
+	ctx.Data["Licenses"] = licenses
+	ctx.Data["Activations"] = activations
+	ctx.Data["Total"] = count
+
+	pager := context.NewPagination(int(count), setting.UI.User.RepoPagingNum, page, 5)
+	ctx.Data["Page"] = pager
+	ctx.HTML(http.StatusOK, tplSettingsLicenseSeats)
+}
+
+// LicenseSeatRelease 释放某台机器占用的席位
+func LicenseSeatRelease(ctx *context.Context) {
+	err := license_service.ReleaseActivation(ctx, ctx.Doer.ID, ctx.PathParamInt64("id"), ctx.PathParamInt64("activation_id"))
+	if license.IsErrLicenseNotExist(err) || license.IsErrActivationNotExist(err) {
+		ctx.Flash.Error(ctx.Tr("settings.license.release_failed"))
+	} else if err != nil {
+		ctx.ServerError("ReleaseActivation", err)
+		return
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.license.release_success"))
+	}
+	ctx.Redirect(setting.AppSubURL + "/user/settings/license/seats")
+}
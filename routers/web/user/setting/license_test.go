@@ -0,0 +1,54 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/services/contexttest"
+	license_service "code.gitea.io/gitea/services/license"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLicenseSeats(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+
+	l, err := license_service.CreateLicense(t.Context(), &license_service.CreateLicenseOptions{UserID: 2, Name: "team", Seats: 2})
+	require.NoError(t, err)
+	_, a, err := license_service.Activate(t.Context(), 2, l.LicenseKey, "MACHINE-1", "laptop")
+	require.NoError(t, err)
+	_, err = license_service.CreateLicense(t.Context(), &license_service.CreateLicenseOptions{UserID: 4, Name: "other", Seats: 1})
+	require.NoError(t, err)
+
+	ctx, _ := contexttest.MockContext(t, "user/settings/license/seats")
+	contexttest.LoadUser(t, ctx, 2)
+	LicenseSeats(ctx)
+	assert.Equal(t, http.StatusOK, ctx.Resp.WrittenStatus())
+	licenses := ctx.Data["Licenses"].([]*license.License)
+	require.Len(t, licenses, 1)
+	assert.Equal(t, l.ID, licenses[0].ID)
+	assert.Len(t, ctx.Data["Activations"].(map[int64][]*license.LicenseActivation)[l.ID], 1)
+
+	release := func(userID int64) {
+		ctx, _ := contexttest.MockContext(t, "user/settings/license/seats")
+		contexttest.LoadUser(t, ctx, userID)
+		ctx.SetPathParam("id", strconv.FormatInt(l.ID, 10))
+		ctx.SetPathParam("activation_id", strconv.FormatInt(a.ID, 10))
+		LicenseSeatRelease(ctx)
+		assert.Equal(t, http.StatusSeeOther, ctx.Resp.WrittenStatus())
+	}
+
+	// other users can't release the seat
+	release(4)
+	unittest.AssertExistsAndLoadBean(t, &license.LicenseActivation{ID: a.ID})
+
+	release(2)
+	unittest.AssertNotExistsBean(t, &license.LicenseActivation{ID: a.ID})
+}
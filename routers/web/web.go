@@ -28,7 +28,6 @@ import (
 	"code.gitea.io/gitea/routers/web/auth"
 	"code.gitea.io/gitea/routers/web/devtest"
 	"code.gitea.io/gitea/routers/web/events"
-	plugin_service "code.gitea.io/gitea/services/plugin"
 	"code.gitea.io/gitea/routers/web/explore"
 	"code.gitea.io/gitea/routers/web/feed"
 	"code.gitea.io/gitea/routers/web/healthcheck"
@@ -46,6 +45,7 @@ import (
 	auth_service "code.gitea.io/gitea/services/auth"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
+	plugin_service "code.gitea.io/gitea/services/plugin"
 
 	_ "code.gitea.io/gitea/modules/session" // to registers all internal adapters
 
@@ -281,32 +281,9 @@ func Routes() *web.Router {
 	webRoutes.Use(mid...)
 	webRoutes.Group("", func() { registerWebRoutes(webRoutes) }, common.BlockExpensive(), common.QoS(), web.RouterMockPoint(RouterMockPointBeforeWebRoutes))
 	routes.Mount("", webRoutes)
-	
-	// 动态注册插件路由
-	registerPluginRoutes(webRoutes)
-	
 	return routes
 }
 
-// registerPluginRoutes 注册所有插件的路由
-func registerPluginRoutes(m *web.Router) {
-	loader := plugin_service.GetLoader()
-	if loader == nil {
-		return
-	}
-
-	plugins := loader.GetAllPlugins()
-	for pluginID, plugin := range plugins {
-		log.Info("Registering routes for plugin: %s", pluginID)
-		
-		// 注册 Web 路由
-		plugin.RegisterRoutes(m)
-		
-		// 注册 API 路由
-		plugin.RegisterAPIRoutes(m)
-	}
-}
-
 // optSignInFromAnyOrigin means that the user can (optionally) be signed in from any origin (no cross-origin protection)
 //   - With CORS middleware: CORS middleware does the preflight request handling, the requests has Sec-Fetch-Site header.
 //     The CORS mechanism already protects cross-origin requests, and the CrossOriginProtection has no "allowed origin" list, so disable CrossOriginProtection.
@@ -707,6 +684,16 @@ func registerWebRoutes(m *web.Router) {
 			m.Get("", user_setting.BlockedUsers)
 			m.Post("", web.Bind(forms.BlockUserForm{}), user_setting.BlockedUsersPost)
 		})
+
+		m.Group("/license", func() {
+			m.Get("", user_setting.License)
+			m.Combo("/new").Get(user_setting.LicenseNew).Post(user_setting.LicenseNewPost)
+			m.Combo("/{id}/edit").Get(user_setting.LicenseEdit).Post(user_setting.LicenseEditPost)
+			m.Post("/{id}/delete", user_setting.LicenseDelete)
+			m.Post("/{id}/toggle", user_setting.LicenseToggle)
+			m.Get("/seats", user_setting.LicenseSeats)
+			m.Post("/seats/{id}/activations/{activation_id}/release", user_setting.LicenseSeatRelease)
+		})
 	}, reqSignIn, ctxDataSet("PageIsUserSettings", true, "EnablePackages", setting.Packages.Enabled, "EnableNotifyMail", setting.Service.EnableNotifyMail))
 
 	m.Group("/user", func() {
@@ -843,10 +830,27 @@ func registerWebRoutes(m *web.Router) {
 		m.Group("/plugins", func() {
 			m.Get("", admin.PluginsList)
 			m.Get("/market", admin.PluginsMarket)
+			m.Post("/market/refresh", admin.PluginsMarketRefresh)
 			m.Post("/install", admin.PluginInstall)
+			m.Get("/install", admin.PluginInstallPage)
+			m.Post("/install/upload", admin.PluginInstallUpload)
+			m.Post("/install/release", admin.PluginInstallRelease)
+			m.Get("/{id}/uninstall", admin.PluginUninstallPage)
 			m.Post("/{id}/uninstall", admin.PluginUninstall)
 			m.Post("/{id}/toggle", admin.PluginToggle)
-		})
+			m.Post("/{id}/upgrade", admin.PluginUpgrade)
+			m.Post("/{id}/rollback", admin.PluginRollback)
+			m.Get("/{id}/config", admin.PluginConfig)
+			m.Post("/{id}/config", admin.PluginConfigPost)
+			m.Get("/{id}/permissions", admin.PluginPermissions)
+			m.Post("/{id}/permissions", admin.PluginPermissionsPost)
+			m.Get("/keys", admin.PluginKeys)
+			m.Post("/keys", admin.PluginKeysAdd)
+			m.Post("/keys/{id}/delete", admin.PluginKeysDelete)
+			m.Get("/audit", admin.PluginAudit)
+		}, admin.PluginManagerRequired)
+
+		m.Get("/licenses", admin.LicenseSeats)
 	}, adminReq, ctxDataSet("EnableOAuth2", setting.OAuth2.Enabled, "EnablePackages", setting.Packages.Enabled))
 	// ***** END: Admin *****
 
@@ -1712,9 +1716,16 @@ func registerWebRoutes(m *web.Router) {
 		})
 	}
 
-	m.NotFound(func(w http.ResponseWriter, req *http.Request) {
+	// plugin routes are mounted dynamically and only tried when no core route matches,
+	// so they can't shadow core routes, and they go through the same auth and cross-origin checks
+	verifyPlugin := verifyAuthWithOptions(&common.VerifyOptions{})
+	m.NotFound(plugin_service.GetRouter().WebHandler(func(_ http.ResponseWriter, req *http.Request) bool {
+		ctx := context.GetWebContext(req.Context())
+		verifyPlugin(ctx)
+		return !ctx.Written()
+	}, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := context.GetWebContext(req.Context())
 		defer routing.RecordFuncInfo(ctx, routing.GetFuncInfo(ctx.NotFound, "WebNotFound"))()
 		ctx.NotFound(nil)
-	})
+	})).ServeHTTP)
 }
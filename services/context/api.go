@@ -163,6 +163,12 @@ func GetAPIContext(req *http.Request) *APIContext {
 	return req.Context().Value(apiContextKey).(*APIContext)
 }
 
+// GetAPIContextOrNil returns the API context of the request, or nil if the request is not an API request
+func GetAPIContextOrNil(req *http.Request) *APIContext {
+	ctx, _ := req.Context().Value(apiContextKey).(*APIContext)
+	return ctx
+}
+
 func genAPILinks(curURL *url.URL, total, pageSize, curPage int) []string {
 	page := NewPagination(total, pageSize, curPage, 0)
 	paginater := page.Paginater
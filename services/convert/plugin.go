@@ -0,0 +1,48 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToPlugin convert a plugin_model.Plugin to an api.Plugin
+func ToPlugin(p *plugin_model.Plugin) *api.Plugin {
+	return &api.Plugin{
+		ID:          p.PluginID,
+		Name:        p.Name,
+		Version:     p.Version,
+		Description: p.Description,
+		Author:      p.Author,
+		Homepage:    p.Homepage,
+		License:     p.License,
+		Enabled:     p.IsEnabled,
+		Source:      p.Source,
+		Signer:      p.Signer,
+		Permissions: p.Permissions,
+		Created:     p.CreatedUnix.AsTime(),
+		Updated:     p.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToPluginAudit convert a plugin_model.Audit to an api.PluginAudit
+func ToPluginAudit(a *plugin_model.Audit) *api.PluginAudit {
+	diff := make([]*api.PluginConfigChange, len(a.ConfigDiff))
+	for i, c := range a.ConfigDiff {
+		diff[i] = &api.PluginConfigChange{Field: c.Field, Old: c.Old, New: c.New}
+	}
+	return &api.PluginAudit{
+		ID:         a.ID,
+		PluginID:   a.PluginID,
+		Action:     a.Action,
+		DoerID:     a.DoerID,
+		DoerName:   a.DoerName,
+		OldVersion: a.OldVersion,
+		NewVersion: a.NewVersion,
+		ConfigDiff: diff,
+		Detail:     a.Detail,
+		Created:    a.CreatedUnix.AsTime(),
+	}
+}
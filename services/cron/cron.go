@@ -62,10 +62,15 @@ type TaskTableRow struct {
 
 func (t *TaskTableRow) FormatLastMessage(locale translation.Locale) string {
 	if t.Status == "finished" {
-		return t.task.GetConfig().FormatMessage(locale, t.Name, t.Status, t.LastDoer)
+		return t.task.config.FormatMessage(locale, t.Name, t.Status, t.LastDoer)
 	}
 
-	return t.task.GetConfig().FormatMessage(locale, t.Name, t.Status, t.LastDoer, t.LastMessage)
+	return t.task.config.FormatMessage(locale, t.Name, t.Status, t.LastDoer, t.LastMessage)
+}
+
+// DisplayName returns the human readable name of the task
+func (t *TaskTableRow) DisplayName(locale translation.Locale) string {
+	return t.task.DisplayName(locale)
 }
 
 // TaskTable represents a table of tasks
@@ -69,8 +69,12 @@ func (b *BaseConfig) DoNoticeOnSuccess() bool {
 // FormatMessage returns a message for the task
 // Please note the `status` string will be concatenated with `admin.dashboard.cron.` and `admin.dashboard.task.` to provide locale messages. Similarly `name` will be composed with `admin.dashboard.` to provide the locale name for the task.
 func (b *BaseConfig) FormatMessage(locale translation.Locale, name, status, doer string, args ...any) string {
+	return formatMessage(locale, locale.TrString("admin.dashboard."+name), status, doer, args...)
+}
+
+func formatMessage(locale translation.Locale, title, status, doer string, args ...any) string {
 	realArgs := make([]any, 0, len(args)+2)
-	realArgs = append(realArgs, locale.TrString("admin.dashboard."+name))
+	realArgs = append(realArgs, title)
 	if doer == "" {
 		realArgs = append(realArgs, "(Cron)")
 	} else {
@@ -84,3 +88,35 @@ func (b *BaseConfig) FormatMessage(locale translation.Locale, name, status, doer
 	}
 	return locale.TrString("admin.dashboard.task."+status, realArgs...)
 }
+
+// titledConfig is implemented by configs of tasks whose names have no translation,
+// e.g. the tasks registered by plugins
+type titledConfig interface {
+	Title() string
+}
+
+// PluginTaskConfig represents the config of a cron task registered by a plugin,
+// the task is shown with the title provided by the plugin instead of a translated name
+type PluginTaskConfig struct {
+	BaseConfig
+	title string
+}
+
+// NewPluginTaskConfig creates the config of a plugin task with the given title and defaults
+func NewPluginTaskConfig(title string, defaults BaseConfig) *PluginTaskConfig {
+	return &PluginTaskConfig{BaseConfig: defaults, title: title}
+}
+
+// Title returns the title of the task
+func (c *PluginTaskConfig) Title() string {
+	return c.title
+}
+
+// FormatMessage returns a message for the task using its title
+func (c *PluginTaskConfig) FormatMessage(locale translation.Locale, name, status, doer string, args ...any) string {
+	title := c.title
+	if title == "" {
+		title = name
+	}
+	return formatMessage(locale, title, status, doer, args...)
+}
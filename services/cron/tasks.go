@@ -5,8 +5,10 @@ package cron
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +22,8 @@ import (
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/translation"
+
+	"github.com/go-co-op/gocron"
 )
 
 var (
@@ -53,6 +57,14 @@ func (t *Task) IsEnabled() bool {
 	return t.config.IsEnabled()
 }
 
+// DisplayName returns the human readable name of the task
+func (t *Task) DisplayName(locale translation.Locale) string {
+	if c, ok := t.config.(titledConfig); ok && c.Title() != "" {
+		return c.Title()
+	}
+	return locale.TrString("admin.dashboard." + t.Name)
+}
+
 // GetConfig will return a copy of the task's config
 func (t *Task) GetConfig() Config {
 	if reflect.TypeOf(t.config).Kind() == reflect.Ptr {
@@ -169,9 +181,11 @@ func GetTask(name string) *Task {
 func RegisterTask(name string, config Config, fun func(context.Context, *user_model.User, Config) error) error {
 	log.Debug("Registering task: %s", name)
 
-	i18nKey := "admin.dashboard." + name
-	if value := translation.NewLocale("en-US").TrString(i18nKey); value == i18nKey {
-		return fmt.Errorf("translation is missing for task %q, please add translation for %q", name, i18nKey)
+	if _, ok := config.(titledConfig); !ok {
+		i18nKey := "admin.dashboard." + name
+		if value := translation.NewLocale("en-US").TrString(i18nKey); value == i18nKey {
+			return fmt.Errorf("translation is missing for task %q, please add translation for %q", name, i18nKey)
+		}
 	}
 
 	_, err := setting.GetCronSettings(name, config)
@@ -209,12 +223,28 @@ func RegisterTask(name string, config Config, fun func(context.Context, *user_mo
 	if started && config.IsEnabled() && config.DoRunAtStart() {
 		lock.Unlock()
 		locked = false
-		task.Run()
+		go task.Run()
 	}
 
 	return nil
 }
 
+// UnregisterTask removes a task from the cron service, e.g. when the plugin which registered it is disabled.
+// A running instance of the task is not interrupted.
+func UnregisterTask(name string) {
+	lock.Lock()
+	defer lock.Unlock()
+	if _, has := tasksMap[name]; !has {
+		return
+	}
+	delete(tasksMap, name)
+	tasks = slices.DeleteFunc(tasks, func(t *Task) bool { return t.Name == name })
+	if err := scheduler.RemoveByTag(name); err != nil && !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
+		log.Error("Unable to remove cron task %s from scheduler: %v", name, err)
+	}
+	log.Debug("Unregistered task: %s", name)
+}
+
 // RegisterTaskFatal will register a task but if there is an error log.Fatal
 func RegisterTaskFatal(name string, config Config, fun func(context.Context, *user_model.User, Config) error) {
 	if err := RegisterTask(name, config, fun); err != nil {
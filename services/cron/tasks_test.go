@@ -4,11 +4,18 @@
 package cron
 
 import (
+	"context"
 	"sort"
 	"strconv"
 	"testing"
 
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/translation"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAddTaskToScheduler(t *testing.T) {
@@ -65,3 +72,35 @@ func TestScheduleHasSeconds(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterPluginTask(t *testing.T) {
+	cfg, err := setting.NewConfigProviderFromData(`
+[cron.plugin.demo.cleanup]
+SCHEDULE = "@every 2h"
+`)
+	require.NoError(t, err)
+	defer test.MockVariableValue(&setting.CfgProvider, cfg)()
+	defer scheduler.Clear()
+
+	const name = "plugin.demo.cleanup"
+	config := NewPluginTaskConfig("Demo cleanup", BaseConfig{Enabled: true, Schedule: "@midnight"})
+	// plugin tasks need no translation of their names
+	require.NoError(t, RegisterTask(name, config, func(context.Context, *user_model.User, Config) error { return nil }))
+	defer UnregisterTask(name)
+	assert.Error(t, RegisterTask(name, config, func(context.Context, *user_model.User, Config) error { return nil }))
+
+	task := GetTask(name)
+	require.NotNil(t, task)
+	assert.Equal(t, "@every 2h", config.Schedule)
+	assert.Equal(t, "Demo cleanup", config.Title())
+	locale := translation.MockLocale{}
+	assert.Equal(t, "Demo cleanup", task.DisplayName(locale))
+	assert.Equal(t, "admin.dashboard.cron.finished:Demo cleanup,(Cron)", config.FormatMessage(locale, name, "finished", ""))
+	require.Len(t, scheduler.Jobs(), 1)
+
+	UnregisterTask(name)
+	assert.Nil(t, GetTask(name))
+	assert.Empty(t, scheduler.Jobs())
+	// unregistering twice is a no-op
+	UnregisterTask(name)
+}
@@ -65,11 +65,12 @@ type RenameOrgForm struct {
 
 // CreateTeamForm form for creating team
 type CreateTeamForm struct {
-	TeamName         string `binding:"Required;AlphaDashDot;MaxSize(255)"`
-	Description      string `binding:"MaxSize(255)"`
-	Permission       string
-	RepoAccess       string
-	CanCreateOrgRepo bool
+	TeamName          string `binding:"Required;AlphaDashDot;MaxSize(255)"`
+	Description       string `binding:"MaxSize(255)"`
+	Permission        string
+	RepoAccess        string
+	CanCreateOrgRepo  bool
+	CanManageLicenses bool
 }
 
 // Validate validates the fields
@@ -0,0 +1,57 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// APIKeyPrefix API 密钥的前缀，便于在代码和日志中识别
+const APIKeyPrefix = "glk_"
+
+// APIKeyHeader 客户端传递 API 密钥的请求头
+const APIKeyHeader = "X-License-API-Key"
+
+// HashAPIKey 计算 API 密钥的哈希值
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// CreateAPIKey 为用户创建 API 密钥，返回的明文密钥只在创建时可见
+func CreateAPIKey(ctx context.Context, userID int64, name string) (*license.APIKey, string, error) {
+	plain := APIKeyPrefix + strings.ToLower(rand.Text())
+	key := &license.APIKey{
+		UserID:       userID,
+		Name:         name,
+		KeyHash:      HashAPIKey(plain),
+		KeyLastEight: plain[len(plain)-8:],
+	}
+	if err := license.CreateAPIKey(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, plain, nil
+}
+
+// AuthenticateAPIKey 验证客户端提供的 API 密钥
+func AuthenticateAPIKey(ctx context.Context, plain string) (*license.APIKey, error) {
+	if !strings.HasPrefix(plain, APIKeyPrefix) {
+		return nil, license.ErrAPIKeyNotExist{}
+	}
+	key, err := license.GetAPIKeyByHash(ctx, HashAPIKey(plain))
+	if err != nil {
+		return nil, err
+	}
+	if err := license.UpdateAPIKeyLastUsed(ctx, key); err != nil {
+		log.Error("UpdateAPIKeyLastUsed: %v", err)
+	}
+	return key, nil
+}
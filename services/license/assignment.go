@@ -0,0 +1,142 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// AssignSeat 将组织授权的一个席位分配给组织成员，分配数不能超过席位数。已分配时直接返回原分配
+func AssignSeat(ctx context.Context, doer *user_model.User, orgID, licenseID, userID int64) (*license.LicenseAssignment, error) {
+	l, err := license.GetLicenseByUserAndID(ctx, orgID, licenseID)
+	if err != nil {
+		return nil, err
+	}
+	isMember, err := organization.IsOrganizationMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, util.NewInvalidArgumentErrorf("user %d is not a member of the license owner", userID)
+	}
+
+	return db.WithTx2(ctx, func(ctx context.Context) (*license.LicenseAssignment, error) {
+		if err := license.LockLicense(ctx, l.ID); err != nil {
+			return nil, err
+		}
+		a, err := license.GetAssignment(ctx, l.ID, userID)
+		if err == nil {
+			return a, nil
+		} else if !license.IsErrAssignmentNotExist(err) {
+			return nil, err
+		}
+
+		assigned, err := license.CountAssignments(ctx, l.ID)
+		if err != nil {
+			return nil, err
+		}
+		if assigned >= int64(l.Seats) {
+			return nil, license.ErrNoSeatsAvailable{LicenseID: l.ID, Seats: l.Seats}
+		}
+		a = &license.LicenseAssignment{
+			LicenseID:  l.ID,
+			UserID:     userID,
+			AssignerID: doer.ID,
+		}
+		return a, license.CreateAssignment(ctx, a)
+	})
+}
+
+// UnassignSeat 收回成员的席位，成员激活的机器同时被释放并加入吊销列表
+func UnassignSeat(ctx context.Context, orgID, licenseID, userID int64) error {
+	l, err := license.GetLicenseByUserAndID(ctx, orgID, licenseID)
+	if err != nil {
+		return err
+	}
+	a, err := license.GetAssignment(ctx, l.ID, userID)
+	if err != nil {
+		return err
+	}
+	return license.DeleteAssignment(ctx, l, a)
+}
+
+// RevokeMemberSeats 收回成员在组织授权中的所有席位，成员离开组织时调用（包括因移出最后一个团队而离开）。
+// 成员激活的机器同时被释放并加入吊销列表
+func RevokeMemberSeats(ctx context.Context, orgID, userID int64) error {
+	licenses, err := license.ListAssignedLicenses(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, l := range licenses {
+		if l.UserID != orgID {
+			continue
+		}
+		a, err := license.GetAssignment(ctx, l.ID, userID)
+		if err != nil {
+			return err
+		}
+		if err := license.DeleteAssignment(ctx, l, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getAssignedLicense 获取分配给成员的授权。成员离开组织后不能再使用分配的席位，
+// 未分配和已离开组织都返回 ErrLicenseNotExist，不向成员透露授权是否存在
+func getAssignedLicense(ctx context.Context, userID, licenseID int64) (*license.License, error) {
+	if _, err := license.GetAssignment(ctx, licenseID, userID); err != nil {
+		if license.IsErrAssignmentNotExist(err) {
+			return nil, license.ErrLicenseNotExist{ID: licenseID}
+		}
+		return nil, err
+	}
+	l, err := license.GetLicenseByID(ctx, licenseID)
+	if err != nil {
+		return nil, err
+	}
+	isMember, err := organization.IsOrganizationMember(ctx, l.UserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, license.ErrLicenseNotExist{ID: licenseID}
+	}
+	return l, nil
+}
+
+// ActivateSeat 组织成员使用分配的席位在机器上激活授权。重复激活同一台机器即续租，
+// 在新机器上激活会释放成员之前激活的机器
+func ActivateSeat(ctx context.Context, userID, licenseID int64, machineCode, machineName string) (*license.License, *license.LicenseActivation, error) {
+	l, err := getAssignedLicense(ctx, userID, licenseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !l.IsValid() {
+		return nil, nil, license.ErrLicenseNotActive{ID: l.ID}
+	}
+	return activate(ctx, l, userID, machineCode, machineName)
+}
+
+// DeactivateSeat 组织成员停用自己激活的机器，席位分配保留
+func DeactivateSeat(ctx context.Context, userID, licenseID int64, machineCode string) error {
+	l, err := getAssignedLicense(ctx, userID, licenseID)
+	if err != nil {
+		return err
+	}
+	a, err := license.GetActivation(ctx, l.ID, machineCode)
+	if err != nil {
+		return err
+	}
+	if a.UserID != userID {
+		return license.ErrActivationNotExist{MachineCode: machineCode}
+	}
+	return license.DeleteActivation(ctx, l, a)
+}
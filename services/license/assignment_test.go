@@ -0,0 +1,98 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanManageLicenses(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	member := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+
+	canManage := func(doer, owner *user_model.User) bool {
+		ok, err := CanManageLicenses(t.Context(), doer, owner)
+		require.NoError(t, err)
+		return ok
+	}
+	assert.True(t, canManage(member, member))
+	assert.False(t, canManage(owner, member))
+	assert.True(t, canManage(owner, org))
+	assert.False(t, canManage(member, org))
+
+	// member of team1 gets the permission from the team
+	_, err := db.GetEngine(t.Context()).ID(2).Cols("can_manage_licenses").Update(&organization.Team{CanManageLicenses: true})
+	require.NoError(t, err)
+	assert.True(t, canManage(member, org))
+}
+
+func TestAssignSeat(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.AppDataPath, t.TempDir())()
+	resetSigningKey()
+	defer resetSigningKey()
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	l, err := CreateLicense(t.Context(), &CreateLicenseOptions{UserID: 3, Name: "team", Seats: 1})
+	require.NoError(t, err)
+
+	// only members of the owning organization can get a seat
+	_, err = AssignSeat(t.Context(), doer, 3, l.ID, 5)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = AssignSeat(t.Context(), doer, 2, l.ID, 4)
+	assert.True(t, license.IsErrLicenseNotExist(err))
+
+	a, err := AssignSeat(t.Context(), doer, 3, l.ID, 4)
+	require.NoError(t, err)
+	again, err := AssignSeat(t.Context(), doer, 3, l.ID, 4)
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, again.ID)
+	_, err = AssignSeat(t.Context(), doer, 3, l.ID, 28)
+	assert.True(t, license.IsErrNoSeatsAvailable(err))
+
+	// an unassigned member cannot see the license
+	_, _, err = ActivateSeat(t.Context(), 28, l.ID, "M-1", "pc-1")
+	assert.True(t, license.IsErrLicenseNotExist(err))
+
+	_, a1, err := ActivateSeat(t.Context(), 4, l.ID, "M-1", "pc-1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, a1.UserID)
+	assigned, err := license.ListAssignedLicenses(t.Context(), 4)
+	require.NoError(t, err)
+	require.Len(t, assigned, 1)
+	assert.EqualValues(t, 1, assigned[0].SeatsUsed)
+
+	// moving to another machine releases and revokes the first one
+	_, a2, err := ActivateSeat(t.Context(), 4, l.ID, "M-2", "pc-2")
+	require.NoError(t, err)
+	_, claims, err := IssueRevocationList(t.Context(), 3)
+	require.NoError(t, err)
+	assert.True(t, claims.IsRevoked(a1.DeviceID))
+	assert.False(t, claims.IsRevoked(a2.DeviceID))
+
+	require.NoError(t, UnassignSeat(t.Context(), 3, l.ID, 4))
+	_, _, err = ActivateSeat(t.Context(), 4, l.ID, "M-2", "pc-2")
+	assert.True(t, license.IsErrLicenseNotExist(err))
+	_, claims, err = IssueRevocationList(t.Context(), 3)
+	require.NoError(t, err)
+	assert.True(t, claims.IsRevoked(a2.DeviceID))
+	used, err := license.CountActiveActivations(t.Context(), l.ID)
+	require.NoError(t, err)
+	assert.Zero(t, used)
+}
@@ -13,15 +13,25 @@ import (
 
 // CreateDeviceOptions 创建设备选项
 type CreateDeviceOptions struct {
-	UserID      int64  // 所属用户ID
+	UserID      int64 // 所属用户ID
 	MachineCode string
 	MachineName string
 	ExpiryDays  int // 0 表示永久
 	Remarks     string
+	ProductID   int64
+	EditionID   int64
+	Features    []*license.Entitlement
 }
 
 // CreateDevice 创建授权设备
 func CreateDevice(ctx context.Context, opts *CreateDeviceOptions) (*license.AuthorizedDevice, error) {
+	if err := checkProductEdition(ctx, opts.UserID, opts.ProductID, opts.EditionID); err != nil {
+		return nil, err
+	}
+	if err := license.ValidateEntitlements(opts.Features); err != nil {
+		return nil, err
+	}
+
 	// 检查该用户的机器码是否已存在
 	existing, err := license.GetDeviceByUserAndMachineCode(ctx, opts.UserID, opts.MachineCode)
 	if err == nil && existing != nil {
@@ -46,6 +56,9 @@ func CreateDevice(ctx context.Context, opts *CreateDeviceOptions) (*license.Auth
 		IsEnabled:   true,
 		ExpiryDate:  expiryDate,
 		Remarks:     opts.Remarks,
+		ProductID:   opts.ProductID,
+		EditionID:   opts.EditionID,
+		Features:    opts.Features,
 	}
 
 	if err := license.CreateDevice(ctx, device); err != nil {
@@ -65,8 +78,8 @@ func VerifyLicense(ctx context.Context, userID int64, machineCode, licenseKey st
 		return false, nil, err
 	}
 
-	// 验证授权码
-	if device.LicenseKey != licenseKey {
+	// 验证授权码，客户端可能提交带分隔符的格式化授权码
+	if device.LicenseKey != NormalizeLicenseKey(licenseKey) {
 		return false, device, nil
 	}
 
@@ -100,6 +113,9 @@ type UpdateDeviceOptions struct {
 	IsEnabled   *bool
 	ExpiryDays  *int // nil 表示不修改，0 表示永久，>0 表示天数
 	Remarks     string
+	ProductID   *int64                 // nil 表示不修改，0 表示取消关联
+	EditionID   *int64                 // nil 表示不修改，0 表示取消关联
+	Features    []*license.Entitlement // nil 表示不修改
 }
 
 // UpdateDevice 更新设备信息
@@ -129,5 +145,22 @@ func UpdateDevice(ctx context.Context, opts *UpdateDeviceOptions) error {
 		device.Remarks = opts.Remarks
 	}
 
+	if opts.ProductID != nil {
+		device.ProductID = *opts.ProductID
+	}
+	if opts.EditionID != nil {
+		device.EditionID = *opts.EditionID
+	}
+	if err := checkProductEdition(ctx, opts.UserID, device.ProductID, device.EditionID); err != nil {
+		return err
+	}
+
+	if opts.Features != nil {
+		if err := license.ValidateEntitlements(opts.Features); err != nil {
+			return err
+		}
+		device.Features = opts.Features
+	}
+
 	return license.UpdateDevice(ctx, device)
 }
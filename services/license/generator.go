@@ -4,18 +4,17 @@
 package license
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
-
-	"code.gitea.io/gitea/modules/util"
 )
 
 // GenerateLicenseKey 生成授权码
 func GenerateLicenseKey(machineCode string) string {
-	data := fmt.Sprintf("%s-%d-%s", machineCode, time.Now().UnixNano(), util.GenerateRandomString(16))
+	data := fmt.Sprintf("%s-%d-%s", machineCode, time.Now().UnixNano(), rand.Text()[:16])
 	hash := sha256.Sum256([]byte(data))
 	key := hex.EncodeToString(hash[:])[:32]
 	return strings.ToUpper(key)
@@ -33,7 +32,12 @@ func FormatLicenseKey(key string) string {
 	return strings.Join(parts, "-")
 }
 
+// NormalizeLicenseKey 去掉格式化授权码中的分隔符，得到保存的授权码
+func NormalizeLicenseKey(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, "-", ""))
+}
+
 // GenerateDeviceID 生成设备ID
 func GenerateDeviceID() string {
-	return util.GenerateRandomString(16)
+	return rand.Text()[:16]
 }
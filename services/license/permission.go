@@ -0,0 +1,26 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+)
+
+// CanManageLicenses 检查 doer 能否管理 owner 的授权。个人的授权只有本人可以管理；
+// 组织的授权由所有者团队和拥有授权管理权限的团队成员管理
+func CanManageLicenses(ctx context.Context, doer, owner *user_model.User) (bool, error) {
+	if doer == nil || owner == nil {
+		return false, nil
+	}
+	if doer.ID == owner.ID {
+		return true, nil
+	}
+	if !owner.IsOrganization() {
+		return false, nil
+	}
+	return organization.CanManageLicenses(ctx, owner.ID, doer.ID)
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CreateProductOptions 创建产品选项
+type CreateProductOptions struct {
+	UserID      int64 // 所属用户ID
+	Key         string
+	Name        string
+	Description string
+}
+
+// CreateProduct 创建产品
+func CreateProduct(ctx context.Context, opts *CreateProductOptions) (*license.LicenseProduct, error) {
+	if !license.IsValidKey(opts.Key) {
+		return nil, util.NewInvalidArgumentErrorf("invalid product key %q", opts.Key)
+	}
+	product := &license.LicenseProduct{
+		UserID:      opts.UserID,
+		Key:         opts.Key,
+		Name:        util.IfZero(opts.Name, opts.Key),
+		Description: opts.Description,
+	}
+	if err := license.CreateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// DeleteProduct 删除用户的产品
+func DeleteProduct(ctx context.Context, userID, id int64) error {
+	product, err := license.GetProductByUserAndID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	return license.DeleteProduct(ctx, product)
+}
+
+// CreateEditionOptions 创建版本选项
+type CreateEditionOptions struct {
+	UserID    int64 // 用户ID（用于数据隔离）
+	ProductID int64
+	Key       string
+	Name      string
+	Features  []*license.Entitlement
+}
+
+// CreateEdition 为用户的产品创建版本
+func CreateEdition(ctx context.Context, opts *CreateEditionOptions) (*license.LicenseEdition, error) {
+	if _, err := license.GetProductByUserAndID(ctx, opts.UserID, opts.ProductID); err != nil {
+		return nil, err
+	}
+	if !license.IsValidKey(opts.Key) {
+		return nil, util.NewInvalidArgumentErrorf("invalid edition key %q", opts.Key)
+	}
+	if err := license.ValidateEntitlements(opts.Features); err != nil {
+		return nil, err
+	}
+	edition := &license.LicenseEdition{
+		ProductID: opts.ProductID,
+		Key:       opts.Key,
+		Name:      util.IfZero(opts.Name, opts.Key),
+		Features:  opts.Features,
+	}
+	if err := license.CreateEdition(ctx, edition); err != nil {
+		return nil, err
+	}
+	return edition, nil
+}
+
+// UpdateEditionOptions 更新版本选项
+type UpdateEditionOptions struct {
+	UserID    int64 // 用户ID（用于数据隔离）
+	ProductID int64
+	ID        int64
+	Name      string
+	Features  []*license.Entitlement // nil 表示不修改
+}
+
+// UpdateEdition 更新版本，修改后的功能在授权下次验证时生效
+func UpdateEdition(ctx context.Context, opts *UpdateEditionOptions) (*license.LicenseEdition, error) {
+	edition, err := getUserEdition(ctx, opts.UserID, opts.ProductID, opts.ID)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Name != "" {
+		edition.Name = opts.Name
+	}
+	if opts.Features != nil {
+		if err := license.ValidateEntitlements(opts.Features); err != nil {
+			return nil, err
+		}
+		edition.Features = opts.Features
+	}
+	if err := license.UpdateEdition(ctx, edition); err != nil {
+		return nil, err
+	}
+	return edition, nil
+}
+
+// DeleteEdition 删除用户产品的版本
+func DeleteEdition(ctx context.Context, userID, productID, id int64) error {
+	edition, err := getUserEdition(ctx, userID, productID, id)
+	if err != nil {
+		return err
+	}
+	return license.DeleteEdition(ctx, edition)
+}
+
+func getUserEdition(ctx context.Context, userID, productID, id int64) (*license.LicenseEdition, error) {
+	if _, err := license.GetProductByUserAndID(ctx, userID, productID); err != nil {
+		return nil, err
+	}
+	return license.GetEditionByProductAndID(ctx, productID, id)
+}
+
+// checkProductEdition 检查授权关联的产品和版本属于该用户，版本属于该产品
+func checkProductEdition(ctx context.Context, userID, productID, editionID int64) error {
+	if productID == 0 {
+		if editionID != 0 {
+			return util.NewInvalidArgumentErrorf("edition %d requires a product", editionID)
+		}
+		return nil
+	}
+	if _, err := license.GetProductByUserAndID(ctx, userID, productID); err != nil {
+		return err
+	}
+	if editionID != 0 {
+		if _, err := license.GetEditionByProductAndID(ctx, productID, editionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Entitlements 授权的产品、版本和最终包含的功能
+type Entitlements struct {
+	Product  string                 `json:"product,omitempty"`
+	Edition  string                 `json:"edition,omitempty"`
+	Features []*license.Entitlement `json:"features"`
+}
+
+// GetEntitlements 计算授权包含的功能：版本的功能与授权自身的功能合并
+func GetEntitlements(ctx context.Context, device *license.AuthorizedDevice) (*Entitlements, error) {
+	return resolveEntitlements(ctx, device.ProductID, device.EditionID, device.Features)
+}
+
+// GetLicenseEntitlements 计算多席位授权包含的功能
+func GetLicenseEntitlements(ctx context.Context, l *license.License) (*Entitlements, error) {
+	return resolveEntitlements(ctx, l.ProductID, l.EditionID, l.Features)
+}
+
+func resolveEntitlements(ctx context.Context, productID, editionID int64, features []*license.Entitlement) (*Entitlements, error) {
+	ent := &Entitlements{}
+	var base []*license.Entitlement
+	if productID > 0 {
+		product, err := license.GetProductByID(ctx, productID)
+		if err != nil {
+			return nil, fmt.Errorf("GetProductByID: %w", err)
+		}
+		ent.Product = product.Key
+	}
+	if editionID > 0 {
+		edition, err := license.GetEditionByID(ctx, editionID)
+		if err != nil {
+			return nil, fmt.Errorf("GetEditionByID: %w", err)
+		}
+		ent.Edition = edition.Key
+		base = edition.Features
+	}
+	ent.Features = license.MergeEntitlements(base, features)
+	return ent, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"encoding/json"
+	"testing"
+
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntitlementJSON(t *testing.T) {
+	var features []*license.Entitlement
+	require.NoError(t, json.Unmarshal([]byte(`["export", {"name": "seats", "quantity": 5}]`), &features))
+	assert.Equal(t, []*license.Entitlement{{Name: "export"}, {Name: "seats", Quantity: 5}}, features)
+
+	assert.Error(t, license.ValidateEntitlements([]*license.Entitlement{{Name: " "}}))
+	assert.Error(t, license.ValidateEntitlements([]*license.Entitlement{{Name: "a", Quantity: -1}}))
+	assert.Error(t, license.ValidateEntitlements([]*license.Entitlement{{Name: "a"}, {Name: "a "}}))
+}
+
+func TestProductEntitlements(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	product, err := CreateProduct(t.Context(), &CreateProductOptions{UserID: 2, Key: "app", Name: "App"})
+	require.NoError(t, err)
+	_, err = CreateProduct(t.Context(), &CreateProductOptions{UserID: 2, Key: "app"})
+	assert.True(t, license.IsErrProductAlreadyExist(err))
+	_, err = CreateProduct(t.Context(), &CreateProductOptions{UserID: 2, Key: "Bad Key"})
+	assert.Error(t, err)
+
+	pro, err := CreateEdition(t.Context(), &CreateEditionOptions{
+		UserID:    2,
+		ProductID: product.ID,
+		Key:       "pro",
+		Features:  []*license.Entitlement{{Name: "export"}, {Name: "seats", Quantity: 5}},
+	})
+	require.NoError(t, err)
+	_, err = CreateEdition(t.Context(), &CreateEditionOptions{UserID: 4, ProductID: product.ID, Key: "other"})
+	assert.True(t, license.IsErrProductNotExist(err))
+
+	// another user's product can not be used
+	_, err = CreateDevice(t.Context(), &CreateDeviceOptions{UserID: 4, MachineCode: "M-4", ProductID: product.ID})
+	assert.True(t, license.IsErrProductNotExist(err))
+	_, err = CreateDevice(t.Context(), &CreateDeviceOptions{UserID: 2, MachineCode: "M-2", EditionID: pro.ID})
+	assert.Error(t, err)
+
+	device, err := CreateDevice(t.Context(), &CreateDeviceOptions{
+		UserID:      2,
+		MachineCode: "M-2",
+		ProductID:   product.ID,
+		EditionID:   pro.ID,
+		Features:    []*license.Entitlement{{Name: "seats", Quantity: 10}, {Name: "beta"}},
+	})
+	require.NoError(t, err)
+
+	ent, err := GetEntitlements(t.Context(), device)
+	require.NoError(t, err)
+	assert.Equal(t, "app", ent.Product)
+	assert.Equal(t, "pro", ent.Edition)
+	assert.Equal(t, []*license.Entitlement{{Name: "beta"}, {Name: "export"}, {Name: "seats", Quantity: 10}}, ent.Features)
+
+	// edition changes apply to existing licenses
+	_, err = UpdateEdition(t.Context(), &UpdateEditionOptions{
+		UserID:    2,
+		ProductID: product.ID,
+		ID:        pro.ID,
+		Features:  []*license.Entitlement{{Name: "sync"}},
+	})
+	require.NoError(t, err)
+	ent, err = GetEntitlements(t.Context(), device)
+	require.NoError(t, err)
+	assert.Equal(t, []*license.Entitlement{{Name: "beta"}, {Name: "seats", Quantity: 10}, {Name: "sync"}}, ent.Features)
+
+	assert.True(t, license.IsErrLicenseInUse(DeleteEdition(t.Context(), 2, product.ID, pro.ID)))
+	assert.True(t, license.IsErrLicenseInUse(DeleteProduct(t.Context(), 2, product.ID)))
+	require.NoError(t, license.DeleteDevice(t.Context(), device.ID))
+	require.NoError(t, DeleteProduct(t.Context(), 2, product.ID))
+	_, err = license.GetEditionByID(t.Context(), pro.ID)
+	assert.True(t, license.IsErrEditionNotExist(err))
+}
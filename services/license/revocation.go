@@ -0,0 +1,68 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RevocationListValidity 吊销列表的有效期，客户端应在到期前重新获取，过期的吊销列表不能再用于离线验证
+const RevocationListValidity = 24 * time.Hour
+
+// RevocationListClaims 吊销列表的内容，与离线授权令牌使用同一把密钥签名。
+// sub 为用户 ID，iat 为生成时间，exp 为下次必须更新的时间
+type RevocationListClaims struct {
+	Revoked []*license.Revocation `json:"revoked"`
+	jwt.RegisteredClaims
+}
+
+// IssueRevocationList 生成并签名用户的吊销列表，包含尚未到期的已禁用和已删除设备
+func IssueRevocationList(ctx context.Context, userID int64) (string, *RevocationListClaims, error) {
+	revoked, err := license.GetRevocations(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	claims := &RevocationListClaims{
+		Revoked: revoked,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    setting.AppURL,
+			Subject:   strconv.FormatInt(userID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(RevocationListValidity)),
+		},
+	}
+	signed, err := sign(claims, TokenTypeRevocationList)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// ParseRevocationList 验证吊销列表的签名和有效期
+func ParseRevocationList(list string) (*RevocationListClaims, error) {
+	claims := &RevocationListClaims{}
+	if err := verify(list, TokenTypeRevocationList, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// IsRevoked 检查设备是否在吊销列表中
+func (c *RevocationListClaims) IsRevoked(deviceID string) bool {
+	for _, r := range c.Revoked {
+		if r.DeviceID == deviceID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	key, plain, err := CreateAPIKey(t.Context(), 2, "client")
+	require.NoError(t, err)
+	assert.True(t, len(plain) > len(APIKeyPrefix))
+	assert.Equal(t, plain[len(plain)-8:], key.KeyLastEight)
+	assert.NotContains(t, key.KeyHash, plain)
+
+	got, err := AuthenticateAPIKey(t.Context(), plain)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, got.UserID)
+	assert.False(t, got.LastUsedUnix.IsZero())
+
+	_, err = AuthenticateAPIKey(t.Context(), plain+"x")
+	assert.True(t, license.IsErrAPIKeyNotExist(err))
+	_, err = AuthenticateAPIKey(t.Context(), "")
+	assert.True(t, license.IsErrAPIKeyNotExist(err))
+
+	assert.True(t, license.IsErrAPIKeyNotExist(license.DeleteAPIKey(t.Context(), 1, key.ID)))
+	require.NoError(t, license.DeleteAPIKey(t.Context(), 2, key.ID))
+	_, err = AuthenticateAPIKey(t.Context(), plain)
+	assert.True(t, license.IsErrAPIKeyNotExist(err))
+}
+
+func TestRevocationList(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.AppDataPath, t.TempDir())()
+	resetSigningKey()
+	defer resetSigningKey()
+
+	create := func(machineCode string, expiryDays int) *license.AuthorizedDevice {
+		device, err := CreateDevice(t.Context(), &CreateDeviceOptions{UserID: 2, MachineCode: machineCode, ExpiryDays: expiryDays})
+		require.NoError(t, err)
+		return device
+	}
+	active := create("ACTIVE", 0)
+	disabled := create("DISABLED", 30)
+	deleted := create("DELETED", 0)
+	other, err := CreateDevice(t.Context(), &CreateDeviceOptions{UserID: 4, MachineCode: "OTHER"})
+	require.NoError(t, err)
+
+	require.NoError(t, ToggleDevice(t.Context(), 2, disabled.ID))
+	require.NoError(t, ToggleDevice(t.Context(), 4, other.ID))
+	require.NoError(t, license.DeleteDevice(t.Context(), deleted.ID))
+
+	// formatted license keys are accepted
+	valid, _, err := VerifyLicense(t.Context(), 2, "ACTIVE", FormatLicenseKey(active.LicenseKey))
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	signed, claims, err := IssueRevocationList(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "2", claims.Subject)
+	if assert.Len(t, claims.Revoked, 2) {
+		assert.Equal(t, license.RevocationReasonDisabled, claims.Revoked[0].Reason)
+		assert.Equal(t, license.RevocationReasonDeleted, claims.Revoked[1].Reason)
+	}
+
+	parsed, err := ParseRevocationList(signed)
+	require.NoError(t, err)
+	assert.True(t, parsed.IsRevoked(disabled.DeviceID))
+	assert.True(t, parsed.IsRevoked(deleted.DeviceID))
+	assert.False(t, parsed.IsRevoked(active.DeviceID))
+	assert.False(t, parsed.IsRevoked(other.DeviceID))
+
+	// a revocation list is not a license token
+	_, err = ParseToken(signed)
+	assert.Error(t, err)
+
+	// re-enabled devices are removed from the list
+	require.NoError(t, ToggleDevice(t.Context(), 2, disabled.ID))
+	_, claims, err = IssueRevocationList(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Len(t, claims.Revoked, 1)
+}
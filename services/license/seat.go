@@ -0,0 +1,249 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// DefaultLeaseDuration 浮动授权默认的租约时长，客户端应在租约到期前发送心跳
+const DefaultLeaseDuration = time.Hour
+
+// MinLeaseDuration 浮动授权最短的租约时长
+const MinLeaseDuration = time.Minute
+
+// CreateLicenseOptions 创建多席位授权选项
+type CreateLicenseOptions struct {
+	UserID        int64 // 所属用户ID
+	Name          string
+	Type          string // seat 或 floating，默认为 seat
+	Seats         int
+	LeaseDuration time.Duration // 浮动授权的租约时长，0 表示使用默认值
+	ExpiryDays    int           // 0 表示永久
+	ProductID     int64
+	EditionID     int64
+	Features      []*license.Entitlement
+	Remarks       string
+}
+
+// CreateLicense 创建多席位授权
+func CreateLicense(ctx context.Context, opts *CreateLicenseOptions) (*license.License, error) {
+	licenseType := util.IfZero(opts.Type, license.LicenseTypeSeat)
+	if licenseType != license.LicenseTypeSeat && licenseType != license.LicenseTypeFloating {
+		return nil, util.NewInvalidArgumentErrorf("invalid license type %q", opts.Type)
+	}
+	if opts.Seats < 1 {
+		return nil, util.NewInvalidArgumentErrorf("a license needs at least one seat")
+	}
+	var lease time.Duration
+	if licenseType == license.LicenseTypeFloating {
+		lease = util.IfZero(opts.LeaseDuration, DefaultLeaseDuration)
+		if lease < MinLeaseDuration {
+			return nil, util.NewInvalidArgumentErrorf("lease duration must be at least %v", MinLeaseDuration)
+		}
+	}
+	if err := checkProductEdition(ctx, opts.UserID, opts.ProductID, opts.EditionID); err != nil {
+		return nil, err
+	}
+	if err := license.ValidateEntitlements(opts.Features); err != nil {
+		return nil, err
+	}
+
+	var expiryDate timeutil.TimeStamp
+	if opts.ExpiryDays > 0 {
+		expiryDate = timeutil.TimeStamp(time.Now().AddDate(0, 0, opts.ExpiryDays).Unix())
+	}
+
+	l := &license.License{
+		UserID:       opts.UserID,
+		Name:         opts.Name,
+		LicenseKey:   GenerateLicenseKey(opts.Name),
+		Type:         licenseType,
+		Seats:        opts.Seats,
+		LeaseSeconds: int64(lease / time.Second),
+		ProductID:    opts.ProductID,
+		EditionID:    opts.EditionID,
+		Features:     opts.Features,
+		IsEnabled:    true,
+		ExpiryDate:   expiryDate,
+		Remarks:      opts.Remarks,
+	}
+	if err := license.CreateLicense(ctx, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// getActiveLicense 获取用户有效的多席位授权
+func getActiveLicense(ctx context.Context, userID int64, licenseKey string) (*license.License, error) {
+	l, err := license.GetLicenseByUserAndKey(ctx, userID, NormalizeLicenseKey(licenseKey))
+	if err != nil {
+		return nil, err
+	}
+	if !l.IsValid() {
+		return nil, license.ErrLicenseNotActive{ID: l.ID}
+	}
+	return l, nil
+}
+
+// renewLease 记录心跳并续租浮动授权
+func renewLease(l *license.License, a *license.LicenseActivation) {
+	now := time.Now()
+	a.LastHeartbeatUnix = timeutil.TimeStamp(now.Unix())
+	if l.IsFloating() {
+		a.LeaseExpiresUnix = timeutil.TimeStamp(now.Add(l.LeaseDuration()).Unix())
+	}
+}
+
+// Activate 在机器上激活授权并占用一个席位，机器已激活时只续租。席位用完时返回 ErrNoSeatsAvailable
+func Activate(ctx context.Context, userID int64, licenseKey, machineCode, machineName string) (*license.License, *license.LicenseActivation, error) {
+	l, err := getActiveLicense(ctx, userID, licenseKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return activate(ctx, l, 0, machineCode, machineName)
+}
+
+// activate 占用授权的一个席位。memberID 不为 0 时表示组织成员使用分配的席位激活，
+// 成员同时只能激活一台机器，在新机器上激活会释放之前的机器。
+// 机器已被其他成员（或通过授权密钥）激活时返回 ErrActivationNotExist，不能续租别人的席位
+func activate(ctx context.Context, l *license.License, memberID int64, machineCode, machineName string) (*license.License, *license.LicenseActivation, error) {
+	a, err := db.WithTx2(ctx, func(ctx context.Context) (*license.LicenseActivation, error) {
+		if err := license.LockLicense(ctx, l.ID); err != nil {
+			return nil, err
+		}
+		if _, err := license.DeleteExpiredLeases(ctx, l.ID); err != nil {
+			return nil, err
+		}
+
+		a, err := license.GetActivation(ctx, l.ID, machineCode)
+		if err == nil {
+			if a.UserID != memberID {
+				return nil, license.ErrActivationNotExist{MachineCode: machineCode}
+			}
+			a.MachineName = util.IfZero(machineName, a.MachineName)
+			renewLease(l, a)
+			return a, license.UpdateActivationLease(ctx, a)
+		} else if !license.IsErrActivationNotExist(err) {
+			return nil, err
+		}
+
+		if memberID > 0 {
+			previous, err := license.ListUserActivations(ctx, l.ID, memberID)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range previous {
+				if err := license.DeleteActivation(ctx, l, p); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		used, err := license.CountActiveActivations(ctx, l.ID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(l.Seats) {
+			return nil, license.ErrNoSeatsAvailable{LicenseID: l.ID, Seats: l.Seats}
+		}
+		a = &license.LicenseActivation{
+			LicenseID:   l.ID,
+			MachineCode: machineCode,
+			MachineName: machineName,
+			DeviceID:    GenerateDeviceID(),
+			UserID:      memberID,
+		}
+		renewLease(l, a)
+		return a, license.CreateActivation(ctx, a)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	l.SeatsUsed, err = license.CountActiveActivations(ctx, l.ID)
+	return l, a, err
+}
+
+// Heartbeat 续租浮动授权。租约已过期并被其他机器占用时返回 ErrActivationNotExist，客户端需要重新激活
+func Heartbeat(ctx context.Context, userID int64, licenseKey, machineCode string) (*license.License, *license.LicenseActivation, error) {
+	l, err := getActiveLicense(ctx, userID, licenseKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := license.GetActivation(ctx, l.ID, machineCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	if a.IsLeaseExpired() {
+		// 租约过期后席位可能已被其他机器占用，需要重新激活以检查席位
+		return nil, nil, license.ErrActivationNotExist{MachineCode: machineCode}
+	}
+	renewLease(l, a)
+	if err := license.UpdateActivationLease(ctx, a); err != nil {
+		return nil, nil, err
+	}
+	l.SeatsUsed, err = license.CountActiveActivations(ctx, l.ID)
+	return l, a, err
+}
+
+// Deactivate 客户端停用授权并释放席位
+func Deactivate(ctx context.Context, userID int64, licenseKey, machineCode string) error {
+	l, err := license.GetLicenseByUserAndKey(ctx, userID, NormalizeLicenseKey(licenseKey))
+	if err != nil {
+		return err
+	}
+	a, err := license.GetActivation(ctx, l.ID, machineCode)
+	if err != nil {
+		return err
+	}
+	return license.DeleteActivation(ctx, l, a)
+}
+
+// ReleaseActivation 授权所有者强制释放某台机器占用的席位
+func ReleaseActivation(ctx context.Context, userID, licenseID, activationID int64) error {
+	l, err := license.GetLicenseByUserAndID(ctx, userID, licenseID)
+	if err != nil {
+		return err
+	}
+	activations, err := license.ListActivations(ctx, l.ID)
+	if err != nil {
+		return err
+	}
+	for _, a := range activations {
+		if a.ID == activationID {
+			return license.DeleteActivation(ctx, l, a)
+		}
+	}
+	return license.ErrActivationNotExist{ID: activationID}
+}
+
+// ToggleLicense 切换多席位授权的启用状态，禁用后已激活机器的令牌出现在吊销列表中
+func ToggleLicense(ctx context.Context, userID, id int64) error {
+	l, err := license.GetLicenseByUserAndID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	l.IsEnabled = !l.IsEnabled
+	return license.UpdateLicense(ctx, l)
+}
+
+// DeleteLicense 删除用户的多席位授权
+func DeleteLicense(ctx context.Context, userID, id int64) error {
+	l, err := license.GetLicenseByUserAndID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	return license.DeleteLicense(ctx, l)
+}
+
+// ExpireStaleLeases 清理所有租约已过期的浮动授权激活，由定时任务调用
+func ExpireStaleLeases(ctx context.Context) (int64, error) {
+	return license.DeleteExpiredLeases(ctx, 0)
+}
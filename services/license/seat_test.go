@@ -0,0 +1,178 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeatLicense(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.AppDataPath, t.TempDir())()
+	resetSigningKey()
+	defer resetSigningKey()
+
+	_, err := CreateLicense(t.Context(), &CreateLicenseOptions{UserID: 2, Seats: 0})
+	assert.Error(t, err)
+	l, err := CreateLicense(t.Context(), &CreateLicenseOptions{UserID: 2, Name: "office", Seats: 2})
+	require.NoError(t, err)
+	key := FormatLicenseKey(l.LicenseKey)
+
+	_, a1, err := Activate(t.Context(), 2, key, "M-1", "pc-1")
+	require.NoError(t, err)
+	got, _, err := Activate(t.Context(), 2, key, "M-2", "pc-2")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, got.SeatsUsed)
+	_, _, err = Activate(t.Context(), 2, key, "M-3", "pc-3")
+	assert.True(t, license.IsErrNoSeatsAvailable(err))
+	_, _, err = Activate(t.Context(), 4, key, "M-3", "pc-3")
+	assert.True(t, license.IsErrLicenseNotExist(err))
+
+	// activating an activated machine again does not take another seat
+	_, again, err := Activate(t.Context(), 2, key, "M-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, a1.DeviceID, again.DeviceID)
+	assert.Equal(t, "pc-1", again.MachineName)
+	assert.True(t, again.LeaseExpiresUnix.IsZero())
+
+	// a released seat can be used by another machine, the old token is revoked
+	require.NoError(t, Deactivate(t.Context(), 2, key, "M-1"))
+	_, _, err = Activate(t.Context(), 2, key, "M-3", "pc-3")
+	require.NoError(t, err)
+	_, claims, err := IssueRevocationList(t.Context(), 2)
+	require.NoError(t, err)
+	assert.True(t, claims.IsRevoked(a1.DeviceID))
+
+	licenses, _, err := license.SearchLicenses(t.Context(), &license.SearchLicensesOptions{UserID: 2})
+	require.NoError(t, err)
+	require.Len(t, licenses, 1)
+	assert.EqualValues(t, 2, licenses[0].SeatsUsed)
+
+	require.NoError(t, ToggleLicense(t.Context(), 2, l.ID))
+	_, _, err = Activate(t.Context(), 2, key, "M-4", "pc-4")
+	assert.True(t, license.IsErrLicenseNotActive(err))
+	_, claims, err = IssueRevocationList(t.Context(), 2)
+	require.NoError(t, err)
+	assert.Len(t, claims.Revoked, 3)
+}
+
+func TestFloatingLicense(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.AppDataPath, t.TempDir())()
+	resetSigningKey()
+	defer resetSigningKey()
+
+	_, err := CreateLicense(t.Context(), &CreateLicenseOptions{UserID: 2, Type: license.LicenseTypeFloating, Seats: 1, LeaseDuration: time.Second})
+	assert.Error(t, err)
+	l, err := CreateLicense(t.Context(), &CreateLicenseOptions{UserID: 2, Type: license.LicenseTypeFloating, Seats: 1})
+	require.NoError(t, err)
+	assert.EqualValues(t, DefaultLeaseDuration/time.Second, l.LeaseSeconds)
+
+	l, a, err := Activate(t.Context(), 2, l.LicenseKey, "M-1", "pc-1")
+	require.NoError(t, err)
+	assert.False(t, a.LeaseExpiresUnix.IsZero())
+	_, _, err = Activate(t.Context(), 2, l.LicenseKey, "M-2", "pc-2")
+	assert.True(t, license.IsErrNoSeatsAvailable(err))
+
+	token, err := IssueActivationToken(l, a, "user2", &Entitlements{})
+	require.NoError(t, err)
+	claims, err := ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, a.LeaseExpiresUnix.AsTime().Unix(), claims.ExpiresAt.Unix())
+
+	_, a, err = Heartbeat(t.Context(), 2, l.LicenseKey, "M-1")
+	require.NoError(t, err)
+
+	// a stale lease frees the seat, the machine has to activate again
+	a.LeaseExpiresUnix = timeutil.TimeStamp(time.Now().Add(-time.Minute).Unix())
+	require.NoError(t, license.UpdateActivationLease(t.Context(), a))
+	_, _, err = Heartbeat(t.Context(), 2, l.LicenseKey, "M-1")
+	assert.True(t, license.IsErrActivationNotExist(err))
+	_, _, err = Activate(t.Context(), 2, l.LicenseKey, "M-2", "pc-2")
+	require.NoError(t, err)
+	_, _, err = Heartbeat(t.Context(), 2, l.LicenseKey, "M-1")
+	assert.True(t, license.IsErrActivationNotExist(err))
+
+	// leases expired without a new activation are cleaned up by the cron task
+	_, a, err = Heartbeat(t.Context(), 2, l.LicenseKey, "M-2")
+	require.NoError(t, err)
+	a.LeaseExpiresUnix = timeutil.TimeStamp(time.Now().Add(-time.Minute).Unix())
+	require.NoError(t, license.UpdateActivationLease(t.Context(), a))
+	n, err := ExpireStaleLeases(t.Context())
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+}
+
+func TestActivateOtherMemberMachine(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.AppDataPath, t.TempDir())()
+	resetSigningKey()
+	defer resetSigningKey()
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	l, err := CreateLicense(t.Context(), &CreateLicenseOptions{UserID: 3, Name: "team", Seats: 3})
+	require.NoError(t, err)
+	for _, memberID := range []int64{4, 28} {
+		_, err = AssignSeat(t.Context(), doer, 3, l.ID, memberID)
+		require.NoError(t, err)
+	}
+	_, a, err := ActivateSeat(t.Context(), 4, l.ID, "M-1", "pc-1")
+	require.NoError(t, err)
+
+	// neither another member nor the license key can renew a member's machine
+	_, _, err = ActivateSeat(t.Context(), 28, l.ID, "M-1", "pc-1")
+	assert.True(t, license.IsErrActivationNotExist(err), "%v", err)
+	_, _, err = Activate(t.Context(), 3, FormatLicenseKey(l.LicenseKey), "M-1", "pc-1")
+	assert.True(t, license.IsErrActivationNotExist(err), "%v", err)
+
+	// the member who activated the machine renews it
+	_, renewed, err := ActivateSeat(t.Context(), 4, l.ID, "M-1", "pc-1")
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, renewed.ID)
+
+	// and a machine activated with the license key cannot be taken over by a member
+	_, _, err = Activate(t.Context(), 3, FormatLicenseKey(l.LicenseKey), "M-2", "pc-2")
+	require.NoError(t, err)
+	_, _, err = ActivateSeat(t.Context(), 28, l.ID, "M-2", "pc-2")
+	assert.True(t, license.IsErrActivationNotExist(err), "%v", err)
+}
+
+func TestRevokeMemberSeats(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.AppDataPath, t.TempDir())()
+	resetSigningKey()
+	defer resetSigningKey()
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	l, err := CreateLicense(t.Context(), &CreateLicenseOptions{UserID: 3, Name: "team", Seats: 2})
+	require.NoError(t, err)
+	_, err = AssignSeat(t.Context(), doer, 3, l.ID, 4)
+	require.NoError(t, err)
+	_, a, err := ActivateSeat(t.Context(), 4, l.ID, "M-1", "pc-1")
+	require.NoError(t, err)
+
+	// seats in licenses of other owners are kept
+	require.NoError(t, RevokeMemberSeats(t.Context(), 2, 4))
+	unittest.AssertExistsAndLoadBean(t, &license.LicenseAssignment{LicenseID: l.ID, UserID: 4})
+
+	require.NoError(t, RevokeMemberSeats(t.Context(), 3, 4))
+	unittest.AssertNotExistsBean(t, &license.LicenseAssignment{LicenseID: l.ID, UserID: 4})
+	used, err := license.CountActiveActivations(t.Context(), l.ID)
+	require.NoError(t, err)
+	assert.Zero(t, used)
+	_, claims, err := IssueRevocationList(t.Context(), 3)
+	require.NoError(t, err)
+	assert.True(t, claims.IsRevoked(a.DeviceID))
+}
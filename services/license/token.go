@@ -0,0 +1,232 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenClaims 离线授权令牌的内容。令牌为 EdDSA 签名的 JWT，客户端使用公钥验证签名和机器码后即可离线使用，
+// 只需定期联网检查令牌是否被吊销。
+// 标准字段中 iss 为实例地址，sub 为设备 ID，jti 为令牌 ID，iat 为签发时间，exp 为到期时间（永久授权没有 exp）
+type TokenClaims struct {
+	MachineCode string                 `json:"machine_code"`
+	UserID      int64                  `json:"uid"`
+	UserName    string                 `json:"user"`
+	Product     string                 `json:"product,omitempty"`
+	Edition     string                 `json:"edition,omitempty"`
+	Features    []*license.Entitlement `json:"features,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// 令牌头部的 typ，区分离线授权令牌和吊销列表，避免一种被当作另一种使用
+const (
+	TokenTypeLicense        = "license+jwt"
+	TokenTypeRevocationList = "license-crl+jwt"
+)
+
+// PublicKey 实例的授权签名公钥
+type PublicKey struct {
+	KeyID string // 公钥指纹，与令牌头部的 kid 相同
+	Key   ed25519.PublicKey
+}
+
+// PEM 返回 PKIX 格式的 PEM 编码公钥
+func (k *PublicKey) PEM() (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(k.Key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// JWK 返回 JWK 格式的公钥
+func (k *PublicKey) JWK() map[string]string {
+	return map[string]string{
+		"kty": "OKP",
+		"crv": "Ed25519",
+		"alg": jwt.SigningMethodEdDSA.Alg(),
+		"kid": k.KeyID,
+		"x":   base64.RawURLEncoding.EncodeToString(k.Key),
+	}
+}
+
+var signingKey struct {
+	sync.Mutex
+	key ed25519.PrivateKey
+	kid string
+}
+
+// SigningKeyPath 实例签名私钥的保存位置，不存在时自动生成
+func SigningKeyPath() string {
+	return filepath.Join(setting.AppDataPath, "license", "signing_key.pem")
+}
+
+// loadSigningKey 读取实例签名私钥，首次使用时生成
+func loadSigningKey() (ed25519.PrivateKey, string, error) {
+	signingKey.Lock()
+	defer signingKey.Unlock()
+	if signingKey.key != nil {
+		return signingKey.key, signingKey.kid, nil
+	}
+
+	keyPath := SigningKeyPath()
+	data, err := os.ReadFile(keyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		data, err = createSigningKey(keyPath)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("load license signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, "", fmt.Errorf("load license signing key: %s is not a PEM encoded private key", keyPath)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("load license signing key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, "", fmt.Errorf("load license signing key: %s is not an ed25519 key", keyPath)
+	}
+	fingerprint, err := util.CreatePublicKeyFingerprint(key.Public())
+	if err != nil {
+		return nil, "", err
+	}
+
+	signingKey.key = key
+	signingKey.kid = base64.RawURLEncoding.EncodeToString(fingerprint)
+	return signingKey.key, signingKey.kid, nil
+}
+
+func createSigningKey(keyPath string) ([]byte, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	if err := os.MkdirAll(filepath.Dir(keyPath), os.ModePerm); err != nil {
+		return nil, err
+	}
+	// O_EXCL 避免多个进程同时生成时互相覆盖
+	f, err := os.OpenFile(keyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return os.ReadFile(keyPath)
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return nil, err
+	}
+	log.Info("Generated license signing key: %s", keyPath)
+	return data, nil
+}
+
+// GetPublicKey 获取实例的授权签名公钥，供客户端离线验证令牌
+func GetPublicKey() (*PublicKey, error) {
+	key, kid, err := loadSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{KeyID: kid, Key: key.Public().(ed25519.PublicKey)}, nil
+}
+
+// IssueToken 为有效的授权设备签发离线授权令牌，令牌的到期时间与设备授权相同，功能由 GetEntitlements 计算
+func IssueToken(device *license.AuthorizedDevice, userName string, entitlements *Entitlements) (string, error) {
+	if !device.IsValid() {
+		return "", errors.New("license is disabled or expired")
+	}
+	return issueToken(device.DeviceID, device.MachineCode, device.UserID, userName, device.ExpiryDate, entitlements)
+}
+
+// IssueActivationToken 为多席位授权的激活签发离线授权令牌，浮动授权的令牌在租约到期时失效
+func IssueActivationToken(l *license.License, a *license.LicenseActivation, userName string, entitlements *Entitlements) (string, error) {
+	if !l.IsValid() || a.IsLeaseExpired() {
+		return "", errors.New("license is disabled or expired")
+	}
+	return issueToken(a.DeviceID, a.MachineCode, l.UserID, userName, a.TokenExpiry(l), entitlements)
+}
+
+func issueToken(deviceID, machineCode string, userID int64, userName string, expiry timeutil.TimeStamp, entitlements *Entitlements) (string, error) {
+	claims := &TokenClaims{
+		MachineCode: machineCode,
+		UserID:      userID,
+		UserName:    userName,
+		Product:     entitlements.Product,
+		Edition:     entitlements.Edition,
+		Features:    entitlements.Features,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:   setting.AppURL,
+			Subject:  deviceID,
+			ID:       rand.Text(),
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		},
+	}
+	if !expiry.IsZero() {
+		claims.ExpiresAt = jwt.NewNumericDate(expiry.AsTime())
+	}
+	return sign(claims, TokenTypeLicense)
+}
+
+// ParseToken 验证离线授权令牌的签名和有效期，与客户端的离线验证逻辑相同
+func ParseToken(tokenString string) (*TokenClaims, error) {
+	claims := &TokenClaims{}
+	if err := verify(tokenString, TokenTypeLicense, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// sign 使用实例密钥签名，头部带有公钥指纹和令牌类型
+func sign(claims jwt.Claims, typ string) (string, error) {
+	key, kid, err := loadSigningKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = kid
+	token.Header["typ"] = typ
+	return token.SignedString(key)
+}
+
+// verify 使用实例公钥验证签名、令牌类型和有效期，并解析到 claims
+func verify(tokenString, typ string, claims jwt.Claims) error {
+	key, _, err := loadSigningKey()
+	if err != nil {
+		return err
+	}
+	_, err = jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		if token.Header["typ"] != typ {
+			return nil, fmt.Errorf("unexpected token type %v", token.Header["typ"])
+		}
+		return key.Public(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}), jwt.WithIssuedAt())
+	return err
+}
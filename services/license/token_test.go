@@ -0,0 +1,99 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package license
+
+import (
+	"crypto/ed25519"
+	"os"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/license"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resetSigningKey() {
+	signingKey.Lock()
+	defer signingKey.Unlock()
+	signingKey.key, signingKey.kid = nil, ""
+}
+
+func TestIssueToken(t *testing.T) {
+	defer test.MockVariableValue(&setting.AppDataPath, t.TempDir())()
+	defer test.MockVariableValue(&setting.AppURL, "https://gitea.example.com/")()
+	resetSigningKey()
+	defer resetSigningKey()
+
+	device := &license.AuthorizedDevice{
+		UserID:      2,
+		DeviceID:    "DEV-1",
+		MachineCode: "MACHINE-1",
+		IsEnabled:   true,
+		ExpiryDate:  timeutil.TimeStamp(time.Now().Add(time.Hour).Unix()),
+	}
+	entitlements := &Entitlements{
+		Product:  "app",
+		Edition:  "pro",
+		Features: []*license.Entitlement{{Name: "export"}, {Name: "seats", Quantity: 5}},
+	}
+	token, err := IssueToken(device, "user2", entitlements)
+	require.NoError(t, err)
+
+	claims, err := ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "MACHINE-1", claims.MachineCode)
+	assert.EqualValues(t, 2, claims.UserID)
+	assert.Equal(t, "user2", claims.UserName)
+	assert.Equal(t, "app", claims.Product)
+	assert.Equal(t, "pro", claims.Edition)
+	assert.Equal(t, entitlements.Features, claims.Features)
+	assert.Equal(t, "DEV-1", claims.Subject)
+	assert.Equal(t, "https://gitea.example.com/", claims.Issuer)
+	assert.Equal(t, device.ExpiryDate.AsTime().Unix(), claims.ExpiresAt.Unix())
+
+	// the key is stored and reused after a restart
+	info, err := os.Stat(SigningKeyPath())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	resetSigningKey()
+	publicKey, err := GetPublicKey()
+	require.NoError(t, err)
+	parsed, err := jwt.ParseWithClaims(token, &TokenClaims{}, func(*jwt.Token) (any, error) {
+		return publicKey.Key, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, publicKey.KeyID, parsed.Header["kid"])
+	assert.Equal(t, publicKey.KeyID, publicKey.JWK()["kid"])
+	pemKey, err := publicKey.PEM()
+	require.NoError(t, err)
+	assert.Contains(t, pemKey, "BEGIN PUBLIC KEY")
+
+	// permanent licenses have no expiry
+	device.ExpiryDate = 0
+	token, err = IssueToken(device, "user2", entitlements)
+	require.NoError(t, err)
+	claims, err = ParseToken(token)
+	require.NoError(t, err)
+	assert.Nil(t, claims.ExpiresAt)
+
+	// tokens signed by another key are rejected
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	forgedToken := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	forgedToken.Header["typ"] = TokenTypeLicense
+	forged, err := forgedToken.SignedString(otherKey)
+	require.NoError(t, err)
+	_, err = ParseToken(forged)
+	assert.Error(t, err)
+
+	device.IsEnabled = false
+	_, err = IssueToken(device, "user2", entitlements)
+	assert.Error(t, err)
+}
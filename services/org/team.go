@@ -109,7 +109,7 @@ func UpdateTeam(ctx context.Context, t *organization.Team, authChanged, includeA
 
 		sess := db.GetEngine(ctx)
 		if _, err = sess.ID(t.ID).Cols("name", "lower_name", "description",
-			"can_create_org_repo", "authorize", "includes_all_repositories").Update(t); err != nil {
+			"can_create_org_repo", "can_manage_licenses", "authorize", "includes_all_repositories").Update(t); err != nil {
 			return fmt.Errorf("update: %w", err)
 		}
 
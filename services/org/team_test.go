@@ -19,6 +19,7 @@ import (
 	repo_service "code.gitea.io/gitea/services/repository"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTeam_AddMember(t *testing.T) {
@@ -93,6 +94,21 @@ func TestRemoveTeamMemberRemovesSubscriptionsAndStopwatches(t *testing.T) {
 	assert.False(t, hasStopwatch)
 }
 
+func TestRemoveTeamMemberRevokesLicenseSeats(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	// user 4 leaves org 3 when removed from team 2, the only team they belong to
+	team := unittest.AssertExistsAndLoadBean(t, &organization.Team{ID: 2})
+	team.CanManageLicenses = true
+	require.NoError(t, UpdateTeam(t.Context(), team, false, false))
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	l, a := activateMemberSeat(t, team.OrgID, user4.ID)
+
+	require.NoError(t, RemoveTeamMember(t.Context(), team, user4))
+	unittest.AssertNotExistsBean(t, &organization.OrgUser{OrgID: team.OrgID, UID: user4.ID})
+	assertSeatRevoked(t, l, a)
+}
+
 func TestNewTeam(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 
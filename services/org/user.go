@@ -13,6 +13,7 @@ import (
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	license_service "code.gitea.io/gitea/services/license"
 )
 
 // RemoveOrgUser removes user from given organization.
@@ -93,6 +94,8 @@ func RemoveOrgUser(ctx context.Context, org *organization.Organization, user *us
 				return err
 			}
 		}
-		return nil
+
+		// Revoke the license seats assigned to the member and release their activations.
+		return license_service.RevokeMemberSeats(ctx, org.ID, user.ID)
 	})
 }
@@ -6,11 +6,14 @@ package org
 import (
 	"testing"
 
+	"code.gitea.io/gitea/models/license"
 	"code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	license_service "code.gitea.io/gitea/services/license"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUser_RemoveMember(t *testing.T) {
@@ -71,3 +74,36 @@ func TestRemoveOrgUser(t *testing.T) {
 	unittest.AssertExistsAndLoadBean(t, &organization.OrgUser{OrgID: org7.ID, UID: user5.ID})
 	unittest.CheckConsistencyFor(t, &user_model.User{}, &organization.Team{})
 }
+
+// activateMemberSeat assigns a seat of a new license of the organization to the member
+// and activates it on a machine.
+func activateMemberSeat(t *testing.T, orgID, memberID int64) (*license.License, *license.LicenseActivation) {
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	l, err := license_service.CreateLicense(t.Context(), &license_service.CreateLicenseOptions{UserID: orgID, Name: "team", Seats: 1})
+	require.NoError(t, err)
+	_, err = license_service.AssignSeat(t.Context(), doer, orgID, l.ID, memberID)
+	require.NoError(t, err)
+	_, a, err := license_service.ActivateSeat(t.Context(), memberID, l.ID, "M-1", "pc-1")
+	require.NoError(t, err)
+	return l, a
+}
+
+func assertSeatRevoked(t *testing.T, l *license.License, a *license.LicenseActivation) {
+	_, err := license.GetAssignment(t.Context(), l.ID, a.UserID)
+	assert.True(t, license.IsErrAssignmentNotExist(err), "%v", err)
+	used, err := license.CountActiveActivations(t.Context(), l.ID)
+	require.NoError(t, err)
+	assert.Zero(t, used)
+	unittest.AssertExistsAndLoadBean(t, &license.RevokedDevice{UserID: l.UserID, DeviceID: a.DeviceID})
+}
+
+func TestRemoveOrgUserRevokesLicenseSeats(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	org := unittest.AssertExistsAndLoadBean(t, &organization.Organization{ID: 3})
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	l, a := activateMemberSeat(t, org.ID, user4.ID)
+
+	require.NoError(t, RemoveOrgUser(t.Context(), org, user4))
+	assertSeatRevoked(t, l, a)
+}
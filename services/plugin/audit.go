@@ -0,0 +1,23 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// writeAudit 记录插件管理操作，doer 为 nil 时记为系统操作。写入失败只记录日志，不影响已完成的操作
+func writeAudit(ctx context.Context, doer *user_model.User, a *plugin_model.Audit) {
+	if doer != nil {
+		a.DoerID = doer.ID
+		a.DoerName = doer.Name
+	}
+	if err := plugin_model.InsertAudit(ctx, a); err != nil {
+		log.Error("Failed to write audit log for plugin %s %s: %v", a.PluginID, a.Action, err)
+	}
+}
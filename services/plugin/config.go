@@ -0,0 +1,184 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// PluginConfig 插件配置页面的数据
+type PluginConfig struct {
+	Plugin    *plugin_model.Plugin
+	Fields    []*plugin_model.ConfigField
+	Values    map[string]string // 表单中显示的值，秘密字段始终为空
+	Config    map[string]any    // 非秘密字段的值（含默认值）
+	SecretSet map[string]bool   // 已设置的秘密字段
+}
+
+// loadConfig 读取插件的 config_schema 和已保存的配置（秘密字段已解密）
+func (l *PluginLoader) loadConfig(dbPlugin *plugin_model.Plugin, pluginPath string) (*plugin_model.PluginMetadata, []*plugin_model.ConfigField, map[string]any, error) {
+	metadata, err := l.readMetadata(pluginPath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("read metadata: %w", err)
+	}
+	fields := plugin_model.ParseConfigFields(metadata.ConfigSchema)
+	config, err := dbPlugin.GetConfig(fields, setting.SecretKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return metadata, fields, config, nil
+}
+
+// pushConfig 将已保存的配置（含默认值）下发给刚初始化的插件实例
+func pushConfig(ctx context.Context, pluginID string, metadata *plugin_model.PluginMetadata, pluginInstance plugin_model.IPlugin) error {
+	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	if err != nil {
+		return err
+	}
+	fields := plugin_model.ParseConfigFields(metadata.ConfigSchema)
+	config, err := dbPlugin.GetConfig(fields, setting.SecretKey)
+	if err != nil {
+		return err
+	}
+	if err := plugin_model.ValidateConfig(pluginID, metadata.ConfigSchema, config); err != nil {
+		// 升级后 schema 可能变化，仍下发已保存的配置，由管理员在配置页面修正
+		log.Warn("Saved config of plugin %s does not match its config_schema: %v", pluginID, err)
+	}
+	return pluginInstance.SetConfig(plugin_model.ApplyConfigDefaults(fields, config))
+}
+
+// GetConfig 获取插件配置页面的数据
+func (m *PluginManager) GetConfig(ctx context.Context, pluginID string) (*PluginConfig, error) {
+	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	if err != nil {
+		return nil, err
+	}
+	_, fields, config, err := m.loader.loadConfig(dbPlugin, m.loader.pluginPath(dbPlugin))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PluginConfig{
+		Plugin:    dbPlugin,
+		Fields:    fields,
+		Values:    make(map[string]string, len(fields)),
+		Config:    make(map[string]any, len(fields)),
+		SecretSet: make(map[string]bool),
+	}
+	config = plugin_model.ApplyConfigDefaults(fields, config)
+	for _, f := range fields {
+		if f.Secret {
+			result.SecretSet[f.Name] = config[f.Name] != nil && config[f.Name] != ""
+			continue
+		}
+		result.Values[f.Name] = f.FormatValue(config[f.Name])
+		if config[f.Name] != nil {
+			result.Config[f.Name] = config[f.Name]
+		}
+	}
+	return result, nil
+}
+
+// UpdateConfigOptions 配置表单提交的值
+type UpdateConfigOptions struct {
+	Values       map[string]string // 按字段名提交的文本，秘密字段为空时保留原值
+	ClearSecrets []string          // 需要清除的秘密字段
+}
+
+// UpdateConfig 按 config_schema 校验并保存配置，插件已加载时立即下发
+func (m *PluginManager) UpdateConfig(ctx context.Context, doer *user_model.User, pluginID string, opts UpdateConfigOptions) error {
+	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	if err != nil {
+		return err
+	}
+	metadata, fields, old, err := m.loader.loadConfig(dbPlugin, m.loader.pluginPath(dbPlugin))
+	if err != nil {
+		return err
+	}
+
+	config := make(map[string]any, len(fields))
+	for _, f := range fields {
+		s := opts.Values[f.Name]
+		if f.Secret && s == "" {
+			if old[f.Name] != nil && !slices.Contains(opts.ClearSecrets, f.Name) {
+				config[f.Name] = old[f.Name]
+			}
+			continue
+		}
+		v, err := f.ParseValue(s)
+		if err != nil {
+			return plugin_model.ErrPluginConfigInvalid{PluginID: pluginID, Field: f.Name, Reason: err.Error()}
+		}
+		if v != nil {
+			config[f.Name] = v
+		}
+	}
+
+	if err := plugin_model.ValidateConfig(pluginID, metadata.ConfigSchema, config); err != nil {
+		return err
+	}
+	return m.saveConfig(ctx, doer, dbPlugin, fields, old, config)
+}
+
+// PatchConfig 按 config_schema 校验并保存 API 提交的配置：未提交的字段保留原值，值为 null 的字段被清除
+func (m *PluginManager) PatchConfig(ctx context.Context, doer *user_model.User, pluginID string, values map[string]any) error {
+	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	if err != nil {
+		return err
+	}
+	metadata, fields, old, err := m.loader.loadConfig(dbPlugin, m.loader.pluginPath(dbPlugin))
+	if err != nil {
+		return err
+	}
+
+	config := make(map[string]any, len(fields))
+	maps.Copy(config, old)
+	for name, v := range values {
+		if !slices.ContainsFunc(fields, func(f *plugin_model.ConfigField) bool { return f.Name == name }) {
+			return plugin_model.ErrPluginConfigInvalid{PluginID: pluginID, Field: name, Reason: "unknown field"}
+		}
+		if v == nil {
+			delete(config, name)
+		} else {
+			config[name] = v
+		}
+	}
+
+	if err := plugin_model.ValidateConfig(pluginID, metadata.ConfigSchema, config); err != nil {
+		return err
+	}
+	return m.saveConfig(ctx, doer, dbPlugin, fields, old, config)
+}
+
+// saveConfig 保存已校验的配置并下发给正在运行的插件实例，审计日志记录与 old 的差异
+func (m *PluginManager) saveConfig(ctx context.Context, doer *user_model.User, dbPlugin *plugin_model.Plugin, fields []*plugin_model.ConfigField, old, config map[string]any) error {
+	if err := dbPlugin.SetConfig(fields, setting.SecretKey, config); err != nil {
+		return err
+	}
+	if err := plugin_model.UpdatePlugin(ctx, dbPlugin); err != nil {
+		return fmt.Errorf("update database: %w", err)
+	}
+	writeAudit(ctx, doer, &plugin_model.Audit{
+		PluginID:   dbPlugin.PluginID,
+		Action:     plugin_model.AuditActionConfig,
+		NewVersion: dbPlugin.Version,
+		ConfigDiff: plugin_model.DiffConfig(fields, old, config),
+	})
+
+	if pluginInstance, ok := m.loader.GetPlugin(dbPlugin.PluginID); ok {
+		if err := pluginInstance.SetConfig(plugin_model.ApplyConfigDefaults(fields, config)); err != nil {
+			return fmt.Errorf("set plugin config: %w", err)
+		}
+	}
+	log.Info("Plugin config updated: %s", dbPlugin.PluginID)
+	return nil
+}
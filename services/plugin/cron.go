@@ -0,0 +1,98 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"sync"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/services/cron"
+)
+
+var (
+	cronTasksMu sync.Mutex
+	cronTasks   = make(map[string][]string) // 插件 ID -> 已注册的完整任务名
+)
+
+// mountCronTasks 注册已启用插件的定时任务，未启用时移除。重复调用时先移除旧实例注册的任务
+func mountCronTasks(pluginID string, p plugin_model.IPlugin, enabled bool) {
+	unmountCronTasks(pluginID)
+	provider, ok := p.(plugin_model.CronProvider)
+	if !enabled || !ok {
+		return
+	}
+
+	var tasks []*plugin_model.CronTask
+	if err := callPlugin(func() error {
+		tasks = provider.CronTasks()
+		return nil
+	}); err != nil {
+		log.Error("Failed to get cron tasks of plugin %s: %v", pluginID, err)
+		return
+	}
+
+	names := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if task == nil || !plugin_model.IsValidCronTaskName(task.Name) || task.Run == nil {
+			log.Warn("Plugin %s registered an invalid cron task: %+v", pluginID, task)
+			continue
+		}
+		name := plugin_model.CronTaskName(pluginID, task.Name)
+		title := task.Title
+		if title == "" {
+			title = name
+		}
+		schedule := task.Schedule
+		if schedule == "" {
+			schedule = plugin_model.DefaultCronSchedule
+		}
+
+		run := task.Run
+		config := cron.NewPluginTaskConfig(title, cron.BaseConfig{
+			Enabled:    true,
+			RunAtStart: task.RunAtStart,
+			Schedule:   schedule,
+		})
+		if err := cron.RegisterTask(name, config, func(ctx context.Context, _ *user_model.User, _ cron.Config) error {
+			return runCronTask(ctx, pluginID, run)
+		}); err != nil {
+			log.Error("Failed to register cron task %s: %v", name, err)
+			continue
+		}
+		names = append(names, name)
+	}
+
+	cronTasksMu.Lock()
+	cronTasks[pluginID] = names
+	cronTasksMu.Unlock()
+}
+
+// unmountCronTasks 移除插件注册的定时任务
+func unmountCronTasks(pluginID string) {
+	cronTasksMu.Lock()
+	names := cronTasks[pluginID]
+	delete(cronTasks, pluginID)
+	cronTasksMu.Unlock()
+
+	for _, name := range names {
+		cron.UnregisterTask(name)
+	}
+}
+
+// runCronTask 执行插件的定时任务，失败和 panic 计入插件的运行状况
+func runCronTask(ctx context.Context, pluginID string, run func(context.Context) error) error {
+	// 管理员手动执行时插件可能刚被禁用
+	if !GetRouter().IsEnabled(pluginID) {
+		return nil
+	}
+	if err := callPlugin(func() error { return run(ctx) }); err != nil {
+		recordFailure(pluginID, HealthSourceCron, err)
+		return err
+	}
+	recordSuccess(pluginID)
+	return nil
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/hashicorp/go-version"
+)
+
+// pluginNode 依赖图中的已安装插件
+type pluginNode struct {
+	Plugin   *plugin_model.Plugin
+	Metadata *plugin_model.PluginMetadata // plugin.json 无法读取时为 nil
+	Deps     []*plugin_model.Dependency
+	DepsErr  error // 依赖声明无法解析，插件在修正前不能加载和启用
+}
+
+// isNative 插件是否以 native 运行时运行，未声明运行时的插件默认为 native
+func (n *pluginNode) isNative() bool {
+	return n.Metadata != nil && n.Metadata.GetRuntime() == plugin_model.RuntimeNative
+}
+
+// loadNodes 读取所有已安装插件的元数据和依赖声明
+func (l *PluginLoader) loadNodes(ctx context.Context) (map[string]*pluginNode, error) {
+	plugins, err := plugin_model.ListPlugins(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list plugins from database: %w", err)
+	}
+
+	nodes := make(map[string]*pluginNode, len(plugins))
+	for _, p := range plugins {
+		if !p.IsInstalled {
+			continue
+		}
+		node := &pluginNode{Plugin: p}
+		nodes[p.PluginID] = node
+
+		metadata, err := l.readMetadata(l.pluginPath(p))
+		if err != nil {
+			log.Warn("Failed to read metadata of plugin %s: %v", p.PluginID, err)
+			continue
+		}
+		node.Metadata = metadata
+		node.Deps, node.DepsErr = metadata.ParseDependencies()
+	}
+	return nodes, nil
+}
+
+// checkGiteaVersion 检查插件声明的 Gitea 版本约束，开发版本（如 "development"）无法比较时跳过
+func checkGiteaVersion(metadata *plugin_model.PluginMetadata) error {
+	if metadata.GiteaVersion == "" {
+		return nil
+	}
+	if _, err := version.NewVersion(setting.AppVer); err != nil {
+		return nil
+	}
+	if !plugin_model.CheckVersionConstraint(metadata.GiteaVersion, setting.AppVer) {
+		return plugin_model.ErrPluginIncompatible{
+			PluginID:     metadata.ID,
+			Constraint:   metadata.GiteaVersion,
+			GiteaVersion: setting.AppVer,
+		}
+	}
+	return nil
+}
+
+// checkDependencies 检查依赖均已安装且版本满足约束，requireEnabled 时还要求依赖已启用
+func checkDependencies(pluginID string, deps []*plugin_model.Dependency, nodes map[string]*pluginNode, requireEnabled bool) error {
+	for _, dep := range deps {
+		notSatisfied := plugin_model.ErrPluginDependencyNotSatisfied{
+			PluginID:     pluginID,
+			DependencyID: dep.PluginID,
+			Constraint:   dep.Constraint,
+		}
+		node, ok := nodes[dep.PluginID]
+		switch {
+		case dep.PluginID == pluginID:
+			notSatisfied.Reason = "plugin depends on itself"
+		case !ok:
+			notSatisfied.Reason = "not installed"
+		case !dep.Satisfies(node.Plugin.Version):
+			notSatisfied.Reason = "installed version is " + node.Plugin.Version
+		case requireEnabled && !node.Plugin.IsEnabled:
+			notSatisfied.Reason = "not enabled"
+		default:
+			continue
+		}
+		return notSatisfied
+	}
+	return nil
+}
+
+// checkNodeDependencies 检查已安装插件的依赖声明有效且均已满足
+func checkNodeDependencies(pluginID string, node *pluginNode, nodes map[string]*pluginNode, requireEnabled bool) error {
+	if node.DepsErr != nil {
+		return node.DepsErr
+	}
+	return checkDependencies(pluginID, node.Deps, nodes, requireEnabled)
+}
+
+// loadOrder 按依赖关系计算拓扑加载顺序，依赖总在依赖者之前；
+// 处于循环依赖中（或依赖循环中插件）的插件无法排序，单独返回
+func loadOrder(nodes map[string]*pluginNode) (order, cyclic []string) {
+	inDegree := make(map[string]int, len(nodes))
+	dependents := make(map[string][]string, len(nodes))
+	for id, node := range nodes {
+		inDegree[id] = 0
+		for _, dep := range node.Deps {
+			if _, ok := nodes[dep.PluginID]; !ok {
+				continue
+			}
+			dependents[dep.PluginID] = append(dependents[dep.PluginID], id)
+		}
+	}
+
+	for _, ids := range dependents {
+		for _, id := range ids {
+			inDegree[id]++
+		}
+	}
+
+	// 同一层按 ID 排序，保证加载顺序稳定
+	var ready []string
+	for id, n := range inDegree {
+		if n == 0 {
+			ready = append(ready, id)
+		}
+	}
+	slices.Sort(ready)
+
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+
+		var next []string
+		for _, dependent := range dependents[id] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				next = append(next, dependent)
+			}
+		}
+		ready = append(ready, next...)
+		slices.Sort(ready)
+	}
+
+	for id, n := range inDegree {
+		if n > 0 {
+			cyclic = append(cyclic, id)
+		}
+	}
+	slices.Sort(cyclic)
+	return order, cyclic
+}
+
+// dependentsOf 返回依赖指定插件的已安装插件，enabledOnly 时只返回已启用的
+func dependentsOf(pluginID string, nodes map[string]*pluginNode, enabledOnly bool) []string {
+	var result []string
+	for id, node := range nodes {
+		if id == pluginID || (enabledOnly && !node.Plugin.IsEnabled) {
+			continue
+		}
+		for _, dep := range node.Deps {
+			if dep.PluginID == pluginID {
+				result = append(result, id)
+				break
+			}
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// DependencyEdge 依赖图中的一条边：From 依赖 To
+type DependencyEdge struct {
+	From       string
+	To         string
+	Constraint string
+	Satisfied  bool
+	Reason     string
+}
+
+// DependencyGraph 已安装插件的依赖关系
+type DependencyGraph struct {
+	Order        []string          // 加载顺序
+	Cyclic       []string          // 存在循环依赖而无法加载的插件
+	Edges        []*DependencyEdge // 按 From、To 排序
+	Incompatible map[string]string // 与当前 Gitea 版本不兼容的插件及其版本约束
+	Unresolved   map[string]string // 依赖声明无法解析的插件及其无效的声明
+	Native       map[string]bool   // native 运行时的插件，不能在运行时升级或回滚
+}
+
+// DependencyGraph 计算已安装插件的依赖关系，用于管理页面展示
+func (m *PluginManager) DependencyGraph(ctx context.Context) (*DependencyGraph, error) {
+	nodes, err := m.loader.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &DependencyGraph{Incompatible: make(map[string]string), Unresolved: make(map[string]string), Native: make(map[string]bool)}
+	graph.Order, graph.Cyclic = loadOrder(nodes)
+
+	for _, id := range slices.Sorted(maps.Keys(nodes)) {
+		node := nodes[id]
+		if node.Metadata != nil && checkGiteaVersion(node.Metadata) != nil {
+			graph.Incompatible[id] = node.Metadata.GiteaVersion
+		}
+		if e, ok := node.DepsErr.(plugin_model.ErrPluginDependencyInvalid); ok {
+			graph.Unresolved[id] = e.Dependency
+		}
+		if node.isNative() {
+			graph.Native[id] = true
+		}
+		for _, dep := range node.Deps {
+			edge := &DependencyEdge{From: id, To: dep.PluginID, Constraint: dep.Constraint, Satisfied: true}
+			if err := checkDependencies(id, []*plugin_model.Dependency{dep}, nodes, node.Plugin.IsEnabled); err != nil {
+				edge.Satisfied = false
+				edge.Reason = err.(plugin_model.ErrPluginDependencyNotSatisfied).Reason
+			}
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+	return graph, nil
+}
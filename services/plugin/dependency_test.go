@@ -0,0 +1,124 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"testing"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNode(t *testing.T, id, version string, enabled bool, deps ...string) *pluginNode {
+	metadata := &plugin_model.PluginMetadata{ID: id, Version: version, Dependencies: deps}
+	parsed, err := metadata.ParseDependencies()
+	require.NoError(t, err)
+	return &pluginNode{
+		Plugin:   &plugin_model.Plugin{PluginID: id, Version: version, IsEnabled: enabled, IsInstalled: true},
+		Metadata: metadata,
+		Deps:     parsed,
+	}
+}
+
+func TestParseDependency(t *testing.T) {
+	dep, err := plugin_model.ParseDependency("license-core")
+	require.NoError(t, err)
+	assert.Equal(t, &plugin_model.Dependency{PluginID: "license-core"}, dep)
+
+	dep, err = plugin_model.ParseDependency("license-core >=1.2.0, <2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "license-core", dep.PluginID)
+	assert.True(t, dep.Satisfies("1.5.0"))
+	assert.False(t, dep.Satisfies("2.0.0"))
+
+	dep, err = plugin_model.ParseDependency("license-core>=1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, ">=1.2.0", dep.Constraint)
+
+	_, err = plugin_model.ParseDependency("license-core >=abc")
+	assert.True(t, plugin_model.IsErrPluginDependencyInvalid(err))
+	_, err = plugin_model.ParseDependency("../core")
+	assert.True(t, plugin_model.IsErrPluginDependencyInvalid(err))
+}
+
+func TestCheckGiteaVersion(t *testing.T) {
+	metadata := &plugin_model.PluginMetadata{ID: "test", GiteaVersion: ">=1.20.0"}
+
+	defer test.MockVariableValue(&setting.AppVer, "1.26.0+dev-10-gabcdef")()
+	assert.NoError(t, checkGiteaVersion(metadata))
+
+	defer test.MockVariableValue(&setting.AppVer, "1.21.0-rc1")()
+	assert.NoError(t, checkGiteaVersion(metadata))
+
+	defer test.MockVariableValue(&setting.AppVer, "1.19.3")()
+	assert.True(t, plugin_model.IsErrPluginIncompatible(checkGiteaVersion(metadata)))
+
+	// development builds are not comparable
+	defer test.MockVariableValue(&setting.AppVer, "development")()
+	assert.NoError(t, checkGiteaVersion(metadata))
+}
+
+func TestCheckDependencies(t *testing.T) {
+	nodes := map[string]*pluginNode{
+		"core": newTestNode(t, "core", "1.4.0", false),
+	}
+	deps := newTestNode(t, "ext", "1.0.0", false, "core >=1.2.0").Deps
+
+	assert.NoError(t, checkDependencies("ext", deps, nodes, false))
+
+	err := checkDependencies("ext", deps, nodes, true)
+	require.True(t, plugin_model.IsErrPluginDependencyNotSatisfied(err))
+	assert.Equal(t, "not enabled", err.(plugin_model.ErrPluginDependencyNotSatisfied).Reason)
+
+	nodes["core"].Plugin.IsEnabled = true
+	assert.NoError(t, checkDependencies("ext", deps, nodes, true))
+
+	nodes["core"].Plugin.Version = "1.1.0"
+	assert.True(t, plugin_model.IsErrPluginDependencyNotSatisfied(checkDependencies("ext", deps, nodes, false)))
+
+	delete(nodes, "core")
+	assert.True(t, plugin_model.IsErrPluginDependencyNotSatisfied(checkDependencies("ext", deps, nodes, false)))
+}
+
+func TestCheckNodeDependencies(t *testing.T) {
+	nodes := map[string]*pluginNode{
+		"core": newTestNode(t, "core", "1.4.0", true),
+		"ext":  newTestNode(t, "ext", "1.0.0", false, "core >=1.2.0"),
+	}
+	assert.NoError(t, checkNodeDependencies("ext", nodes["ext"], nodes, true))
+
+	// an invalid spec is not treated as having no dependencies
+	metadata := &plugin_model.PluginMetadata{ID: "broken", Version: "1.0.0", Dependencies: []string{"core >=abc"}}
+	broken := &pluginNode{Plugin: &plugin_model.Plugin{PluginID: "broken", IsInstalled: true}, Metadata: metadata}
+	broken.Deps, broken.DepsErr = metadata.ParseDependencies()
+	nodes["broken"] = broken
+
+	err := checkNodeDependencies("broken", broken, nodes, false)
+	assert.True(t, plugin_model.IsErrPluginDependencyInvalid(err))
+}
+
+func TestLoadOrder(t *testing.T) {
+	nodes := map[string]*pluginNode{
+		"app":     newTestNode(t, "app", "1.0.0", true, "ext", "core"),
+		"ext":     newTestNode(t, "ext", "1.0.0", true, "core"),
+		"core":    newTestNode(t, "core", "1.0.0", true),
+		"alone":   newTestNode(t, "alone", "1.0.0", false, "missing"),
+		"cycle-a": newTestNode(t, "cycle-a", "1.0.0", false, "cycle-b"),
+		"cycle-b": newTestNode(t, "cycle-b", "1.0.0", false, "cycle-a"),
+		"cycle-c": newTestNode(t, "cycle-c", "1.0.0", false, "cycle-a"),
+	}
+
+	order, cyclic := loadOrder(nodes)
+	assert.Equal(t, []string{"alone", "core", "ext", "app"}, order)
+	assert.Equal(t, []string{"cycle-a", "cycle-b", "cycle-c"}, cyclic)
+
+	assert.Equal(t, []string{"app", "ext"}, dependentsOf("core", nodes, false))
+	nodes["ext"].Plugin.IsEnabled = false
+	assert.Equal(t, []string{"app"}, dependentsOf("core", nodes, true))
+	assert.Empty(t, dependentsOf("app", nodes, false))
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/timeutil"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+const eventHandleTimeout = 30 * time.Second
+
+// NotifierPlugin 希望直接接收 notify.Notifier 调用的插件实现此接口（仅 native 运行时）。
+// 与 EventSubscriber 一样经队列异步投递，调用时模型会按事件中的 ID 重新加载
+type NotifierPlugin interface {
+	Notifier() notify_service.Notifier
+}
+
+// eventTask 事件队列中的一项，投递给一个插件
+type eventTask struct {
+	PluginID string
+	Event    *plugin_model.Event
+}
+
+// EventStats 插件的事件投递统计，进程重启后清零
+type EventStats struct {
+	Delivered     int64
+	Failed        int64
+	LastError     string
+	LastErrorTime timeutil.TimeStamp
+}
+
+var (
+	eventQueue    *queue.WorkerPoolQueue[*eventTask]
+	eventInitOnce sync.Once
+
+	eventStatsMu sync.RWMutex
+	eventStats   = make(map[string]*EventStats)
+)
+
+// initEventBus 创建事件队列并注册到 notify，进程内只执行一次
+func initEventBus() error {
+	var err error
+	eventInitOnce.Do(func() {
+		eventQueue = queue.CreateSimpleQueue(graceful.GetManager().ShutdownContext(), "plugin_event", handleEventTasks)
+		if eventQueue == nil {
+			err = errors.New("unable to create plugin_event queue")
+			return
+		}
+		go graceful.GetManager().RunWithCancel(eventQueue)
+		notify_service.RegisterNotifier(&eventNotifier{})
+	})
+	return err
+}
+
+// subscribes 检查插件是否接收此类事件
+func subscribes(p plugin_model.IPlugin, eventType string) bool {
+	if s, ok := p.(plugin_model.EventSubscriber); ok && plugin_model.IsSubscribed(s.SubscribedEvents(), eventType) {
+		return true
+	}
+	_, ok := p.(NotifierPlugin)
+	return ok
+}
+
+// publishEvent 将事件放入队列，投递给所有已启用且订阅了此事件的插件
+func publishEvent(event *plugin_model.Event) {
+	if eventQueue == nil {
+		return
+	}
+	event.Created = timeutil.TimeStampNow()
+	for id, p := range GetLoader().GetAllPlugins() {
+		if !GetRouter().IsEnabled(id) || !subscribes(p, event.Type) {
+			continue
+		}
+		if err := eventQueue.Push(&eventTask{PluginID: id, Event: event}); err != nil {
+			log.Error("Unable to push %s event for plugin %s: %v", event.Type, id, err)
+		}
+	}
+}
+
+func handleEventTasks(tasks ...*eventTask) []*eventTask {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, task := range tasks {
+		// 排队期间插件可能已被禁用或卸载
+		p, ok := GetLoader().GetPlugin(task.PluginID)
+		if !ok || !GetRouter().IsEnabled(task.PluginID) {
+			continue
+		}
+		recordEvent(task.PluginID, deliverEvent(ctx, task.PluginID, p, task.Event))
+	}
+	return nil
+}
+
+// deliverEvent 在超时时间内将事件交给插件处理，插件 panic 视为处理失败
+func deliverEvent(ctx context.Context, pluginID string, p plugin_model.IPlugin, event *plugin_model.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	if err != nil {
+		return err
+	}
+	if !dbPlugin.HasPermission(plugin_model.PermissionEventRead) {
+		return plugin_model.ErrPermissionDenied{PluginID: pluginID, Permission: plugin_model.PermissionEventRead}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, eventHandleTimeout)
+	defer cancel()
+
+	if s, ok := p.(plugin_model.EventSubscriber); ok && plugin_model.IsSubscribed(s.SubscribedEvents(), event.Type) {
+		return s.HandleEvent(ctx, event)
+	}
+	if n, ok := p.(NotifierPlugin); ok {
+		return callNotifier(ctx, n.Notifier(), event)
+	}
+	return nil
+}
+
+func recordEvent(pluginID string, err error) {
+	if err == nil {
+		recordSuccess(pluginID)
+	} else {
+		recordFailure(pluginID, HealthSourceEvent, fmt.Errorf("handle event: %w", err))
+	}
+
+	eventStatsMu.Lock()
+	defer eventStatsMu.Unlock()
+	stats := eventStats[pluginID]
+	if stats == nil {
+		stats = &EventStats{}
+		eventStats[pluginID] = stats
+	}
+	if err == nil {
+		stats.Delivered++
+		return
+	}
+	stats.Failed++
+	stats.LastError = err.Error()
+	stats.LastErrorTime = timeutil.TimeStampNow()
+	log.Warn("Plugin %s failed to handle event: %v", pluginID, err)
+}
+
+// GetEventStats 获取所有插件的事件投递统计
+func GetEventStats() map[string]EventStats {
+	eventStatsMu.RLock()
+	defer eventStatsMu.RUnlock()
+	result := make(map[string]EventStats, len(eventStats))
+	for id, stats := range eventStats {
+		result[id] = *stats
+	}
+	return result
+}
@@ -0,0 +1,263 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	packages_model "code.gitea.io/gitea/models/packages"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repository"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// eventNotifier 将 notify 的调用转换为插件事件放入队列，不在调用方的请求中执行插件代码
+type eventNotifier struct {
+	notify_service.NullNotifier
+}
+
+var _ notify_service.Notifier = &eventNotifier{}
+
+func newRepoEvent(eventType string, doer *user_model.User, repo *repo_model.Repository) *plugin_model.Event {
+	event := &plugin_model.Event{Type: eventType}
+	if doer != nil {
+		event.DoerID, event.DoerName = doer.ID, doer.Name
+	}
+	if repo != nil {
+		event.RepoID, event.RepoOwner, event.RepoName, event.IsPrivate = repo.ID, repo.OwnerName, repo.Name, repo.IsPrivate
+	}
+	return event
+}
+
+func newIssueEvent(ctx context.Context, eventType string, doer *user_model.User, issue *issues_model.Issue) *plugin_model.Event {
+	_ = issue.LoadRepo(ctx)
+	event := newRepoEvent(eventType, doer, issue.Repo)
+	event.IssueID, event.IssueIndex, event.IsPull, event.IsClosed, event.Title = issue.ID, issue.Index, issue.IsPull, issue.IsClosed, issue.Title
+	return event
+}
+
+func (*eventNotifier) CreateRepository(_ context.Context, doer, _ *user_model.User, repo *repo_model.Repository) {
+	publishEvent(newRepoEvent(plugin_model.EventCreateRepository, doer, repo))
+}
+
+func (*eventNotifier) DeleteRepository(_ context.Context, doer *user_model.User, repo *repo_model.Repository) {
+	publishEvent(newRepoEvent(plugin_model.EventDeleteRepository, doer, repo))
+}
+
+func (*eventNotifier) NewIssue(ctx context.Context, issue *issues_model.Issue, _ []*user_model.User) {
+	_ = issue.LoadPoster(ctx)
+	publishEvent(newIssueEvent(ctx, plugin_model.EventNewIssue, issue.Poster, issue))
+}
+
+func (*eventNotifier) IssueChangeStatus(ctx context.Context, doer *user_model.User, _ string, issue *issues_model.Issue, _ *issues_model.Comment, _ bool) {
+	publishEvent(newIssueEvent(ctx, plugin_model.EventIssueChangeStatus, doer, issue))
+}
+
+func (*eventNotifier) CreateIssueComment(ctx context.Context, doer *user_model.User, _ *repo_model.Repository, issue *issues_model.Issue, comment *issues_model.Comment, _ []*user_model.User) {
+	event := newIssueEvent(ctx, plugin_model.EventCreateIssueComment, doer, issue)
+	event.CommentID = comment.ID
+	publishEvent(event)
+}
+
+func (*eventNotifier) NewPullRequest(ctx context.Context, pr *issues_model.PullRequest, _ []*user_model.User) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		return
+	}
+	_ = pr.Issue.LoadPoster(ctx)
+	event := newIssueEvent(ctx, plugin_model.EventNewPullRequest, pr.Issue.Poster, pr.Issue)
+	event.PullID = pr.ID
+	publishEvent(event)
+}
+
+func (*eventNotifier) MergePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		return
+	}
+	event := newIssueEvent(ctx, plugin_model.EventMergePullRequest, doer, pr.Issue)
+	event.PullID, event.NewCommitID = pr.ID, pr.MergedCommitID
+	publishEvent(event)
+}
+
+func (*eventNotifier) PushCommits(_ context.Context, pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	event := newRepoEvent(plugin_model.EventPushCommits, pusher, repo)
+	event.RefName, event.OldCommitID, event.NewCommitID = opts.RefFullName.String(), opts.OldCommitID, opts.NewCommitID
+	for _, c := range commits.Commits {
+		event.Commits = append(event.Commits, &plugin_model.EventCommit{
+			Sha1:           c.Sha1,
+			Message:        c.Message,
+			AuthorEmail:    c.AuthorEmail,
+			AuthorName:     c.AuthorName,
+			CommitterEmail: c.CommitterEmail,
+			CommitterName:  c.CommitterName,
+			Timestamp:      c.Timestamp,
+		})
+	}
+	publishEvent(event)
+}
+
+func (*eventNotifier) CreateRef(_ context.Context, doer *user_model.User, repo *repo_model.Repository, refFullName git.RefName, refID string) {
+	event := newRepoEvent(plugin_model.EventCreateRef, doer, repo)
+	event.RefName, event.NewCommitID = refFullName.String(), refID
+	publishEvent(event)
+}
+
+func (*eventNotifier) DeleteRef(_ context.Context, doer *user_model.User, repo *repo_model.Repository, refFullName git.RefName) {
+	event := newRepoEvent(plugin_model.EventDeleteRef, doer, repo)
+	event.RefName = refFullName.String()
+	publishEvent(event)
+}
+
+func (*eventNotifier) NewRelease(ctx context.Context, rel *repo_model.Release) {
+	if err := rel.LoadAttributes(ctx); err != nil {
+		return
+	}
+	event := newRepoEvent(plugin_model.EventNewRelease, rel.Publisher, rel.Repo)
+	event.ReleaseID, event.TagName, event.Title = rel.ID, rel.TagName, rel.Title
+	publishEvent(event)
+}
+
+func newPackageEvent(eventType string, doer *user_model.User, pd *packages_model.PackageDescriptor) *plugin_model.Event {
+	event := newRepoEvent(eventType, doer, pd.Repository)
+	event.PackageVerID, event.PackageType, event.PackageName, event.PackageVer = pd.Version.ID, string(pd.Package.Type), pd.Package.Name, pd.Version.Version
+	if pd.Owner != nil {
+		event.PackageOwner = pd.Owner.Name
+	}
+	return event
+}
+
+func (*eventNotifier) PackageCreate(_ context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) {
+	publishEvent(newPackageEvent(plugin_model.EventPackageCreate, doer, pd))
+}
+
+func (*eventNotifier) PackageDelete(_ context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) {
+	publishEvent(newPackageEvent(plugin_model.EventPackageDelete, doer, pd))
+}
+
+// callNotifier 按事件中的 ID 重新加载模型并调用插件提供的 Notifier。
+// 已删除的对象（仓库、软件包）使用事件中的快照字段构造
+func callNotifier(ctx context.Context, n notify_service.Notifier, event *plugin_model.Event) error {
+	doer, err := user_model.GetPossibleUserByID(ctx, event.DoerID)
+	if err != nil {
+		if !user_model.IsErrUserNotExist(err) {
+			return err
+		}
+		doer = user_model.NewGhostUser()
+	}
+
+	switch event.Type {
+	case plugin_model.EventDeleteRepository:
+		n.DeleteRepository(ctx, doer, &repo_model.Repository{ID: event.RepoID, OwnerName: event.RepoOwner, Name: event.RepoName, IsPrivate: event.IsPrivate})
+		return nil
+	case plugin_model.EventPackageDelete:
+		n.PackageDelete(ctx, doer, &packages_model.PackageDescriptor{
+			Package: &packages_model.Package{Type: packages_model.Type(event.PackageType), Name: event.PackageName},
+			Version: &packages_model.PackageVersion{ID: event.PackageVerID, Version: event.PackageVer},
+			Owner:   &user_model.User{Name: event.PackageOwner},
+		})
+		return nil
+	case plugin_model.EventPackageCreate:
+		pv, err := packages_model.GetVersionByID(ctx, event.PackageVerID)
+		if err != nil {
+			return err
+		}
+		pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+		if err != nil {
+			return err
+		}
+		n.PackageCreate(ctx, doer, pd)
+		return nil
+	}
+
+	repo, err := repo_model.GetRepositoryByID(ctx, event.RepoID)
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case plugin_model.EventCreateRepository:
+		if err := repo.LoadOwner(ctx); err != nil {
+			return err
+		}
+		n.CreateRepository(ctx, doer, repo.Owner, repo)
+	case plugin_model.EventNewIssue, plugin_model.EventIssueChangeStatus, plugin_model.EventCreateIssueComment:
+		issue, err := issues_model.GetIssueByID(ctx, event.IssueID)
+		if err != nil {
+			return err
+		}
+		issue.Repo = repo
+		switch event.Type {
+		case plugin_model.EventNewIssue:
+			n.NewIssue(ctx, issue, nil)
+		case plugin_model.EventIssueChangeStatus:
+			n.IssueChangeStatus(ctx, doer, "", issue, nil, event.IsClosed)
+		default:
+			comment, err := issues_model.GetCommentByID(ctx, event.CommentID)
+			if err != nil {
+				return err
+			}
+			n.CreateIssueComment(ctx, doer, repo, issue, comment, nil)
+		}
+	case plugin_model.EventNewPullRequest, plugin_model.EventMergePullRequest:
+		pr, err := issues_model.GetPullRequestByID(ctx, event.PullID)
+		if err != nil {
+			return err
+		}
+		if err := pr.LoadIssue(ctx); err != nil {
+			return err
+		}
+		pr.Issue.Repo = repo
+		if event.Type == plugin_model.EventNewPullRequest {
+			n.NewPullRequest(ctx, pr, nil)
+		} else {
+			n.MergePullRequest(ctx, doer, pr)
+		}
+	case plugin_model.EventPushCommits:
+		commits := repository.NewPushCommits()
+		for _, c := range event.Commits {
+			commits.Commits = append(commits.Commits, &repository.PushCommit{
+				Sha1:           c.Sha1,
+				Message:        c.Message,
+				AuthorEmail:    c.AuthorEmail,
+				AuthorName:     c.AuthorName,
+				CommitterEmail: c.CommitterEmail,
+				CommitterName:  c.CommitterName,
+				Timestamp:      c.Timestamp,
+			})
+		}
+		if len(commits.Commits) > 0 {
+			commits.HeadCommit = commits.Commits[0]
+		}
+		commits.Len = len(commits.Commits)
+		n.PushCommits(ctx, doer, repo, &repository.PushUpdateOptions{
+			PusherID:     doer.ID,
+			PusherName:   doer.Name,
+			RepoUserName: repo.OwnerName,
+			RepoName:     repo.Name,
+			RefFullName:  git.RefName(event.RefName),
+			OldCommitID:  event.OldCommitID,
+			NewCommitID:  event.NewCommitID,
+		}, commits)
+	case plugin_model.EventCreateRef:
+		n.CreateRef(ctx, doer, repo, git.RefName(event.RefName), event.NewCommitID)
+	case plugin_model.EventDeleteRef:
+		n.DeleteRef(ctx, doer, repo, git.RefName(event.RefName))
+	case plugin_model.EventNewRelease:
+		rel, err := repo_model.GetReleaseByID(ctx, event.ReleaseID)
+		if err != nil {
+			return err
+		}
+		if err := rel.LoadAttributes(ctx); err != nil {
+			return err
+		}
+		n.NewRelease(ctx, rel)
+	default:
+		return fmt.Errorf("unsupported event type: %s", event.Type)
+	}
+	return nil
+}
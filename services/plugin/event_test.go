@@ -0,0 +1,23 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordEvent(t *testing.T) {
+	recordEvent("event-test", nil)
+	recordEvent("event-test", nil)
+	recordEvent("event-test", errors.New("boom"))
+
+	stats := GetEventStats()["event-test"]
+	assert.EqualValues(t, 2, stats.Delivered)
+	assert.EqualValues(t, 1, stats.Failed)
+	assert.Equal(t, "boom", stats.LastError)
+	assert.NotZero(t, stats.LastErrorTime)
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	system_model "code.gitea.io/gitea/models/system"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/cron"
+)
+
+// 插件失败的来源
+const (
+	HealthSourceInit    = "init"
+	HealthSourceHTTP    = "http"
+	HealthSourceEvent   = "event"
+	HealthSourceSlot    = "slot"
+	HealthSourceCron    = "cron"
+	HealthSourceCheck   = "health_check"
+	HealthSourceProcess = "process"
+)
+
+const (
+	healthMaxErrors    = 5 // 每个插件保留的最近错误数
+	healthCheckTimeout = 10 * time.Second
+)
+
+// HealthError 插件的一次失败
+type HealthError struct {
+	Source  string
+	Message string
+	Time    timeutil.TimeStamp
+}
+
+// PluginHealth 插件的运行状况，进程重启后清零
+type PluginHealth struct {
+	StartedUnix      timeutil.TimeStamp // 最近一次启动（加载、升级替换或进程重启）的时间
+	Calls            int64
+	Failures         int64
+	Errors           []HealthError // 最近的错误，最新的在前
+	Quarantined      bool
+	QuarantineReason string
+}
+
+// ErrorRate 失败调用所占的百分比
+func (h PluginHealth) ErrorRate() float64 {
+	if h.Calls == 0 {
+		return 0
+	}
+	return float64(h.Failures) * 100 / float64(h.Calls)
+}
+
+// healthState 在 PluginHealth 之外记录隔离窗口内的失败时间
+type healthState struct {
+	PluginHealth
+	recent []time.Time
+}
+
+var (
+	healthMu     sync.Mutex
+	healthStates = make(map[string]*healthState)
+)
+
+func getHealthState(pluginID string) *healthState {
+	s := healthStates[pluginID]
+	if s == nil {
+		s = &healthState{}
+		healthStates[pluginID] = s
+	}
+	return s
+}
+
+// succeed 记录一次成功的调用
+func (s *healthState) succeed() {
+	s.Calls++
+}
+
+// fail 记录一次失败，返回是否应隔离插件：window 内的失败次数达到 threshold 且尚未隔离
+func (s *healthState) fail(now time.Time, source string, err error, threshold int, window time.Duration) bool {
+	s.Calls++
+	s.Failures++
+	s.Errors = slices.Insert(s.Errors, 0, HealthError{
+		Source:  source,
+		Message: err.Error(),
+		Time:    timeutil.TimeStamp(now.Unix()),
+	})
+	if len(s.Errors) > healthMaxErrors {
+		s.Errors = s.Errors[:healthMaxErrors]
+	}
+
+	s.recent = slices.DeleteFunc(s.recent, func(t time.Time) bool { return now.Sub(t) > window })
+	s.recent = append(s.recent, now)
+	if threshold <= 0 || s.Quarantined || len(s.recent) < threshold {
+		return false
+	}
+	s.Quarantined = true
+	s.QuarantineReason = fmt.Sprintf("%d failures within %s, last %s error: %v", len(s.recent), window, source, err)
+	return true
+}
+
+// recordStart 记录插件启动，隔离窗口重新计算
+func recordStart(pluginID string) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	s := getHealthState(pluginID)
+	s.StartedUnix = timeutil.TimeStampNow()
+	s.recent = nil
+}
+
+// recordSuccess 记录插件一次成功的调用
+func recordSuccess(pluginID string) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	getHealthState(pluginID).succeed()
+}
+
+// recordFailure 记录插件一次失败，失败过多的已启用插件会被隔离
+func recordFailure(pluginID, source string, err error) {
+	threshold := setting.PluginQuarantineFailures
+	if !GetRouter().IsEnabled(pluginID) {
+		threshold = 0 // 未启用的插件（如初始化失败）只保留错误记录
+	}
+
+	healthMu.Lock()
+	s := getHealthState(pluginID)
+	quarantine := s.fail(time.Now(), source, err, threshold, setting.PluginQuarantineWindow)
+	reason := s.QuarantineReason
+	healthMu.Unlock()
+
+	if quarantine {
+		// 失败可能发生在插件的请求处理中，禁用插件需在请求之外进行
+		go quarantinePlugin(pluginID, reason)
+	}
+}
+
+// resetHealth 管理员重新启用插件时清除隔离状态
+func resetHealth(pluginID string) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	if s := healthStates[pluginID]; s != nil {
+		s.Quarantined = false
+		s.QuarantineReason = ""
+		s.recent = nil
+	}
+}
+
+// removeHealth 插件卸载后清除运行状况
+func removeHealth(pluginID string) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	delete(healthStates, pluginID)
+}
+
+// GetHealth 获取所有插件的运行状况
+func GetHealth() map[string]PluginHealth {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	result := make(map[string]PluginHealth, len(healthStates))
+	for id, s := range healthStates {
+		h := s.PluginHealth
+		h.Errors = slices.Clone(s.Errors)
+		result[id] = h
+	}
+	return result
+}
+
+// quarantinePlugin 自动禁用失败过多的插件，并通知管理员
+func quarantinePlugin(pluginID, reason string) {
+	ctx := graceful.GetManager().ShutdownContext()
+	log.Error("Plugin %s is quarantined: %s", pluginID, reason)
+
+	desc := fmt.Sprintf("Plugin %s has been disabled automatically: %s", pluginID, reason)
+	if m := GetManager(); m != nil {
+		// 依赖此插件的插件保持启用，但相关功能可能不可用
+		if nodes, err := m.loader.loadNodes(ctx); err == nil {
+			if dependents := dependentsOf(pluginID, nodes, true); len(dependents) > 0 {
+				desc += fmt.Sprintf(". Enabled plugins depending on it: %s", strings.Join(dependents, ", "))
+			}
+		}
+		if dbPlugin, err := m.disable(ctx, pluginID, true); err != nil {
+			log.Error("Failed to disable quarantined plugin %s: %v", pluginID, err)
+		} else {
+			writeAudit(ctx, nil, &plugin_model.Audit{
+				PluginID:   pluginID,
+				Action:     plugin_model.AuditActionQuarantine,
+				OldVersion: dbPlugin.Version,
+				Detail:     reason,
+			})
+		}
+	} else {
+		GetRouter().SetEnabled(ctx, pluginID, false)
+	}
+
+	if err := system_model.CreateNotice(ctx, system_model.NoticePlugin, desc); err != nil {
+		log.Error("CreateNotice: %v", err)
+	}
+}
+
+// CheckHealth 调用所有已启用插件的 HealthCheck，失败计入插件的错误率
+func CheckHealth(ctx context.Context) {
+	for id, p := range GetLoader().GetAllPlugins() {
+		checker, ok := p.(plugin_model.HealthChecker)
+		if !ok || !GetRouter().IsEnabled(id) {
+			continue
+		}
+		if err := checkPluginHealth(ctx, checker); err != nil {
+			log.Warn("Plugin %s health check failed: %v", id, err)
+			recordFailure(id, HealthSourceCheck, err)
+		} else {
+			recordSuccess(id)
+		}
+	}
+}
+
+func checkPluginHealth(ctx context.Context, checker plugin_model.HealthChecker) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return callPlugin(func() error { return checker.HealthCheck(ctx) })
+}
+
+// callPlugin 调用插件方法，插件 panic 时转换为错误
+func callPlugin(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			log.Error("Plugin panicked: %v\n%s", r, log.Stack(2))
+		}
+	}()
+	return fn()
+}
+
+// registerHealthCheckTask 注册定期健康检查的定时任务，可在 [cron.plugin_health_check] 中配置
+func registerHealthCheckTask() {
+	if err := cron.RegisterTask("plugin_health_check", &cron.BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1m",
+	}, func(ctx context.Context, _ *user_model.User, _ cron.Config) error {
+		CheckHealth(ctx)
+		return nil
+	}); err != nil {
+		log.Error("Unable to register plugin health check task: %v", err)
+	}
+}
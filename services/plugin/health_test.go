@@ -0,0 +1,98 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthStateFail(t *testing.T) {
+	s := &healthState{}
+	now := time.Now()
+	errFailed := errors.New("failed")
+
+	assert.False(t, s.fail(now, HealthSourceHTTP, errFailed, 3, time.Minute))
+	assert.False(t, s.fail(now.Add(30*time.Second), HealthSourceHTTP, errFailed, 3, time.Minute))
+	// the first failure falls out of the window
+	assert.False(t, s.fail(now.Add(2*time.Minute), HealthSourceEvent, errFailed, 3, time.Minute))
+	assert.False(t, s.fail(now.Add(2*time.Minute+10*time.Second), HealthSourceEvent, errFailed, 3, time.Minute))
+	assert.True(t, s.fail(now.Add(2*time.Minute+20*time.Second), HealthSourceSlot, errFailed, 3, time.Minute))
+	assert.True(t, s.Quarantined)
+	assert.Contains(t, s.QuarantineReason, "3 failures")
+	// already quarantined
+	assert.False(t, s.fail(now.Add(2*time.Minute+30*time.Second), HealthSourceSlot, errFailed, 3, time.Minute))
+
+	s.succeed()
+	assert.EqualValues(t, 7, s.Calls)
+	assert.EqualValues(t, 6, s.Failures)
+	assert.InDelta(t, 85.7, s.ErrorRate(), 0.1)
+	require.Len(t, s.Errors, healthMaxErrors)
+	assert.Equal(t, HealthSourceSlot, s.Errors[0].Source)
+
+	// quarantine disabled
+	s = &healthState{}
+	for range 10 {
+		assert.False(t, s.fail(now, HealthSourceHTTP, errFailed, 0, time.Minute))
+	}
+}
+
+func TestCallPlugin(t *testing.T) {
+	assert.NoError(t, callPlugin(func() error { return nil }))
+	assert.EqualError(t, callPlugin(func() error { return errors.New("failed") }), "failed")
+	assert.EqualError(t, callPlugin(func() error { panic("boom") }), "panic: boom")
+}
+
+type panicTestPlugin struct {
+	plugin_model.IPlugin
+}
+
+func (p *panicTestPlugin) Info() *plugin_model.PluginInfo {
+	return &plugin_model.PluginInfo{ID: "panic-test"}
+}
+
+func (p *panicTestPlugin) RegisterRoutes(r chi.Router) {
+	r.Get("/panic-plugin/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	r.Get("/panic-plugin/error", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+	r.Get("/panic-plugin/ok", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "ok")
+	})
+}
+
+func (p *panicTestPlugin) RegisterAPIRoutes(r chi.Router) {}
+
+func TestPluginRouterRecover(t *testing.T) {
+	const pluginID = "panic-test"
+	defer removeHealth(pluginID)
+
+	r := &PluginRouter{mounts: make(map[string]*pluginMount)}
+	web := r.WebHandler(nil, http.NotFoundHandler())
+	r.Mount(t.Context(), pluginID, &panicTestPlugin{}, true)
+
+	assert.Equal(t, http.StatusInternalServerError, serveRouter(web, "/panic-plugin/panic").Code)
+	assert.Equal(t, http.StatusBadGateway, serveRouter(web, "/panic-plugin/error").Code)
+	assert.Equal(t, "ok", serveRouter(web, "/panic-plugin/ok").Body.String())
+
+	health := GetHealth()[pluginID]
+	assert.EqualValues(t, 3, health.Calls)
+	assert.EqualValues(t, 2, health.Failures)
+	require.Len(t, health.Errors, 2)
+	assert.Equal(t, HealthSourceHTTP, health.Errors[0].Source)
+	assert.Contains(t, health.Errors[0].Message, "status 502")
+	assert.Contains(t, health.Errors[1].Message, "panic")
+	assert.False(t, health.Quarantined)
+}
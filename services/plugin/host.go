@@ -0,0 +1,423 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	perm_model "code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/mailer"
+	sender_service "code.gitea.io/gitea/services/mailer/sender"
+
+	"xorm.io/builder"
+)
+
+const hostHTTPTimeout = 30 * time.Second
+
+// pluginHost 交给插件的宿主能力实现，每次调用时读取最新批准的权限，撤销权限立即生效
+type pluginHost struct {
+	pluginID string
+}
+
+func newPluginHost(pluginID string) *pluginHost {
+	return &pluginHost{pluginID: pluginID}
+}
+
+// require 检查管理员是否批准了指定权限
+func (h *pluginHost) require(ctx context.Context, permissions ...string) error {
+	p, err := plugin_model.GetPluginByID(ctx, h.pluginID)
+	if err != nil {
+		return err
+	}
+	for _, permission := range permissions {
+		if p.HasPermission(permission) {
+			return nil
+		}
+	}
+	return plugin_model.ErrPermissionDenied{PluginID: h.pluginID, Permission: permissions[0]}
+}
+
+// PluginID 当前插件 ID
+func (h *pluginHost) PluginID() string {
+	return h.pluginID
+}
+
+// DB 只能访问插件自身数据表的数据库会话
+func (h *pluginHost) DB() plugin_model.DB {
+	return &scopedDB{host: h, prefix: plugin_model.TablePrefix(h.pluginID)}
+}
+
+// GetUserByID 根据 ID 查询用户
+func (h *pluginHost) GetUserByID(ctx context.Context, id int64) (*plugin_model.HostUser, error) {
+	if err := h.require(ctx, plugin_model.PermissionUserRead); err != nil {
+		return nil, err
+	}
+	u, err := user_model.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ToHostUser(u), nil
+}
+
+// GetUserByName 根据用户名查询用户
+func (h *pluginHost) GetUserByName(ctx context.Context, name string) (*plugin_model.HostUser, error) {
+	if err := h.require(ctx, plugin_model.PermissionUserRead); err != nil {
+		return nil, err
+	}
+	u, err := user_model.GetUserByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return ToHostUser(u), nil
+}
+
+// ToHostUser 转换为提供给插件的用户信息
+func ToHostUser(u *user_model.User) *plugin_model.HostUser {
+	return &plugin_model.HostUser{
+		ID:       u.ID,
+		Name:     u.Name,
+		FullName: u.FullName,
+		Email:    u.Email,
+		IsAdmin:  u.IsAdmin,
+		IsActive: u.IsActive,
+	}
+}
+
+// SendNotification 向用户发送通知邮件
+func (h *pluginHost) SendNotification(ctx context.Context, userID int64, subject, body string) error {
+	if err := h.require(ctx, plugin_model.PermissionNotificationSend); err != nil {
+		return err
+	}
+	if setting.MailService == nil {
+		return fmt.Errorf("mail service is not enabled")
+	}
+	u, err := user_model.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.Email == "" || !u.IsActive {
+		return fmt.Errorf("user %d cannot receive notifications", userID)
+	}
+	mailer.SendAsync(sender_service.NewMessage(u.Email, subject, body))
+	return nil
+}
+
+// Settings 读取实例的基本设置
+func (h *pluginHost) Settings(ctx context.Context) (*plugin_model.HostSettings, error) {
+	if err := h.require(ctx, plugin_model.PermissionSettingRead); err != nil {
+		return nil, err
+	}
+	return &plugin_model.HostSettings{
+		AppName:   setting.AppName,
+		AppURL:    setting.AppURL,
+		AppSubURL: setting.AppSubURL,
+		Version:   setting.AppVer,
+	}, nil
+}
+
+// HTTPClient 用于访问外部网络的 HTTP 客户端，只能访问管理员允许的主机
+func (h *pluginHost) HTTPClient(ctx context.Context) (*http.Client, error) {
+	if err := h.require(ctx, plugin_model.PermissionHTTPEgress); err != nil {
+		return nil, err
+	}
+	return getHostHTTPClient(), nil
+}
+
+// DataDir 插件专属的数据目录
+func (h *pluginHost) DataDir(ctx context.Context) (string, error) {
+	if err := h.require(ctx, plugin_model.PermissionStorage); err != nil {
+		return "", err
+	}
+	dir := pluginDataDir(h.pluginID)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Storage 插件专属的对象存储命名空间
+func (h *pluginHost) Storage(ctx context.Context) (plugin_model.Storage, error) {
+	if err := h.require(ctx, plugin_model.PermissionStorage); err != nil {
+		return nil, err
+	}
+	return &pluginStorage{host: h}, nil
+}
+
+// GetRepository 查询用户可以访问的仓库，无权访问时与仓库不存在返回相同的错误，避免泄露私有仓库
+func (h *pluginHost) GetRepository(ctx context.Context, doerID int64, ownerName, repoName string) (*plugin_model.HostRepo, error) {
+	if err := h.require(ctx, plugin_model.PermissionRepoRead); err != nil {
+		return nil, err
+	}
+	notExist := util.NewNotExistErrorf("repository %s/%s does not exist", ownerName, repoName)
+
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+	if repo_model.IsErrRepoNotExist(err) {
+		return nil, notExist
+	} else if err != nil {
+		return nil, err
+	}
+
+	var doer *user_model.User
+	if doerID > 0 {
+		if doer, err = user_model.GetUserByID(ctx, doerID); err != nil {
+			return nil, err
+		}
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, doer)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.HasAnyUnitAccessOrPublicAccess() {
+		return nil, notExist
+	}
+
+	return &plugin_model.HostRepo{
+		ID:            repo.ID,
+		OwnerName:     repo.OwnerName,
+		Name:          repo.Name,
+		Description:   repo.Description,
+		IsPrivate:     repo.IsPrivate,
+		IsArchived:    repo.IsArchived,
+		DefaultBranch: repo.DefaultBranch,
+		HTMLURL:       repo.HTMLURL(ctx),
+		AccessMode:    max(perm.AccessMode, perm_model.AccessModeRead).ToString(),
+	}, nil
+}
+
+// scopedDB 限定在插件自身数据表的数据库操作
+type scopedDB struct {
+	host   *pluginHost
+	prefix string
+}
+
+// checkTable 检查 bean（或切片指针的元素）对应的表是否属于插件
+func (s *scopedDB) checkTable(bean any) error {
+	v := reflect.ValueOf(bean)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice {
+		t := v.Type().Elem()
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		bean = reflect.New(t).Interface()
+	}
+
+	tableName := db.TableName(bean)
+	if !plugin_model.OwnsTable(s.host.pluginID, tableName, nil) {
+		return plugin_model.ErrPermissionDenied{
+			PluginID:   s.host.pluginID,
+			Permission: fmt.Sprintf("table %s (plugin tables must start with %s)", tableName, s.prefix),
+		}
+	}
+	return nil
+}
+
+// checkCond 检查查询条件只由作用于列名的简单比较组成。原始 SQL 表达式（builder.Expr）、
+// 子查询和 EXISTS 可以引用插件以外的表，一律拒绝
+func (s *scopedDB) checkCond(cond builder.Cond) error {
+	if cond == nil {
+		return nil
+	}
+	denied := func(reason string) error {
+		return plugin_model.ErrPermissionDenied{PluginID: s.host.pluginID, Permission: "query condition: " + reason}
+	}
+	checkColumn := func(col string) error {
+		if !condColumnPattern.MatchString(col) {
+			return denied(fmt.Sprintf("invalid column %q", col))
+		}
+		return nil
+	}
+	checkValue := func(t reflect.Type) error {
+		if t != nil && (t == builderType || t.Implements(condType)) {
+			return denied("sub-queries and expressions are not allowed")
+		}
+		return nil
+	}
+	checkMap := func(m map[string]any) error {
+		for col, value := range m {
+			if err := checkColumn(col); err != nil {
+				return err
+			}
+			if err := checkValue(reflect.TypeOf(value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch c := cond.(type) {
+	case builder.Eq:
+		return checkMap(c)
+	case builder.Neq:
+		return checkMap(c)
+	case builder.Lt:
+		return checkMap(c)
+	case builder.Lte:
+		return checkMap(c)
+	case builder.Gt:
+		return checkMap(c)
+	case builder.Gte:
+		return checkMap(c)
+	case builder.Like:
+		return checkColumn(c[0])
+	case builder.IsNull:
+		return checkColumn(c[0])
+	case builder.NotNull:
+		return checkColumn(c[0])
+	case builder.Between:
+		if err := checkColumn(c.Col); err != nil {
+			return err
+		}
+		if err := checkValue(reflect.TypeOf(c.LessVal)); err != nil {
+			return err
+		}
+		return checkValue(reflect.TypeOf(c.MoreVal))
+	case builder.Not:
+		return s.checkCond(c[0])
+	}
+
+	// And、Or、In、NotIn 和空条件的类型未导出，通过反射检查
+	v := reflect.ValueOf(cond)
+	switch v.Type() {
+	case condEmptyType:
+		return nil
+	case condAndType, condOrType:
+		for i := range v.Len() {
+			c, _ := v.Index(i).Interface().(builder.Cond)
+			if err := s.checkCond(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	case condInType, condNotInType:
+		if err := checkColumn(v.Field(0).String()); err != nil {
+			return err
+		}
+		values := v.Field(1)
+		for i := range values.Len() {
+			if value := values.Index(i); !value.IsNil() {
+				if err := checkValue(value.Elem().Type()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return denied(fmt.Sprintf("unsupported condition %T", cond))
+}
+
+var (
+	condColumnPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	condType      = reflect.TypeFor[builder.Cond]()
+	builderType   = reflect.TypeFor[*builder.Builder]()
+	condEmptyType = reflect.TypeOf(builder.NewCond())
+	condAndType   = reflect.TypeOf(builder.And())
+	condOrType    = reflect.TypeOf(builder.Or())
+	condInType    = reflect.TypeOf(builder.In(""))
+	condNotInType = reflect.TypeOf(builder.NotIn(""))
+)
+
+func (s *scopedDB) canRead(ctx context.Context, bean any) error {
+	if err := s.host.require(ctx, plugin_model.PermissionDatabaseRead, plugin_model.PermissionDatabaseWrite); err != nil {
+		return err
+	}
+	return s.checkTable(bean)
+}
+
+func (s *scopedDB) canWrite(ctx context.Context, beans ...any) error {
+	if err := s.host.require(ctx, plugin_model.PermissionDatabaseWrite); err != nil {
+		return err
+	}
+	for _, bean := range beans {
+		if err := s.checkTable(bean); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get 按 bean 的非零字段查询一条记录
+func (s *scopedDB) Get(ctx context.Context, bean any) (bool, error) {
+	if err := s.canRead(ctx, bean); err != nil {
+		return false, err
+	}
+	return db.GetEngine(ctx).Get(bean)
+}
+
+// Find 按条件查询记录
+func (s *scopedDB) Find(ctx context.Context, rowsSlicePtr any, cond builder.Cond) error {
+	if err := s.canRead(ctx, rowsSlicePtr); err != nil {
+		return err
+	}
+	if err := s.checkCond(cond); err != nil {
+		return err
+	}
+	return db.GetEngine(ctx).Where(cond).Find(rowsSlicePtr)
+}
+
+// Count 按条件统计记录数
+func (s *scopedDB) Count(ctx context.Context, bean any, cond builder.Cond) (int64, error) {
+	if err := s.canRead(ctx, bean); err != nil {
+		return 0, err
+	}
+	if err := s.checkCond(cond); err != nil {
+		return 0, err
+	}
+	return db.GetEngine(ctx).Where(cond).Count(bean)
+}
+
+// Insert 插入记录
+func (s *scopedDB) Insert(ctx context.Context, beans ...any) error {
+	if err := s.canWrite(ctx, beans...); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Insert(beans...)
+	return err
+}
+
+// Update 按条件更新记录的所有字段
+func (s *scopedDB) Update(ctx context.Context, bean any, cond builder.Cond) (int64, error) {
+	if err := s.canWrite(ctx, bean); err != nil {
+		return 0, err
+	}
+	if err := s.checkWriteCond(cond); err != nil {
+		return 0, err
+	}
+	return db.GetEngine(ctx).Where(cond).AllCols().Update(bean)
+}
+
+// Delete 按条件删除记录，bean 用于确定数据表，其非零字段同样作为条件
+func (s *scopedDB) Delete(ctx context.Context, bean any, cond builder.Cond) (int64, error) {
+	if err := s.canWrite(ctx, bean); err != nil {
+		return 0, err
+	}
+	if err := s.checkWriteCond(cond); err != nil {
+		return 0, err
+	}
+	return db.GetEngine(ctx).Where(cond).Delete(bean)
+}
+
+// checkWriteCond 更新和删除必须给出条件，避免空条件改写整张表
+func (s *scopedDB) checkWriteCond(cond builder.Cond) error {
+	if cond == nil || !cond.IsValid() {
+		return plugin_model.ErrPermissionDenied{PluginID: s.host.pluginID, Permission: "query condition: update and delete require a condition"}
+	}
+	return s.checkCond(cond)
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+type hostTestItem struct {
+	ID   int64 `xorm:"pk autoincr"`
+	Name string
+}
+
+func (hostTestItem) TableName() string {
+	return "plugin_host_test_item"
+}
+
+func TestScopedDB(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	const pluginID = "host-test"
+	require.NoError(t, plugin_model.CreatePlugin(t.Context(), &plugin_model.Plugin{
+		PluginID:    pluginID,
+		Name:        pluginID,
+		Version:     "1.0.0",
+		IsInstalled: true,
+		Permissions: []string{plugin_model.PermissionDatabaseWrite},
+	}))
+	require.NoError(t, db.GetEngine(t.Context()).Sync(new(hostTestItem)))
+	defer func() { _ = db.GetXORMEngine().DropTables(new(hostTestItem)) }()
+
+	s := newPluginHost(pluginID).DB()
+	require.NoError(t, s.Insert(t.Context(), &hostTestItem{Name: "a"}, &hostTestItem{Name: "b"}))
+
+	var items []*hostTestItem
+	require.NoError(t, s.Find(t.Context(), &items, builder.Eq{"name": "a"}.Or(builder.In("id", []int64{2}), builder.Not{builder.IsNull{"name"}})))
+	assert.Len(t, items, 2)
+	count, err := s.Count(t.Context(), new(hostTestItem), builder.Like{"name", "a"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	updated, err := s.Update(t.Context(), &hostTestItem{Name: "c"}, builder.Between{Col: "id", LessVal: 2, MoreVal: 3})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, updated)
+
+	// update and delete without a condition would rewrite the whole table
+	for _, cond := range []builder.Cond{nil, builder.NewCond()} {
+		_, err = s.Update(t.Context(), &hostTestItem{Name: "d"}, cond)
+		assert.True(t, plugin_model.IsErrPermissionDenied(err), "%v", err)
+		_, err = s.Delete(t.Context(), new(hostTestItem), cond)
+		assert.True(t, plugin_model.IsErrPermissionDenied(err), "%v", err)
+	}
+	count, err = s.Count(t.Context(), new(hostTestItem), builder.NotNull{"name"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	deleted, err := s.Delete(t.Context(), new(hostTestItem), builder.Eq{"name": "c"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+	require.NoError(t, s.Insert(t.Context(), &hostTestItem{Name: "b"}))
+
+	// core tables must not be reachable through query conditions
+	for name, cond := range map[string]builder.Cond{
+		"expr":         builder.Expr("id IN (SELECT id FROM `user`)"),
+		"in subquery":  builder.In("id", builder.Select("id").From("user")),
+		"eq subquery":  builder.Eq{"id": builder.Select("id").From("user").Where(builder.Eq{"is_admin": true})},
+		"exists":       builder.Exists(builder.Select("id").From("user")),
+		"column":       builder.Eq{"id IN (SELECT id FROM `user`) OR 1": 1},
+		"like column":  builder.Like{"(SELECT passwd FROM `user` LIMIT 1)", "a"},
+		"nested":       builder.And(builder.Eq{"name": "a"}, builder.Or(builder.Expr("1=1"))),
+		"not subquery": builder.Not{builder.In("id", builder.Select("id").From("user"))},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := s.Find(t.Context(), &items, cond)
+			assert.True(t, plugin_model.IsErrPermissionDenied(err), "%v", err)
+			_, err = s.Count(t.Context(), new(hostTestItem), cond)
+			assert.True(t, plugin_model.IsErrPermissionDenied(err), "%v", err)
+			_, err = s.Update(t.Context(), &hostTestItem{Name: "d"}, cond)
+			assert.True(t, plugin_model.IsErrPermissionDenied(err), "%v", err)
+			_, err = s.Delete(t.Context(), new(hostTestItem), cond)
+			assert.True(t, plugin_model.IsErrPermissionDenied(err), "%v", err)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -22,6 +23,7 @@ import (
 type PluginLoader struct {
 	pluginsDir string
 	plugins    map[string]plugin_model.IPlugin
+	loading    map[string]bool // 正在打开的插件，防止同一插件被并发加载
 	mu         sync.RWMutex
 }
 
@@ -35,7 +37,8 @@ func GetLoader() *PluginLoader {
 	loaderOnce.Do(func() {
 		globalLoader = &PluginLoader{
 			pluginsDir: "./plugins",
-			plugins:    make(map[string]plugin.IPlugin),
+			plugins:    make(map[string]plugin_model.IPlugin),
+			loading:    make(map[string]bool),
 		}
 	})
 	return globalLoader
@@ -48,21 +51,48 @@ func (l *PluginLoader) SetPluginsDir(dir string) {
 	l.pluginsDir = dir
 }
 
-// LoadAll 加载所有已安装的插件
+// LoadAll 按依赖关系的拓扑顺序加载所有已安装的插件
 func (l *PluginLoader) LoadAll(ctx context.Context) error {
-	// 从数据库获取已安装的插件列表
 	plugins, err := plugin_model.ListPlugins(ctx)
 	if err != nil {
 		return fmt.Errorf("list plugins from database: %w", err)
 	}
-
 	for _, p := range plugins {
-		if !p.IsInstalled {
+		if err := l.migrateLegacyLayout(ctx, p); err != nil {
+			log.Error("Failed to migrate plugin %s to versioned directory: %v", p.PluginID, err)
+		}
+	}
+
+	nodes, err := l.loadNodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	order, cyclic := loadOrder(nodes)
+	for _, id := range cyclic {
+		log.Error("Failed to load plugin %s: circular dependency", id)
+	}
+
+	for _, id := range order {
+		node := nodes[id]
+		if node.Metadata != nil {
+			if err := checkGiteaVersion(node.Metadata); err != nil {
+				log.Error("Failed to load plugin %s: %v", id, err)
+				continue
+			}
+		}
+		if err := checkNodeDependencies(id, node, nodes, false); err != nil {
+			log.Error("Failed to load plugin %s: %v", id, err)
+			continue
+		}
+		if dep := l.firstUnloaded(node.Deps); dep != "" {
+			log.Error("Failed to load plugin %s: dependency %s is not loaded", id, dep)
 			continue
 		}
 
-		if err := l.LoadPlugin(ctx, p.PluginID); err != nil {
-			log.Error("Failed to load plugin %s: %v", p.PluginID, err)
+		if err := l.LoadPlugin(ctx, id); err != nil {
+			log.Error("Failed to load plugin %s: %v", id, err)
+			recordFailure(id, HealthSourceInit, err)
 			continue
 		}
 	}
@@ -71,92 +101,209 @@ func (l *PluginLoader) LoadAll(ctx context.Context) error {
 	return nil
 }
 
-// LoadPlugin 加载单个插件
-func (l *PluginLoader) LoadPlugin(ctx context.Context, pluginID string) error {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	// 检查是否已加载
-	if _, exists := l.plugins[pluginID]; exists {
-		return fmt.Errorf("plugin already loaded: %s", pluginID)
+// firstUnloaded 返回第一个未成功加载的依赖
+func (l *PluginLoader) firstUnloaded(deps []*plugin_model.Dependency) string {
+	for _, dep := range deps {
+		if _, ok := l.GetPlugin(dep.PluginID); !ok {
+			return dep.PluginID
+		}
 	}
+	return ""
+}
 
+// LoadPlugin 加载单个插件。打开插件（编译、启动进程、初始化、迁移和握手）可能很慢，期间不持有锁，
+// 以免阻塞其他插件的加载、卸载和查询；打开成功后再加锁登记插件
+func (l *PluginLoader) LoadPlugin(ctx context.Context, pluginID string) error {
 	// 从数据库获取插件信息
 	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
 	if err != nil {
 		return fmt.Errorf("get plugin from database: %w", err)
 	}
 
-	pluginPath := dbPlugin.InstallPath
-	if pluginPath == "" {
-		pluginPath = filepath.Join(l.pluginsDir, "installed", pluginID)
+	// 检查是否已加载或正在加载
+	l.mu.Lock()
+	if _, exists := l.plugins[pluginID]; exists || l.loading[pluginID] {
+		l.mu.Unlock()
+		return fmt.Errorf("plugin already loaded: %s", pluginID)
 	}
+	l.loading[pluginID] = true
+	pluginPath := l.pluginPath(dbPlugin)
+	l.mu.Unlock()
 
-	// 读取插件元数据
-	metadata, err := l.readMetadata(pluginPath)
+	pluginInstance, metadata, err := l.openPlugin(ctx, pluginID, pluginPath)
+
+	l.mu.Lock()
+	delete(l.loading, pluginID)
+	if err == nil {
+		l.plugins[pluginID] = pluginInstance
+	}
+	l.mu.Unlock()
 	if err != nil {
-		return fmt.Errorf("read metadata: %w", err)
+		return err
 	}
 
-	// 编译插件（如果需要）
-	soPath := filepath.Join(pluginPath, "plugin.so")
-	if !fileExists(soPath) {
-		log.Info("Compiling plugin: %s", pluginID)
-		if err := l.compilePlugin(pluginPath); err != nil {
-			return fmt.Errorf("compile plugin: %w", err)
+	// 权限未经批准（如启用权限审批前安装的插件）时保持禁用，等待管理员授权
+	if dbPlugin.IsEnabled {
+		if err := checkPermissionsApproved(dbPlugin, metadata); err != nil {
+			log.Warn("Plugin %s is disabled until its permissions are approved: %v", pluginID, err)
+			dbPlugin.IsEnabled = false
+			if err := plugin_model.UpdatePlugin(ctx, dbPlugin); err != nil {
+				log.Error("Failed to disable plugin %s: %v", pluginID, err)
+			}
 		}
 	}
 
-	// 加载插件
-	p, err := plugin.Open(soPath)
+	GetRouter().Mount(ctx, pluginID, pluginInstance, dbPlugin.IsEnabled)
+	mountResources(pluginID, pluginInstance, dbPlugin.IsEnabled)
+	mountCronTasks(pluginID, pluginInstance, dbPlugin.IsEnabled)
+	recordStart(pluginID)
+	log.Info("Plugin loaded: %s v%s", metadata.Name, metadata.Version)
+
+	return nil
+}
+
+// openPlugin 从安装目录创建插件实例，完成初始化并同步数据库模型，但不挂载路由
+func (l *PluginLoader) openPlugin(ctx context.Context, pluginID, pluginPath string) (plugin_model.IPlugin, *plugin_model.PluginMetadata, error) {
+	// 读取插件元数据
+	metadata, err := l.readMetadata(pluginPath)
 	if err != nil {
-		return fmt.Errorf("open plugin: %w", err)
+		return nil, nil, fmt.Errorf("read metadata: %w", err)
 	}
 
-	// 获取插件实例
-	symPlugin, err := p.Lookup("Plugin")
+	// 按 plugin.json 声明的运行时创建插件实例
+	var pluginInstance plugin_model.IPlugin
+	switch metadata.GetRuntime() {
+	case plugin_model.RuntimeNative:
+		pluginInstance, err = l.openNativePlugin(pluginID, pluginPath)
+	case plugin_model.RuntimeProcess:
+		pluginInstance, err = newProcessPlugin(pluginID, pluginPath, metadata)
+	default:
+		err = fmt.Errorf("unknown runtime: %s", metadata.Runtime)
+	}
 	if err != nil {
-		return fmt.Errorf("lookup Plugin symbol: %w", err)
+		return nil, nil, err
 	}
 
-	pluginInstance, ok := symPlugin.(plugin_model.IPlugin)
-	if !ok {
-		return fmt.Errorf("invalid plugin type")
+	if err := InitPlugin(ctx, pluginID, pluginInstance, metadata); err != nil {
+		closePlugin(pluginInstance)
+		return nil, nil, err
+	}
+
+	return pluginInstance, metadata, nil
+}
+
+// InitPlugin 初始化插件并下发已保存的配置，成功后再执行数据库迁移和同步数据库模型，
+// 初始化失败的插件版本不会修改表结构。插件加载和测试工具共用这一流程，出错时由调用方释放插件实例
+func InitPlugin(ctx context.Context, pluginID string, pluginInstance plugin_model.IPlugin, metadata *plugin_model.PluginMetadata) error {
+	migrator, hasMigrations := pluginInstance.(plugin_model.Migrator)
+	if hasMigrations {
+		if _, err := checkSchemaVersion(ctx, pluginID, migrator.Migrations()); err != nil {
+			return err
+		}
 	}
 
 	// 初始化插件
-	if err := pluginInstance.Init(); err != nil {
+	host := newPluginHost(pluginID)
+	if err := callPlugin(func() error { return pluginInstance.Init(host) }); err != nil {
 		return fmt.Errorf("init plugin: %w", err)
 	}
 
+	// 下发已保存的配置
+	if err := pushConfig(ctx, pluginID, metadata, pluginInstance); err != nil {
+		return fmt.Errorf("set config: %w", err)
+	}
+
+	// 执行插件的数据库迁移
+	if hasMigrations {
+		if err := migratePlugin(ctx, pluginID, migrator.Migrations()); err != nil {
+			return fmt.Errorf("migrate plugin: %w", err)
+		}
+	}
+
 	// 注册数据库模型并创建表
 	if metadata.Hooks["models"] {
 		models := pluginInstance.RegisterModels()
 		if len(models) > 0 {
-			if err := l.syncModels(ctx, pluginID, models); err != nil {
+			if err := syncModels(ctx, pluginID, models); err != nil {
 				return fmt.Errorf("sync models: %w", err)
 			}
 			log.Info("Plugin %s synced %d models to database", pluginID, len(models))
 		}
 	}
+	return nil
+}
 
+// swapPlugin 用已初始化（需要时已启用）的新实例替换正在运行的实例，等待旧实例进行中的请求完成后将其关闭。
+// 调用方负责在启用新实例之前停用旧实例
+func (l *PluginLoader) swapPlugin(ctx context.Context, pluginID string, pluginInstance plugin_model.IPlugin, enabled bool) {
+	l.mu.Lock()
+	old := l.plugins[pluginID]
 	l.plugins[pluginID] = pluginInstance
-	log.Info("Plugin loaded: %s v%s", metadata.Name, metadata.Version)
+	l.mu.Unlock()
 
-	return nil
+	GetRouter().Mount(ctx, pluginID, pluginInstance, enabled)
+	mountResources(pluginID, pluginInstance, enabled)
+	mountCronTasks(pluginID, pluginInstance, enabled)
+	recordStart(pluginID)
+
+	if old != nil {
+		closePlugin(old)
+	}
+}
+
+// openNativePlugin 通过 Go plugin.Open 加载插件，必要时先编译 plugin.so
+func (l *PluginLoader) openNativePlugin(pluginID, pluginPath string) (plugin_model.IPlugin, error) {
+	soPath := filepath.Join(pluginPath, "plugin.so")
+	if !fileExists(soPath) {
+		log.Info("Compiling plugin: %s", pluginID)
+		if err := l.compilePlugin(pluginPath); err != nil {
+			return nil, fmt.Errorf("compile plugin: %w", err)
+		}
+	}
+
+	p, err := plugin.Open(soPath)
+	if err != nil {
+		return nil, fmt.Errorf("open plugin: %w", err)
+	}
+
+	symPlugin, err := p.Lookup("Plugin")
+	if err != nil {
+		return nil, fmt.Errorf("lookup Plugin symbol: %w", err)
+	}
+
+	pluginInstance, ok := symPlugin.(plugin_model.IPlugin)
+	if !ok {
+		return nil, fmt.Errorf("invalid plugin type")
+	}
+	return pluginInstance, nil
+}
+
+// closePlugin 释放插件占用的资源（如 process 运行时的子进程）
+func closePlugin(p plugin_model.IPlugin) {
+	if closer, ok := p.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Error("Failed to close plugin: %v", err)
+		}
+	}
 }
 
-// syncModels 同步插件的数据库模型
-func (l *PluginLoader) syncModels(ctx context.Context, pluginID string, models []interface{}) error {
+// syncModels 同步插件的数据库模型。Sync 只能新增表和列，需要修改或删除列、迁移数据的插件应实现 Migrator
+func syncModels(ctx context.Context, pluginID string, models []interface{}) error {
 	if len(models) == 0 {
 		return nil
 	}
 
+	for _, model := range models {
+		if table := db.TableName(model); !plugin_model.OwnsTable(pluginID, table, nil) {
+			return plugin_model.ErrPluginTablePrefix{PluginID: pluginID, Table: table}
+		}
+	}
+
 	// 获取数据库引擎
 	engine := db.GetEngine(ctx)
-	
+
 	// 使用 xorm 的 Sync2 方法创建或更新表结构
-	if err := engine.Sync2(models...); err != nil {
+	if err := engine.Sync(models...); err != nil {
 		return fmt.Errorf("sync models for plugin %s: %w", pluginID, err)
 	}
 
@@ -164,22 +311,27 @@ func (l *PluginLoader) syncModels(ctx context.Context, pluginID string, models [
 	return nil
 }
 
-// UnloadPlugin 卸载插件
-func (l *PluginLoader) UnloadPlugin(pluginID string) error {
+// UnloadPlugin 卸载插件，先移除路由并等待进行中的请求完成
+func (l *PluginLoader) UnloadPlugin(ctx context.Context, pluginID string) error {
+	// 先从加载器中移除再卸载路由，等待进行中的请求完成时不持有锁，以免阻塞其他插件的加载和查询
 	l.mu.Lock()
-	defer l.mu.Unlock()
-
 	pluginInstance, exists := l.plugins[pluginID]
+	delete(l.plugins, pluginID)
+	l.mu.Unlock()
 	if !exists {
 		return fmt.Errorf("plugin not loaded: %s", pluginID)
 	}
 
-	// 调用卸载方法
-	if err := pluginInstance.Uninstall(); err != nil {
+	GetRouter().Unmount(ctx, pluginID)
+	unmountResources(pluginID)
+	unmountCronTasks(pluginID)
+
+	// 调用卸载方法，失败时插件同样已被卸载
+	err := pluginInstance.Uninstall()
+	closePlugin(pluginInstance)
+	if err != nil {
 		return fmt.Errorf("uninstall plugin: %w", err)
 	}
-
-	delete(l.plugins, pluginID)
 	log.Info("Plugin unloaded: %s", pluginID)
 
 	return nil
@@ -197,7 +349,7 @@ func (l *PluginLoader) GetPlugin(pluginID string) (plugin_model.IPlugin, bool) {
 func (l *PluginLoader) GetAllPlugins() map[string]plugin_model.IPlugin {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
-	
+
 	result := make(map[string]plugin_model.IPlugin, len(l.plugins))
 	for k, v := range l.plugins {
 		result[k] = v
@@ -205,6 +357,48 @@ func (l *PluginLoader) GetAllPlugins() map[string]plugin_model.IPlugin {
 	return result
 }
 
+// pluginPath 获取插件安装目录
+func (l *PluginLoader) pluginPath(p *plugin_model.Plugin) string {
+	if p.InstallPath != "" {
+		return p.InstallPath
+	}
+	return filepath.Join(l.pluginsDir, "installed", p.PluginID)
+}
+
+// versionPath 获取插件指定版本的安装目录：installed/<id>/<version>
+func (l *PluginLoader) versionPath(pluginID, version string) string {
+	return filepath.Join(l.pluginsDir, "installed", pluginID, version)
+}
+
+// migrateLegacyLayout 将旧版本直接安装在 installed/<id> 下的插件迁移到版本目录
+func (l *PluginLoader) migrateLegacyLayout(ctx context.Context, p *plugin_model.Plugin) error {
+	legacyPath := filepath.Join(l.pluginsDir, "installed", p.PluginID)
+	if !p.IsInstalled || filepath.Clean(l.pluginPath(p)) != legacyPath || !fileExists(filepath.Join(legacyPath, "plugin.json")) {
+		return nil
+	}
+	if !plugin_model.IsValidPluginVersion(p.Version) {
+		return plugin_model.ErrPluginInvalidVersion{Version: p.Version}
+	}
+
+	tmpPath := filepath.Join(l.pluginsDir, "temp", "migrate-"+p.PluginID)
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(legacyPath, tmpPath); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(legacyPath, 0o755); err != nil {
+		return err
+	}
+	newPath := l.versionPath(p.PluginID, p.Version)
+	if err := os.Rename(tmpPath, newPath); err != nil {
+		return err
+	}
+
+	p.InstallPath = newPath
+	return plugin_model.UpdatePlugin(ctx, p)
+}
+
 // readMetadata 读取插件元数据
 func (l *PluginLoader) readMetadata(pluginPath string) (*plugin_model.PluginMetadata, error) {
 	metaPath := filepath.Join(pluginPath, "plugin.json")
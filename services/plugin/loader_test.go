@@ -0,0 +1,64 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type unloadTestPlugin struct {
+	routeTestPlugin
+	uninstallErr error
+}
+
+func (p *unloadTestPlugin) Uninstall() error {
+	return p.uninstallErr
+}
+
+func TestUnloadPlugin(t *testing.T) {
+	const pluginID = "unload-test"
+	p := &unloadTestPlugin{
+		routeTestPlugin: routeTestPlugin{body: "slow", started: make(chan struct{}), release: make(chan struct{})},
+		uninstallErr:    errors.New("uninstall failed"),
+	}
+	l := &PluginLoader{plugins: map[string]plugin_model.IPlugin{pluginID: p}}
+	GetRouter().Mount(t.Context(), pluginID, p, true)
+
+	web := GetRouter().WebHandler(nil, http.NotFoundHandler())
+	served := make(chan *httptest.ResponseRecorder)
+	go func() { served <- serveRouter(web, "/test-plugin/a") }()
+	<-p.started
+
+	unloaded := make(chan error)
+	go func() { unloaded <- l.UnloadPlugin(t.Context(), pluginID) }()
+
+	// the loader is not locked while waiting for in-flight requests
+	assert.Eventually(t, func() bool {
+		_, ok := l.GetPlugin(pluginID)
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+	select {
+	case <-unloaded:
+		t.Fatal("unload finished before in-flight request")
+	default:
+	}
+
+	close(p.release)
+	assert.Equal(t, "slow a", (<-served).Body.String())
+	assert.ErrorContains(t, <-unloaded, "uninstall failed")
+
+	// the failed uninstall still removes the plugin, so it can be loaded again
+	_, ok := l.GetPlugin(pluginID)
+	assert.False(t, ok)
+	assert.False(t, GetRouter().IsMounted(pluginID))
+	assert.EqualError(t, l.UnloadPlugin(t.Context(), pluginID), "plugin not loaded: "+pluginID)
+}
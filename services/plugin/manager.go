@@ -6,32 +6,41 @@ package plugin
 import (
 	"archive/zip"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 
 	plugin_model "code.gitea.io/gitea/models/plugin"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
 )
 
 // PluginManager 插件管理器
 type PluginManager struct {
-	loader         *PluginLoader
-	pluginsDir     string
-	marketplaceURL string
+	loader     *PluginLoader
+	pluginsDir string
+	market     *market
+	upgradeMu  sync.Mutex // 串行化升级和回滚
 }
 
 var globalManager *PluginManager
 
+// 解压插件包的限制，防止压缩炸弹耗尽磁盘
+var (
+	unzipMaxFiles = 10000
+	unzipMaxSize  = int64(1 << 30) // 解压后的总大小
+)
+
 // InitManager 初始化插件管理器
-func InitManager(pluginsDir, marketplaceURL string) error {
+func InitManager(pluginsDir string) error {
 	globalManager = &PluginManager{
-		loader:         GetLoader(),
-		pluginsDir:     pluginsDir,
-		marketplaceURL: marketplaceURL,
+		loader:     GetLoader(),
+		pluginsDir: pluginsDir,
+		market:     newMarket(filepath.Join(pluginsDir, "cache"), setting.PluginRegistries),
 	}
 
 	// 设置插件目录
@@ -42,6 +51,7 @@ func InitManager(pluginsDir, marketplaceURL string) error {
 		filepath.Join(pluginsDir, "installed"),
 		filepath.Join(pluginsDir, "temp"),
 		filepath.Join(pluginsDir, "config"),
+		filepath.Join(pluginsDir, "cache"),
 	}
 
 	for _, dir := range dirs {
@@ -50,6 +60,14 @@ func InitManager(pluginsDir, marketplaceURL string) error {
 		}
 	}
 
+	templates.SetSlotRenderer(RenderSlot)
+
+	if err := initEventBus(); err != nil {
+		log.Error("Unable to initialize plugin event bus: %v", err)
+	}
+	registerHealthCheckTask()
+	registerMarketRefreshTask()
+
 	log.Info("Plugin manager initialized")
 	return nil
 }
@@ -59,37 +77,117 @@ func GetManager() *PluginManager {
 	return globalManager
 }
 
-// Install 安装插件
-func (m *PluginManager) Install(ctx context.Context, pluginID, version string) error {
+// Install 从插件市场安装插件
+func (m *PluginManager) Install(ctx context.Context, doer *user_model.User, pluginID, version string) error {
 	// 检查插件是否已安装
 	existing, err := plugin_model.GetPluginByID(ctx, pluginID)
 	if err == nil && existing.IsInstalled {
 		return plugin_model.ErrPluginAlreadyExist{PluginID: pluginID}
 	}
 
-	// 1. 从插件市场下载
-	downloadURL := fmt.Sprintf("%s/plugins/%s/%s/download",
-		m.marketplaceURL, pluginID, version)
-
-	zipPath := filepath.Join(m.pluginsDir, "temp", pluginID+".zip")
-	if err := m.downloadFile(downloadURL, zipPath); err != nil {
-		return fmt.Errorf("download plugin: %w", err)
+	zipPath, err := m.downloadPackage(ctx, pluginID, version)
+	if err != nil {
+		return err
 	}
 	defer os.Remove(zipPath)
 
-	// 2. 解压到 installed 目录
-	installPath := filepath.Join(m.pluginsDir, "installed", pluginID)
-	if err := m.unzip(zipPath, installPath); err != nil {
-		return fmt.Errorf("unzip plugin: %w", err)
+	_, err = m.installArchive(ctx, doer, zipPath, pluginID, plugin_model.SourceMarketplace)
+	return err
+}
+
+// downloadPackage 从插件市场下载指定版本的插件包到临时目录并校验 SHA256，version 为空或 latest 时下载兼容的最新版本
+func (m *PluginManager) downloadPackage(ctx context.Context, pluginID, version string) (string, error) {
+	if !plugin_model.IsValidPluginID(pluginID) {
+		return "", plugin_model.ErrPluginInvalidID{PluginID: pluginID}
+	}
+	if version != "" && !plugin_model.IsValidPluginVersion(version) {
+		return "", plugin_model.ErrPluginInvalidVersion{Version: version}
+	}
+
+	registry, mv, err := m.market.find(ctx, pluginID, version)
+	if err != nil {
+		return "", err
+	}
+
+	zipPath := filepath.Join(m.pluginsDir, "temp", pluginID+"-"+mv.Version+".zip")
+	if err := m.market.download(ctx, registry, mv, zipPath); err != nil {
+		if plugin_model.IsErrPackageVerification(err) {
+			return "", err
+		}
+		return "", fmt.Errorf("download plugin: %w", err)
+	}
+	return zipPath, nil
+}
+
+// installArchive 验证并安装插件包，expectedID 非空时要求包内插件 ID 一致
+func (m *PluginManager) installArchive(ctx context.Context, doer *user_model.User, zipPath, expectedID, source string) (*plugin_model.Plugin, error) {
+	// 1. 验证签名和校验和
+	signer, err := verifyPackage(ctx, zipPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 解压到临时目录，读取元数据后才能确定插件 ID
+	stagingPath, err := os.MkdirTemp(filepath.Join(m.pluginsDir, "temp"), "install-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(stagingPath)
+
+	if err := m.unzip(zipPath, stagingPath); err != nil {
+		return nil, fmt.Errorf("unzip plugin: %w", err)
 	}
 
 	// 3. 读取插件元数据
-	metadata, err := m.loader.readMetadata(installPath)
+	metadata, err := m.loader.readMetadata(stagingPath)
+	if err != nil {
+		return nil, fmt.Errorf("read metadata: %w", err)
+	}
+	if !plugin_model.IsValidPluginID(metadata.ID) {
+		return nil, plugin_model.ErrPluginInvalidID{PluginID: metadata.ID}
+	}
+	if !plugin_model.IsValidPluginVersion(metadata.Version) {
+		return nil, plugin_model.ErrPluginInvalidVersion{Version: metadata.Version}
+	}
+	if expectedID != "" && metadata.ID != expectedID {
+		return nil, fmt.Errorf("package contains plugin %s, expected %s", metadata.ID, expectedID)
+	}
+
+	existing, err := plugin_model.GetPluginByID(ctx, metadata.ID)
+	if err == nil && existing.IsInstalled {
+		return nil, plugin_model.ErrPluginAlreadyExist{PluginID: metadata.ID}
+	}
+
+	// 4. 检查 Gitea 版本和依赖插件
+	if err := checkGiteaVersion(metadata); err != nil {
+		return nil, err
+	}
+	deps, err := metadata.ParseDependencies()
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := m.loader.loadNodes(ctx)
 	if err != nil {
-		return fmt.Errorf("read metadata: %w", err)
+		return nil, err
+	}
+	if err := checkDependencies(metadata.ID, deps, nodes, false); err != nil {
+		return nil, err
 	}
 
-	// 4. 保存到数据库
+	// 5. 移动到版本目录 installed/<id>/<version>
+	pluginDir := filepath.Join(m.pluginsDir, "installed", metadata.ID)
+	if err := os.RemoveAll(pluginDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(pluginDir, 0o755); err != nil {
+		return nil, err
+	}
+	installPath := m.loader.versionPath(metadata.ID, metadata.Version)
+	if err := os.Rename(stagingPath, installPath); err != nil {
+		return nil, fmt.Errorf("move plugin files: %w", err)
+	}
+
+	// 6. 保存到数据库
 	dbPlugin := &plugin_model.Plugin{
 		PluginID:    metadata.ID,
 		Name:        metadata.Name,
@@ -101,127 +199,217 @@ func (m *PluginManager) Install(ctx context.Context, pluginID, version string) e
 		IsEnabled:   false,
 		IsInstalled: true,
 		InstallPath: installPath,
+		Source:      source,
+	}
+	if signer != nil {
+		dbPlugin.Signer = signer.Fingerprint
 	}
 
 	if err := plugin_model.CreatePlugin(ctx, dbPlugin); err != nil {
-		return fmt.Errorf("save to database: %w", err)
+		removeInstallDir(pluginDir)
+		return nil, fmt.Errorf("save to database: %w", err)
 	}
 
-	// 5. 加载插件
-	if err := m.loader.LoadPlugin(ctx, pluginID); err != nil {
-		return fmt.Errorf("load plugin: %w", err)
+	// 7. 加载插件，失败时删除记录和文件，以便修正后重新安装
+	if err := m.loader.LoadPlugin(ctx, metadata.ID); err != nil {
+		if err := plugin_model.DeletePlugin(ctx, metadata.ID); err != nil {
+			log.Error("Failed to delete record of plugin %s after load failure: %v", metadata.ID, err)
+		}
+		removeInstallDir(pluginDir)
+		return nil, fmt.Errorf("load plugin: %w", err)
 	}
 
-	log.Info("Plugin installed: %s v%s", metadata.Name, metadata.Version)
-	return nil
+	writeAudit(ctx, doer, &plugin_model.Audit{
+		PluginID:   metadata.ID,
+		Action:     plugin_model.AuditActionInstall,
+		NewVersion: metadata.Version,
+		Detail:     source,
+	})
+	log.Info("Plugin installed: %s v%s (%s)", metadata.Name, metadata.Version, source)
+	return dbPlugin, nil
+}
+
+// removeInstallDir 删除安装失败的插件目录
+func removeInstallDir(dir string) {
+	if err := os.RemoveAll(dir); err != nil {
+		log.Error("Failed to remove plugin directory %s: %v", dir, err)
+	}
 }
 
-// Uninstall 卸载插件
-func (m *PluginManager) Uninstall(ctx context.Context, pluginID string) error {
+// Uninstall 卸载插件，purgeData 为 true 时同时删除插件的数据表
+func (m *PluginManager) Uninstall(ctx context.Context, doer *user_model.User, pluginID string, purgeData bool) error {
 	// 1. 从数据库获取插件信息
 	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
 	if err != nil {
 		return err
 	}
 
-	// 2. 卸载插件
-	if err := m.loader.UnloadPlugin(pluginID); err != nil {
+	// 2. 检查是否仍被其他插件依赖
+	nodes, err := m.loader.loadNodes(ctx)
+	if err != nil {
+		return err
+	}
+	if dependents := dependentsOf(pluginID, nodes, false); len(dependents) > 0 {
+		return plugin_model.ErrPluginHasDependents{PluginID: pluginID, Dependents: dependents}
+	}
+
+	// 3. 卸载插件
+	if err := m.loader.UnloadPlugin(ctx, pluginID); err != nil {
 		log.Warn("Failed to unload plugin %s: %v", pluginID, err)
 	}
 
-	// 3. 删除插件文件
-	if err := os.RemoveAll(dbPlugin.InstallPath); err != nil {
-		return fmt.Errorf("remove plugin files: %w", err)
+	// 4. 删除插件文件，包括保留的上一个版本，以及插件的数据目录和对象存储中的文件
+	for _, dir := range []string{dbPlugin.InstallPath, dbPlugin.PreviousPath, filepath.Join(m.pluginsDir, "installed", pluginID)} {
+		if dir == "" {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("remove plugin files: %w", err)
+		}
+	}
+	if err := removePluginFiles(pluginID); err != nil {
+		return err
+	}
+
+	// 5. 按管理员确认删除插件数据，否则保留数据表以便重新安装
+	if purgeData {
+		if err := m.purgePluginData(ctx, pluginID); err != nil {
+			return fmt.Errorf("purge plugin data: %w", err)
+		}
 	}
 
-	// 4. 从数据库删除
+	// 6. 从数据库删除
 	if err := plugin_model.DeletePlugin(ctx, pluginID); err != nil {
 		return fmt.Errorf("delete from database: %w", err)
 	}
 
+	removeHealth(pluginID)
+	audit := &plugin_model.Audit{
+		PluginID:   pluginID,
+		Action:     plugin_model.AuditActionUninstall,
+		OldVersion: dbPlugin.Version,
+	}
+	if purgeData {
+		audit.Detail = "purge data"
+	}
+	writeAudit(ctx, doer, audit)
 	log.Info("Plugin uninstalled: %s", pluginID)
 	return nil
 }
 
 // Enable 启用插件
-func (m *PluginManager) Enable(ctx context.Context, pluginID string) error {
+func (m *PluginManager) Enable(ctx context.Context, doer *user_model.User, pluginID string) error {
 	// 1. 获取插件实例
 	pluginInstance, ok := m.loader.GetPlugin(pluginID)
 	if !ok {
 		return fmt.Errorf("plugin not loaded: %s", pluginID)
 	}
 
-	// 2. 调用启用方法
-	if err := pluginInstance.Enable(); err != nil {
-		return fmt.Errorf("enable plugin: %w", err)
-	}
-
-	// 3. 更新数据库
-	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	// 2. 检查 Gitea 版本，依赖插件须已安装、版本满足且已启用
+	nodes, err := m.loader.loadNodes(ctx)
 	if err != nil {
 		return err
 	}
+	node, ok := nodes[pluginID]
+	if !ok {
+		return plugin_model.ErrPluginNotExist{PluginID: pluginID}
+	}
+	// 无法读取元数据时不能确认插件声明的权限已获批准
+	if node.Metadata == nil {
+		return fmt.Errorf("plugin metadata unavailable: %s", pluginID)
+	}
+	if err := checkGiteaVersion(node.Metadata); err != nil {
+		return err
+	}
+	// 插件声明的权限须经管理员在授权页面批准
+	if err := checkPermissionsApproved(node.Plugin, node.Metadata); err != nil {
+		return err
+	}
+	if err := checkNodeDependencies(pluginID, node, nodes, true); err != nil {
+		return err
+	}
+
+	// 3. 调用启用方法
+	if err := callPlugin(pluginInstance.Enable); err != nil {
+		return fmt.Errorf("enable plugin: %w", err)
+	}
+	resetHealth(pluginID)
+	GetRouter().SetEnabled(ctx, pluginID, true)
+	mountResources(pluginID, pluginInstance, true)
+	mountCronTasks(pluginID, pluginInstance, true)
 
+	// 4. 更新数据库
+	dbPlugin := node.Plugin
 	dbPlugin.IsEnabled = true
 	if err := plugin_model.UpdatePlugin(ctx, dbPlugin); err != nil {
 		return fmt.Errorf("update database: %w", err)
 	}
 
+	writeAudit(ctx, doer, &plugin_model.Audit{PluginID: pluginID, Action: plugin_model.AuditActionEnable, NewVersion: dbPlugin.Version})
 	log.Info("Plugin enabled: %s", pluginID)
 	return nil
 }
 
 // Disable 禁用插件
-func (m *PluginManager) Disable(ctx context.Context, pluginID string) error {
-	// 1. 获取插件实例
-	pluginInstance, ok := m.loader.GetPlugin(pluginID)
-	if !ok {
+func (m *PluginManager) Disable(ctx context.Context, doer *user_model.User, pluginID string) error {
+	// 1. 检查插件是否已加载
+	if _, ok := m.loader.GetPlugin(pluginID); !ok {
 		return fmt.Errorf("plugin not loaded: %s", pluginID)
 	}
 
-	// 2. 调用禁用方法
-	if err := pluginInstance.Disable(); err != nil {
-		return fmt.Errorf("disable plugin: %w", err)
-	}
-
-	// 3. 更新数据库
-	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	// 2. 检查是否仍被已启用的插件依赖
+	nodes, err := m.loader.loadNodes(ctx)
 	if err != nil {
 		return err
 	}
+	if dependents := dependentsOf(pluginID, nodes, true); len(dependents) > 0 {
+		return plugin_model.ErrPluginHasDependents{PluginID: pluginID, Dependents: dependents}
+	}
 
-	dbPlugin.IsEnabled = false
-	if err := plugin_model.UpdatePlugin(ctx, dbPlugin); err != nil {
-		return fmt.Errorf("update database: %w", err)
+	// 3. 停止接收新请求，再调用禁用方法
+	dbPlugin, err := m.disable(ctx, pluginID, false)
+	if err != nil {
+		return err
 	}
 
+	writeAudit(ctx, doer, &plugin_model.Audit{PluginID: pluginID, Action: plugin_model.AuditActionDisable, OldVersion: dbPlugin.Version})
 	log.Info("Plugin disabled: %s", pluginID)
 	return nil
 }
 
-// ListInstalled 列出已安装的插件
-func (m *PluginManager) ListInstalled(ctx context.Context) ([]*plugin_model.Plugin, error) {
-	return plugin_model.ListPlugins(ctx)
-}
-
-// ListAvailable 列出可用插件（从市场获取）
-func (m *PluginManager) ListAvailable() ([]*plugin_model.PluginMetadata, error) {
-	resp, err := http.Get(m.marketplaceURL + "/api/plugins")
-	if err != nil {
-		return nil, fmt.Errorf("fetch from marketplace: %w", err)
+// disable 停止插件接收请求并等待进行中的请求完成（与卸载相同），再调用其禁用方法并更新数据库，不检查依赖。
+// force 为 true 时（隔离插件）忽略插件禁用方法的错误，插件仍会被禁用
+func (m *PluginManager) disable(ctx context.Context, pluginID string, force bool) (*plugin_model.Plugin, error) {
+	pluginInstance, ok := m.loader.GetPlugin(pluginID)
+	if !ok {
+		return nil, fmt.Errorf("plugin not loaded: %s", pluginID)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("marketplace returned status %d", resp.StatusCode)
+	GetRouter().SetEnabled(ctx, pluginID, false)
+	if err := callPlugin(pluginInstance.Disable); err != nil {
+		if !force {
+			GetRouter().SetEnabled(ctx, pluginID, true)
+			return nil, fmt.Errorf("disable plugin: %w", err)
+		}
+		log.Warn("Failed to disable plugin %s: %v", pluginID, err)
 	}
+	unmountResources(pluginID)
+	unmountCronTasks(pluginID)
 
-	var plugins []*plugin_model.PluginMetadata
-	if err := json.NewDecoder(resp.Body).Decode(&plugins); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	if err != nil {
+		return nil, err
+	}
+	dbPlugin.IsEnabled = false
+	if err := plugin_model.UpdatePlugin(ctx, dbPlugin); err != nil {
+		return nil, fmt.Errorf("update database: %w", err)
 	}
+	return dbPlugin, nil
+}
 
-	return plugins, nil
+// ListInstalled 列出已安装的插件
+func (m *PluginManager) ListInstalled(ctx context.Context) ([]*plugin_model.Plugin, error) {
+	return plugin_model.ListPlugins(ctx)
 }
 
 // GetPluginInfo 获取插件信息
@@ -234,29 +422,7 @@ func (m *PluginManager) GetPluginInfo(pluginID string) (*plugin_model.PluginInfo
 	return pluginInstance.Info(), nil
 }
 
-// downloadFile 下载文件
-func (m *PluginManager) downloadFile(url, filepath string) error {
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download failed with status %d", resp.StatusCode)
-	}
-
-	out, err := os.Create(filepath)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-
-	_, err = io.Copy(out, resp.Body)
-	return err
-}
-
-// unzip 解压文件
+// unzip 解压文件，限制文件数量和解压后的总大小，文件权限最多为 0755
 func (m *PluginManager) unzip(src, dest string) error {
 	r, err := zip.OpenReader(src)
 	if err != nil {
@@ -264,6 +430,11 @@ func (m *PluginManager) unzip(src, dest string) error {
 	}
 	defer r.Close()
 
+	if len(r.File) > unzipMaxFiles {
+		return plugin_model.ErrPackageVerification{Reason: fmt.Sprintf("package contains more than %d files", unzipMaxFiles)}
+	}
+
+	remaining := unzipMaxSize
 	for _, f := range r.File {
 		fpath := filepath.Join(dest, f.Name)
 
@@ -273,7 +444,9 @@ func (m *PluginManager) unzip(src, dest string) error {
 		}
 
 		if f.FileInfo().IsDir() {
-			os.MkdirAll(fpath, os.ModePerm)
+			if err := os.MkdirAll(fpath, os.ModePerm); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -281,7 +454,7 @@ func (m *PluginManager) unzip(src, dest string) error {
 			return err
 		}
 
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode().Perm()&0o755)
 		if err != nil {
 			return err
 		}
@@ -292,13 +465,18 @@ func (m *PluginManager) unzip(src, dest string) error {
 			return err
 		}
 
-		_, err = io.Copy(outFile, rc)
+		// 不信任 zip 头中声明的大小，按实际写入的字节计数
+		n, err := io.Copy(outFile, io.LimitReader(rc, remaining+1))
 		outFile.Close()
 		rc.Close()
 
 		if err != nil {
 			return err
 		}
+		remaining -= n
+		if remaining < 0 {
+			return plugin_model.ErrPackageVerification{Reason: fmt.Sprintf("package is larger than %d bytes when extracted", unzipMaxSize)}
+		}
 	}
 
 	return nil
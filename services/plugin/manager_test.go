@@ -0,0 +1,81 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestZip 写入 zip 文件，files 的值为文件内容，文件权限均为 mode
+func writeTestZip(t *testing.T, files map[string]string, mode os.FileMode) string {
+	zipPath := filepath.Join(t.TempDir(), "plugin.zip")
+	f, err := os.Create(zipPath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		header.SetMode(mode)
+		fw, err := w.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return zipPath
+}
+
+func TestUnzip(t *testing.T) {
+	m := &PluginManager{}
+
+	t.Run("Mode", func(t *testing.T) {
+		dest := t.TempDir()
+		require.NoError(t, m.unzip(writeTestZip(t, map[string]string{"bin/plugin": "x"}, os.ModeSetuid|0o777), dest))
+		info, err := os.Stat(filepath.Join(dest, "bin", "plugin"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o755), info.Mode())
+	})
+
+	t.Run("TooManyFiles", func(t *testing.T) {
+		defer test.MockVariableValue(&unzipMaxFiles, 2)()
+		zipPath := writeTestZip(t, map[string]string{"a": "a", "b": "b", "c": "c"}, 0o644)
+		err := m.unzip(zipPath, t.TempDir())
+		assert.True(t, plugin_model.IsErrPackageVerification(err), "%v", err)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		defer test.MockVariableValue(&unzipMaxSize, int64(1024))()
+		zipPath := writeTestZip(t, map[string]string{"a": strings.Repeat("a", 600), "b": strings.Repeat("b", 600)}, 0o644)
+		err := m.unzip(zipPath, t.TempDir())
+		assert.True(t, plugin_model.IsErrPackageVerification(err), "%v", err)
+	})
+}
+
+func TestEnableWithoutMetadata(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	// plugin.json 缺失时无法确认权限已获批准，不能启用
+	const pluginID = "enable-no-metadata"
+	p := &routeTestPlugin{}
+	l := &PluginLoader{pluginsDir: t.TempDir(), plugins: map[string]plugin_model.IPlugin{pluginID: p}, loading: make(map[string]bool)}
+	m := &PluginManager{loader: l}
+	require.NoError(t, plugin_model.CreatePlugin(t.Context(), &plugin_model.Plugin{PluginID: pluginID, Name: pluginID, Version: "1.0.0", IsInstalled: true, InstallPath: t.TempDir()}))
+
+	assert.ErrorContains(t, m.Enable(t.Context(), nil, pluginID), "metadata unavailable")
+	dbPlugin, err := plugin_model.GetPluginByID(t.Context(), pluginID)
+	require.NoError(t, err)
+	assert.False(t, dbPlugin.IsEnabled)
+}
@@ -0,0 +1,486 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/cron"
+)
+
+const (
+	marketFetchTimeout = 30 * time.Second
+	marketMaxIndexSize = 10 << 20
+)
+
+// marketCache 注册表索引的本地缓存，保存在 <插件目录>/cache/market-<名称>.json，Gitea 重启后继续使用
+type marketCache struct {
+	ETag         string                    `json:"etag"`
+	LastModified string                    `json:"last_modified"`
+	FetchedUnix  timeutil.TimeStamp        `json:"fetched_unix"`
+	Index        *plugin_model.MarketIndex `json:"index"`
+}
+
+// marketRegistry 插件市场注册表及其缓存的索引
+type marketRegistry struct {
+	setting.PluginRegistry
+
+	mu        sync.Mutex
+	cache     *marketCache
+	lastError string
+}
+
+// market 所有配置的插件市场注册表
+type market struct {
+	cacheDir   string
+	registries []*marketRegistry
+	client     *http.Client
+}
+
+func newMarket(cacheDir string, registries []setting.PluginRegistry) *market {
+	mk := &market{
+		cacheDir: cacheDir,
+		client: &http.Client{
+			Timeout:   marketFetchTimeout,
+			Transport: &http.Transport{Proxy: proxy.Proxy()},
+		},
+	}
+	for _, r := range registries {
+		mk.registries = append(mk.registries, &marketRegistry{PluginRegistry: r})
+	}
+	return mk
+}
+
+func (mk *market) cachePath(r *marketRegistry) string {
+	return filepath.Join(mk.cacheDir, "market-"+r.Name+".json")
+}
+
+// indexOf 返回注册表的索引，首次使用时读取本地缓存，没有缓存时立即获取
+func (mk *market) indexOf(ctx context.Context, r *marketRegistry) *plugin_model.MarketIndex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cache == nil {
+		if data, err := os.ReadFile(mk.cachePath(r)); err == nil {
+			var c marketCache
+			if err := json.Unmarshal(data, &c); err == nil && c.Index != nil {
+				r.cache = &c
+			}
+		}
+	}
+	if r.cache == nil && r.lastError == "" {
+		if err := mk.fetch(ctx, r); err != nil {
+			log.Warn("Failed to fetch plugin registry %s: %v", r.Name, err)
+			r.lastError = err.Error()
+		}
+	}
+	if r.cache == nil {
+		return nil
+	}
+	return r.cache.Index
+}
+
+// refresh 重新获取注册表的索引，HTTP 注册表使用 ETag 和 Last-Modified 避免重复下载
+func (mk *market) refresh(ctx context.Context, r *marketRegistry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := mk.fetch(ctx, r)
+	r.lastError = ""
+	if err != nil {
+		r.lastError = err.Error()
+	}
+	return err
+}
+
+// Refresh 刷新所有注册表，单个注册表失败时继续使用其缓存
+func (mk *market) Refresh(ctx context.Context) error {
+	var errs []error
+	for _, r := range mk.registries {
+		if err := mk.refresh(ctx, r); err != nil {
+			errs = append(errs, fmt.Errorf("registry %s: %w", r.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fetch 获取注册表的索引并写入缓存，调用方需持有 r.mu
+func (mk *market) fetch(ctx context.Context, r *marketRegistry) error {
+	var c marketCache
+	if r.cache != nil {
+		c = *r.cache
+	}
+
+	var data []byte
+	if indexPath, ok := localRegistryPath(r.URL); ok {
+		var err error
+		if data, err = os.ReadFile(indexPath); err != nil {
+			return err
+		}
+	} else {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL, nil)
+		if err != nil {
+			return err
+		}
+		if r.Token != "" {
+			req.Header.Set("Authorization", "token "+r.Token)
+		}
+		if c.Index != nil {
+			if c.ETag != "" {
+				req.Header.Set("If-None-Match", c.ETag)
+			}
+			if c.LastModified != "" {
+				req.Header.Set("If-Modified-Since", c.LastModified)
+			}
+		}
+
+		resp, err := mk.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusNotModified:
+			if c.Index == nil {
+				return errors.New("registry returned 304 without a cached index")
+			}
+			c.FetchedUnix = timeutil.TimeStampNow()
+			return mk.saveCache(r, &c)
+		case http.StatusOK:
+		default:
+			return fmt.Errorf("registry returned status %d", resp.StatusCode)
+		}
+
+		if data, err = io.ReadAll(io.LimitReader(resp.Body, marketMaxIndexSize+1)); err != nil {
+			return err
+		}
+		if len(data) > marketMaxIndexSize {
+			return fmt.Errorf("index is larger than %d bytes", marketMaxIndexSize)
+		}
+		c.ETag = resp.Header.Get("ETag")
+		c.LastModified = resp.Header.Get("Last-Modified")
+	}
+
+	var index plugin_model.MarketIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return fmt.Errorf("decode index: %w", err)
+	}
+	problems, err := index.Validate()
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		log.Warn("Plugin registry %s: skipped %s", r.Name, problem)
+	}
+
+	c.Index = &index
+	c.FetchedUnix = timeutil.TimeStampNow()
+	return mk.saveCache(r, &c)
+}
+
+// saveCache 更新内存和磁盘上的缓存，写磁盘失败时只记录日志
+func (mk *market) saveCache(r *marketRegistry, c *marketCache) error {
+	r.cache = c
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	tmpPath := mk.cachePath(r) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		log.Error("Failed to write plugin registry cache: %v", err)
+		return nil
+	}
+	if err := os.Rename(tmpPath, mk.cachePath(r)); err != nil {
+		log.Error("Failed to write plugin registry cache: %v", err)
+	}
+	return nil
+}
+
+// localRegistryPath 注册表地址为 file:// 或本地路径时返回文件路径
+func localRegistryPath(s string) (string, bool) {
+	if filepath.IsAbs(s) {
+		return s, true
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return s, true
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return "", false
+	case "file":
+		return u.Path, true
+	default:
+		return s, true
+	}
+}
+
+// find 按注册表优先级查找插件，version 为空或 latest 时返回兼容当前 Gitea 的最新版本
+func (mk *market) find(ctx context.Context, pluginID, version string) (*marketRegistry, *plugin_model.MarketVersion, error) {
+	for _, r := range mk.registries {
+		index := mk.indexOf(ctx, r)
+		if index == nil {
+			continue
+		}
+		for _, p := range index.Plugins {
+			if p.ID != pluginID {
+				continue
+			}
+			var mv *plugin_model.MarketVersion
+			if version == "" || version == "latest" {
+				mv = p.LatestVersion(setting.AppVer)
+			} else {
+				mv = p.FindVersion(version)
+			}
+			if mv == nil {
+				return nil, nil, plugin_model.ErrMarketPluginNotExist{PluginID: pluginID, Version: version}
+			}
+			return r, mv, nil
+		}
+	}
+	return nil, nil, plugin_model.ErrMarketPluginNotExist{PluginID: pluginID, Version: version}
+}
+
+// download 下载插件包到 dest 并校验 SHA256，相对地址基于注册表索引的地址
+func (mk *market) download(ctx context.Context, r *marketRegistry, mv *plugin_model.MarketVersion, dest string) error {
+	var src io.ReadCloser
+	if indexPath, ok := localRegistryPath(r.URL); ok {
+		pkgPath, isLocal := localRegistryPath(mv.URL)
+		if !isLocal {
+			return mk.downloadHTTP(ctx, mv.URL, "", mv.SHA256, dest)
+		}
+		if !filepath.IsAbs(pkgPath) {
+			pkgPath = filepath.Join(filepath.Dir(indexPath), pkgPath)
+		}
+		f, err := os.Open(pkgPath)
+		if err != nil {
+			return err
+		}
+		src = f
+	} else {
+		base, err := url.Parse(r.URL)
+		if err != nil {
+			return err
+		}
+		ref, err := url.Parse(mv.URL)
+		if err != nil {
+			return err
+		}
+		pkgURL := base.ResolveReference(ref)
+		token := ""
+		if pkgURL.Host == base.Host {
+			token = r.Token // 令牌只发送给注册表所在的主机
+		}
+		return mk.downloadHTTP(ctx, pkgURL.String(), token, mv.SHA256, dest)
+	}
+	defer src.Close()
+	return writeVerified(src, mv.SHA256, dest)
+}
+
+func (mk *market) downloadHTTP(ctx context.Context, pkgURL, token, sum, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pkgURL, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	// 插件包可能较大，不使用获取索引时的超时
+	resp, err := (&http.Client{Transport: mk.client.Transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+	return writeVerified(resp.Body, sum, dest)
+}
+
+// writeVerified 写入文件并校验 SHA256，校验失败时删除文件
+func writeVerified(r io.Reader, sum, dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, h), r)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && hex.EncodeToString(h.Sum(nil)) != sum {
+		err = plugin_model.ErrPackageVerification{Reason: "checksum does not match the marketplace index"}
+	}
+	if err != nil {
+		_ = os.Remove(dest)
+	}
+	return err
+}
+
+// MarketSearchOptions 插件市场的搜索条件
+type MarketSearchOptions struct {
+	Keyword     string // 匹配插件 ID、名称、描述和作者
+	Category    string
+	Registry    string
+	UpdatesOnly bool // 只列出有可用更新的已安装插件
+}
+
+// MarketEntry 插件市场中的一个插件及其安装状态
+type MarketEntry struct {
+	*plugin_model.MarketPlugin
+	Registry         string
+	Latest           *plugin_model.MarketVersion // 兼容当前 Gitea 的最新版本，没有兼容版本时为 nil
+	InstalledVersion string
+	UpdateAvailable  bool
+	Native           bool // 已安装的是 native 插件，只能重新安装并重启 Gitea 来更新
+}
+
+// MarketRegistryStatus 注册表的缓存状态
+type MarketRegistryStatus struct {
+	Name        string
+	URL         string
+	FetchedUnix timeutil.TimeStamp
+	Plugins     int
+	Error       string
+}
+
+// MarketResult 插件市场的搜索结果
+type MarketResult struct {
+	Entries    []*MarketEntry
+	Categories []string // 所有插件的分类，用于筛选
+	Registries []*MarketRegistryStatus
+}
+
+// SearchMarket 在所有注册表的缓存索引中搜索插件，并标记已安装插件的可用更新
+func (m *PluginManager) SearchMarket(ctx context.Context, opts MarketSearchOptions) (*MarketResult, error) {
+	nodes, err := m.loader.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	installedVersions := make(map[string]string, len(nodes))
+	for id, node := range nodes {
+		installedVersions[id] = node.Plugin.Version
+	}
+
+	result := &MarketResult{}
+	seen := make(map[string]bool)
+	categories := make(map[string]bool)
+	keyword := strings.ToLower(strings.TrimSpace(opts.Keyword))
+	for _, r := range m.market.registries {
+		index := m.market.indexOf(ctx, r)
+
+		r.mu.Lock()
+		status := &MarketRegistryStatus{Name: r.Name, URL: r.URL, Error: r.lastError}
+		if r.cache != nil {
+			status.FetchedUnix = r.cache.FetchedUnix
+		}
+		r.mu.Unlock()
+		result.Registries = append(result.Registries, status)
+		if index == nil {
+			continue
+		}
+		status.Plugins = len(index.Plugins)
+
+		for _, p := range index.Plugins {
+			// 靠前的注册表优先
+			if seen[p.ID] {
+				continue
+			}
+			seen[p.ID] = true
+			for _, c := range p.Categories {
+				categories[c] = true
+			}
+
+			entry := &MarketEntry{
+				MarketPlugin:     p,
+				Registry:         r.Name,
+				Latest:           p.LatestVersion(setting.AppVer),
+				InstalledVersion: installedVersions[p.ID],
+			}
+			if node := nodes[p.ID]; node != nil {
+				entry.Native = node.isNative()
+			}
+			entry.UpdateAvailable = entry.InstalledVersion != "" && entry.Latest != nil &&
+				plugin_model.CompareVersions(entry.Latest.Version, entry.InstalledVersion) > 0
+
+			if !entry.matches(keyword, opts) {
+				continue
+			}
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+
+	slices.SortFunc(result.Entries, func(a, b *MarketEntry) int {
+		return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	for c := range categories {
+		result.Categories = append(result.Categories, c)
+	}
+	slices.Sort(result.Categories)
+	return result, nil
+}
+
+func (e *MarketEntry) matches(keyword string, opts MarketSearchOptions) bool {
+	if opts.Registry != "" && e.Registry != opts.Registry {
+		return false
+	}
+	if opts.Category != "" && !e.HasCategory(opts.Category) {
+		return false
+	}
+	if opts.UpdatesOnly && !e.UpdateAvailable {
+		return false
+	}
+	if keyword == "" {
+		return true
+	}
+	for _, s := range []string{e.ID, e.Name, e.Description, e.Author} {
+		if strings.Contains(strings.ToLower(s), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// RefreshMarket 立即刷新所有注册表的索引
+func (m *PluginManager) RefreshMarket(ctx context.Context) error {
+	return m.market.Refresh(ctx)
+}
+
+// registerMarketRefreshTask 注册定期刷新插件市场索引的定时任务，可在 [cron.plugin_market_refresh] 中配置
+func registerMarketRefreshTask() {
+	if err := cron.RegisterTask("plugin_market_refresh", &cron.BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 6h",
+	}, func(ctx context.Context, _ *user_model.User, _ cron.Config) error {
+		if m := GetManager(); m != nil {
+			return m.RefreshMarket(ctx)
+		}
+		return nil
+	}); err != nil {
+		log.Error("Unable to register plugin marketplace refresh task: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMarketIndex(t *testing.T, pkg []byte) []byte {
+	sum := sha256.Sum256(pkg)
+	data, err := json.Marshal(&plugin_model.MarketIndex{
+		Version: plugin_model.MarketIndexVersion,
+		Plugins: []*plugin_model.MarketPlugin{{
+			ID:         "demo",
+			Name:       "Demo",
+			Categories: []string{"Tools"},
+			Versions: []*plugin_model.MarketVersion{
+				{Version: "1.0.0", URL: "packages/demo-1.0.0.tar.gz", SHA256: hex.EncodeToString(sum[:])},
+				{Version: "1.1.0", URL: "packages/demo-1.1.0.tar.gz", SHA256: hex.EncodeToString(make([]byte, 32))},
+			},
+		}},
+	})
+	require.NoError(t, err)
+	return data
+}
+
+func TestMarketHTTPRegistry(t *testing.T) {
+	pkg := []byte("package content")
+	index := testMarketIndex(t, pkg)
+
+	var indexRequests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/market/index.json":
+			indexRequests++
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write(index)
+		case "/market/packages/demo-1.0.0.tar.gz", "/market/packages/demo-1.1.0.tar.gz":
+			_, _ = w.Write(pkg)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cacheDir := t.TempDir()
+	registries := []setting.PluginRegistry{{Name: "default", URL: srv.URL + "/market/index.json", Token: "secret"}}
+	mk := newMarket(cacheDir, registries)
+	ctx := context.Background()
+
+	r, mv, err := mk.find(ctx, "demo", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "default", r.Name)
+	assert.FileExists(t, filepath.Join(cacheDir, "market-default.json"))
+
+	_, _, err = mk.find(ctx, "demo", "2.0.0")
+	assert.True(t, plugin_model.IsErrMarketPluginNotExist(err))
+	_, _, err = mk.find(ctx, "unknown", "")
+	assert.True(t, plugin_model.IsErrMarketPluginNotExist(err))
+
+	dest := filepath.Join(t.TempDir(), "demo.tar.gz")
+	require.NoError(t, mk.download(ctx, r, mv, dest))
+	content, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, pkg, content)
+
+	// the checksum of 1.1.0 does not match, the file must not be kept
+	err = mk.download(ctx, r, r.cache.Index.Plugins[0].FindVersion("1.1.0"), dest)
+	assert.True(t, plugin_model.IsErrPackageVerification(err))
+	assert.NoFileExists(t, dest)
+
+	// refreshing sends the ETag and keeps the cached index on 304
+	require.NoError(t, mk.Refresh(ctx))
+	assert.Equal(t, 1, notModified)
+	assert.Equal(t, 2, indexRequests)
+
+	// a new market (e.g. after restart) reads the disk cache without fetching
+	mk2 := newMarket(cacheDir, registries)
+	require.NotNil(t, mk2.indexOf(ctx, mk2.registries[0]))
+	assert.Equal(t, 2, indexRequests)
+}
+
+func TestMarketLocalRegistry(t *testing.T) {
+	dir := t.TempDir()
+	pkg := []byte("local package")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "packages"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "packages", "demo-1.0.0.tar.gz"), pkg, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.json"), testMarketIndex(t, pkg), 0o644))
+
+	mk := newMarket(t.TempDir(), []setting.PluginRegistry{
+		{Name: "broken", URL: filepath.Join(dir, "missing.json")},
+		{Name: "local", URL: "file://" + filepath.ToSlash(filepath.Join(dir, "index.json"))},
+	})
+	ctx := context.Background()
+
+	r, mv, err := mk.find(ctx, "demo", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "local", r.Name)
+	assert.NotEmpty(t, mk.registries[0].lastError)
+
+	dest := filepath.Join(t.TempDir(), "demo.tar.gz")
+	require.NoError(t, mk.download(ctx, r, mv, dest))
+	assert.FileExists(t, dest)
+
+	assert.Error(t, mk.Refresh(ctx), "the broken registry is reported")
+	assert.NotNil(t, mk.indexOf(ctx, mk.registries[1]))
+}
+
+func TestMarketEntryMatches(t *testing.T) {
+	e := &MarketEntry{
+		MarketPlugin: &plugin_model.MarketPlugin{ID: "demo", Name: "Demo", Author: "Gitea", Categories: []string{"Tools"}},
+		Registry:     "default",
+	}
+	assert.True(t, e.matches("", MarketSearchOptions{}))
+	assert.True(t, e.matches("gitea", MarketSearchOptions{Category: "tools"}))
+	assert.False(t, e.matches("other", MarketSearchOptions{}))
+	assert.False(t, e.matches("", MarketSearchOptions{Registry: "internal"}))
+	assert.False(t, e.matches("", MarketSearchOptions{UpdatesOnly: true}))
+
+	e.UpdateAvailable = true
+	assert.True(t, e.matches("", MarketSearchOptions{UpdatesOnly: true}))
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// checkMigrations 检查迁移 ID 从 1 开始连续递增
+func checkMigrations(migrations []*plugin_model.Migration) error {
+	for i, m := range migrations {
+		if m == nil || m.Migrate == nil {
+			return fmt.Errorf("migration #%d is empty", i+1)
+		}
+		if m.ID != int64(i+1) {
+			return fmt.Errorf("migration #%d has id %d, ids must start at 1 and increase by 1", i+1, m.ID)
+		}
+	}
+	return nil
+}
+
+// checkSchemaVersion 检查插件的迁移不落后于数据库版本。表结构不会降级，
+// 迁移数量少于数据库版本的插件版本（如回滚到迁移之前的版本）不能运行
+func checkSchemaVersion(ctx context.Context, pluginID string, migrations []*plugin_model.Migration) (int64, error) {
+	current, err := plugin_model.GetSchemaVersion(ctx, pluginID)
+	if err != nil {
+		return 0, fmt.Errorf("get schema version: %w", err)
+	}
+	if latest := int64(len(migrations)); current > latest {
+		return 0, plugin_model.ErrPluginSchemaDowngrade{PluginID: pluginID, SchemaVersion: current, Migrations: latest}
+	}
+	return current, nil
+}
+
+// migratePlugin 按顺序执行插件尚未执行的迁移，每次迁移成功后更新插件的数据库版本。
+// 迁移使用的引擎拒绝修改插件以外的数据表（见 newMigrationEngine），执行后再检查没有创建前缀不符的表
+func migratePlugin(ctx context.Context, pluginID string, migrations []*plugin_model.Migration) error {
+	if err := checkMigrations(migrations); err != nil {
+		return err
+	}
+
+	current, err := checkSchemaVersion(ctx, pluginID, migrations)
+	if err != nil {
+		return err
+	}
+	if current == int64(len(migrations)) {
+		return nil
+	}
+
+	x, err := newMigrationEngine(ctx, pluginID)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations[current:] {
+		before, err := db.TableNames()
+		if err != nil {
+			return err
+		}
+
+		log.Info("Plugin %s migration[%d]: %s", pluginID, m.ID, m.Description)
+		if err := m.Migrate(ctx, x); err != nil {
+			return fmt.Errorf("migration[%d]: %s failed: %w", m.ID, m.Description, err)
+		}
+
+		after, err := db.TableNames()
+		if err != nil {
+			return err
+		}
+		for _, table := range after {
+			if !slices.Contains(before, table) && !plugin_model.OwnsTable(pluginID, table, nil) {
+				return plugin_model.ErrPluginTablePrefix{PluginID: pluginID, Table: table}
+			}
+		}
+
+		if err := plugin_model.SetSchemaVersion(ctx, pluginID, m.ID); err != nil {
+			return fmt.Errorf("set schema version: %w", err)
+		}
+	}
+	return nil
+}
+
+// PluginTables 列出属于插件的数据表
+func (m *PluginManager) PluginTables(ctx context.Context, pluginID string) ([]string, error) {
+	plugins, err := plugin_model.ListPlugins(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pluginIDs := make([]string, 0, len(plugins))
+	for _, p := range plugins {
+		pluginIDs = append(pluginIDs, p.PluginID)
+	}
+
+	tables, err := db.TableNames()
+	if err != nil {
+		return nil, err
+	}
+	var owned []string
+	for _, table := range tables {
+		if plugin_model.OwnsTable(pluginID, table, pluginIDs) {
+			owned = append(owned, table)
+		}
+	}
+	slices.Sort(owned)
+	return owned, nil
+}
+
+// purgePluginData 删除插件的数据表和数据库版本记录
+func (m *PluginManager) purgePluginData(ctx context.Context, pluginID string) error {
+	tables, err := m.PluginTables(ctx, pluginID)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if err := db.GetXORMEngine().DropTables(table); err != nil {
+			return fmt.Errorf("drop table %s: %w", table, err)
+		}
+		log.Info("Plugin %s table dropped: %s", pluginID, table)
+	}
+	return plugin_model.DeleteSchemaVersion(ctx, pluginID)
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+
+	"xorm.io/xorm"
+	"xorm.io/xorm/contexts"
+	"xorm.io/xorm/core"
+)
+
+// newMigrationEngine 创建执行插件迁移的 xorm 引擎。引擎与 Gitea 共用连接池，
+// 但有独立的 SQL 钩子：修改表结构或数据的语句只能作用于插件自己的数据表，在执行前检查
+func newMigrationEngine(ctx context.Context, pluginID string) (*xorm.Engine, error) {
+	x := db.GetXORMEngine()
+	coreDB := core.FromDB(x.DB().DB)
+	coreDB.AddHook(&migrationGuard{pluginID: pluginID})
+
+	e, err := xorm.NewEngineWithDialectAndDB(x.DriverName(), x.DataSourceName(), x.Dialect(), coreDB)
+	if err != nil {
+		return nil, err
+	}
+	// 连接池属于 Gitea 的引擎，回收这个引擎时不能关闭它
+	runtime.SetFinalizer(e, nil)
+	e.SetTableMapper(x.GetTableMapper())
+	e.SetColumnMapper(x.GetColumnMapper())
+	e.SetTZLocation(x.GetTZLocation())
+	e.SetTZDatabase(x.GetTZDatabase())
+	e.SetLogger(x.Logger())
+	e.SetDefaultContext(ctx)
+	return e, nil
+}
+
+// migrationGuard 拒绝插件迁移中作用于其他数据表的写语句
+type migrationGuard struct {
+	pluginID string
+}
+
+var _ contexts.Hook = (*migrationGuard)(nil)
+
+func (g *migrationGuard) BeforeProcess(c *contexts.ContextHook) (context.Context, error) {
+	for _, stmt := range splitStatements(c.SQL) {
+		tables, err := statementTables(stmt)
+		if err != nil {
+			return nil, plugin_model.ErrPermissionDenied{PluginID: g.pluginID, Permission: "migration statement: " + err.Error()}
+		}
+		for _, table := range tables {
+			if !plugin_model.OwnsTable(g.pluginID, table, nil) {
+				return nil, plugin_model.ErrPluginTablePrefix{PluginID: g.pluginID, Table: table}
+			}
+		}
+	}
+	return c.Ctx, nil
+}
+
+func (g *migrationGuard) AfterProcess(*contexts.ContextHook) error {
+	return nil
+}
+
+// 不修改表结构和数据的语句
+var migrationReadOnlyPattern = regexp.MustCompile(`(?i)^(SELECT|PRAGMA|SHOW|DESCRIBE|DESC|EXPLAIN|BEGIN|START\s+TRANSACTION|COMMIT|ROLLBACK|SAVEPOINT|RELEASE)\b`)
+
+// SELECT ... INTO 会创建新表或写入文件
+var migrationSelectIntoPattern = regexp.MustCompile(`(?i)\bINTO\b`)
+
+// 可能带引号和模式名的标识符
+const migrationIdent = "((?:[`\"\\[]?[\\w$]+[`\"\\]]?\\.)?[`\"\\[]?[\\w$]+[`\"\\]]?)"
+
+// 写语句及其作用的数据表（或索引）所在的分组
+var migrationWritePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?is)^CREATE\s+(?:TEMPORARY\s+|TEMP\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?` + migrationIdent),
+	regexp.MustCompile(`(?is)^IF\s+NOT\s+EXISTS\s*\(.*?\)\s*CREATE\s+TABLE\s+` + migrationIdent),
+	regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?` + migrationIdent),
+	regexp.MustCompile(`(?is)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?` + migrationIdent),
+	regexp.MustCompile(`(?is)^TRUNCATE\s+(?:TABLE\s+)?` + migrationIdent),
+	regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(?:IF\s+NOT\s+EXISTS\s+)?\S+\s+ON\s+` + migrationIdent),
+	regexp.MustCompile(`(?is)^DROP\s+INDEX\s+(?:IF\s+EXISTS\s+)?\S+\s+ON\s+` + migrationIdent),
+	regexp.MustCompile(`(?is)^(?:INSERT|REPLACE)\s+(?:OR\s+\w+\s+|IGNORE\s+)?INTO\s+` + migrationIdent),
+	regexp.MustCompile(`(?is)^UPDATE\s+(?:OR\s+\w+\s+)?` + migrationIdent),
+	regexp.MustCompile(`(?is)^DELETE\s+FROM\s+` + migrationIdent),
+}
+
+// 不带 ON 的 DROP INDEX（SQLite、PostgreSQL），xorm 的索引名为 IDX_<表名>_<索引名> 或 UQE_<表名>_<索引名>
+var migrationDropIndexPattern = regexp.MustCompile(`(?is)^DROP\s+INDEX\s+(?:IF\s+EXISTS\s+)?` + migrationIdent + `\s*$`)
+
+// statementTables 返回语句修改的数据表，只读语句返回空，无法识别的语句返回错误
+func statementTables(stmt string) ([]string, error) {
+	if migrationReadOnlyPattern.MatchString(stmt) {
+		if strings.HasPrefix(strings.ToUpper(stmt), "SELECT") && migrationSelectIntoPattern.MatchString(stmt) {
+			return nil, fmt.Errorf("SELECT INTO is not allowed: %s", stmt)
+		}
+		return nil, nil
+	}
+	if m := migrationDropIndexPattern.FindStringSubmatch(stmt); m != nil {
+		name := unquoteIdent(m[1])
+		for _, prefix := range []string{"IDX_", "UQE_"} {
+			if table, ok := strings.CutPrefix(name, prefix); ok {
+				return []string{table}, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot tell the table of index %s", name)
+	}
+	for _, pattern := range migrationWritePatterns {
+		if m := pattern.FindStringSubmatch(stmt); m != nil {
+			return []string{unquoteIdent(m[1])}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported statement: %s", stmt)
+}
+
+// unquoteIdent 去掉标识符的引号和模式名
+func unquoteIdent(ident string) string {
+	if i := strings.LastIndexByte(ident, '.'); i >= 0 {
+		ident = ident[i+1:]
+	}
+	return strings.Trim(ident, "`\"[]")
+}
+
+// splitStatements 按分号拆分 SQL 并去掉注释，引号内的分号和注释符号不拆分
+func splitStatements(sql string) []string {
+	var stmts []string
+	var b strings.Builder
+	var quote byte
+	flush := func() {
+		if s := strings.TrimSpace(b.String()); s != "" {
+			stmts = append(stmts, s)
+		}
+		b.Reset()
+	}
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '-' && strings.HasPrefix(sql[i:], "--"):
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(sql)
+			}
+			b.WriteByte(' ')
+			continue
+		case ch == '/' && strings.HasPrefix(sql[i:], "/*"):
+			if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(sql)
+			}
+			b.WriteByte(' ')
+			continue
+		case ch == ';':
+			flush()
+			continue
+		}
+		b.WriteByte(ch)
+	}
+	flush()
+	return stmts
+}
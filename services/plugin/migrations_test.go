@@ -0,0 +1,164 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+func TestCheckMigrations(t *testing.T) {
+	noop := func(context.Context, *xorm.Engine) error { return nil }
+
+	assert.NoError(t, checkMigrations(nil))
+	assert.NoError(t, checkMigrations([]*plugin_model.Migration{
+		plugin_model.NewMigration(1, "create table", noop),
+		plugin_model.NewMigration(2, "add column", noop),
+	}))
+
+	assert.ErrorContains(t, checkMigrations([]*plugin_model.Migration{
+		plugin_model.NewMigration(2, "add column", noop),
+	}), "must start at 1")
+	assert.ErrorContains(t, checkMigrations([]*plugin_model.Migration{
+		plugin_model.NewMigration(1, "create table", noop),
+		plugin_model.NewMigration(3, "add column", noop),
+	}), "increase by 1")
+	assert.ErrorContains(t, checkMigrations([]*plugin_model.Migration{
+		plugin_model.NewMigration(1, "create table", nil),
+	}), "empty")
+}
+
+type migrationTestItem struct {
+	ID   int64 `xorm:"pk autoincr"`
+	Name string
+}
+
+func (migrationTestItem) TableName() string {
+	return "plugin_migration_test_item"
+}
+
+// migrationTestUser 与 user 表同名，用于尝试通过 Sync 修改核心表结构
+type migrationTestUser struct {
+	ID     int64 `xorm:"pk autoincr"`
+	Hacked string
+}
+
+func (migrationTestUser) TableName() string {
+	return "user"
+}
+
+func TestMigratePluginGuard(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	const pluginID = "migration-test"
+	defer func() { _ = db.GetXORMEngine().DropTables(new(migrationTestItem)) }()
+	require.NoError(t, migratePlugin(t.Context(), pluginID, []*plugin_model.Migration{
+		plugin_model.NewMigration(1, "create table", func(ctx context.Context, x *xorm.Engine) error {
+			return x.Sync(new(migrationTestItem))
+		}),
+		plugin_model.NewMigration(2, "insert and index", func(ctx context.Context, x *xorm.Engine) error {
+			if _, err := x.Insert(&migrationTestItem{Name: "a"}); err != nil {
+				return err
+			}
+			if _, err := x.Exec("CREATE INDEX `IDX_plugin_migration_test_item_name` ON `plugin_migration_test_item` (`name`)"); err != nil {
+				return err
+			}
+			_, err := x.Exec("DROP INDEX `IDX_plugin_migration_test_item_name`")
+			return err
+		}),
+	}))
+	// 迁移引擎回收后 Gitea 的连接池仍可用
+	runtime.GC()
+	version, err := plugin_model.GetSchemaVersion(t.Context(), pluginID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, version)
+
+	admins, err := db.GetEngine(t.Context()).Where("is_admin = ?", true).Count(new(user_model.User))
+	require.NoError(t, err)
+	for name, migrate := range map[string]func(ctx context.Context, x *xorm.Engine) error{
+		"sync": func(ctx context.Context, x *xorm.Engine) error {
+			return x.Sync(new(migrationTestUser))
+		},
+		"alter": func(ctx context.Context, x *xorm.Engine) error {
+			_, err := x.Exec("ALTER TABLE `user` ADD COLUMN hacked TEXT")
+			return err
+		},
+		"update": func(ctx context.Context, x *xorm.Engine) error {
+			_, err := x.Exec("UPDATE `user` SET is_admin = ?", true)
+			return err
+		},
+		"drop index": func(ctx context.Context, x *xorm.Engine) error {
+			_, err := x.Exec("DROP INDEX `IDX_user_created_unix`")
+			return err
+		},
+		"second statement": func(ctx context.Context, x *xorm.Engine) error {
+			_, err := x.Exec("SELECT 1; /* ; */ DELETE FROM `user`")
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := migratePlugin(t.Context(), "migration-core-test", []*plugin_model.Migration{
+				plugin_model.NewMigration(1, name, migrate),
+			})
+			assert.ErrorContains(t, err, "plugin_id: migration-core-test")
+			version, err := plugin_model.GetSchemaVersion(t.Context(), "migration-core-test")
+			require.NoError(t, err)
+			assert.Zero(t, version)
+
+			columns, err := db.GetXORMEngine().DBMetas()
+			require.NoError(t, err)
+			for _, table := range columns {
+				if table.Name == "user" {
+					assert.Nil(t, table.GetColumn("hacked"))
+				}
+			}
+			count, err := db.GetEngine(t.Context()).Where("is_admin = ?", true).Count(new(user_model.User))
+			require.NoError(t, err)
+			assert.Equal(t, admins, count)
+			unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+		})
+	}
+}
+
+func TestStatementTables(t *testing.T) {
+	for stmt, tables := range map[string][]string{
+		"SELECT * FROM `user`":                                   nil,
+		"PRAGMA table_info(`user`)":                              nil,
+		"CREATE TABLE IF NOT EXISTS `plugin_a_t` (`id` INTEGER)": {"plugin_a_t"},
+		"IF NOT EXISTS (SELECT [name] FROM sys.tables WHERE [name] = 'x' ) CREATE TABLE [x] ([id] INT)": {"x"},
+		`ALTER TABLE "public"."plugin_a_t" ADD "name" TEXT`:                                             {"plugin_a_t"},
+		"CREATE UNIQUE INDEX `UQE_plugin_a_t_name` ON `plugin_a_t` (`name`)":                            {"plugin_a_t"},
+		"DROP INDEX `IDX_plugin_a_t_name` ON `plugin_a_t`":                                              {"plugin_a_t"},
+		`DROP INDEX "IDX_plugin_a_t_name"`:                                                              {"plugin_a_t_name"},
+		"INSERT OR REPLACE INTO `plugin_a_t` (`id`) VALUES (?)":                                         {"plugin_a_t"},
+		"DELETE FROM `plugin_a_t` WHERE `id` = ?":                                                       {"plugin_a_t"},
+		"TRUNCATE TABLE `plugin_a_t`":                                                                   {"plugin_a_t"},
+	} {
+		actual, err := statementTables(stmt)
+		require.NoError(t, err, stmt)
+		assert.Equal(t, tables, actual, stmt)
+	}
+
+	for _, stmt := range []string{
+		"SELECT * INTO `plugin_a_copy` FROM `user`",
+		"GRANT ALL ON `user` TO someone",
+		"DROP INDEX `some_index`",
+		"WITH d AS (DELETE FROM `user` RETURNING *) SELECT 1",
+	} {
+		_, err := statementTables(stmt)
+		assert.Error(t, err, stmt)
+	}
+
+	assert.Equal(t, []string{"SELECT ';'", "DELETE FROM `user`"}, splitStatements("SELECT ';'; -- comment ;\nDELETE FROM `user`;"))
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// PermissionRequest 插件声明的权限及其批准状态，用于授权页面
+type PermissionRequest struct {
+	Plugin     *plugin_model.Plugin
+	Declared   []string
+	Unapproved []string
+}
+
+// PermissionRequest 获取插件声明的权限（以当前安装版本的 plugin.json 为准）
+func (m *PluginManager) PermissionRequest(ctx context.Context, pluginID string) (*PermissionRequest, error) {
+	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := m.loader.readMetadata(m.loader.pluginPath(dbPlugin))
+	if err != nil {
+		return nil, fmt.Errorf("read metadata: %w", err)
+	}
+	return &PermissionRequest{
+		Plugin:     dbPlugin,
+		Declared:   metadata.Permissions,
+		Unapproved: dbPlugin.UnapprovedPermissions(metadata.Permissions),
+	}, nil
+}
+
+// ApprovePermissions 批准插件当前版本声明的全部权限，之前批准但不再声明的权限会被撤销
+func (m *PluginManager) ApprovePermissions(ctx context.Context, doer *user_model.User, pluginID string) error {
+	req, err := m.PermissionRequest(ctx, pluginID)
+	if err != nil {
+		return err
+	}
+	req.Plugin.Permissions = slices.Clone(req.Declared)
+	if err := plugin_model.UpdatePlugin(ctx, req.Plugin); err != nil {
+		return fmt.Errorf("update database: %w", err)
+	}
+	writeAudit(ctx, doer, &plugin_model.Audit{
+		PluginID:   pluginID,
+		Action:     plugin_model.AuditActionApprovePermissions,
+		NewVersion: req.Plugin.Version,
+		Detail:     strings.Join(req.Declared, ", "),
+	})
+	log.Info("Plugin permissions approved: %s %v", pluginID, req.Declared)
+	return nil
+}
+
+// PendingPermissions 返回声明了未批准权限的插件及这些权限
+func (m *PluginManager) PendingPermissions(ctx context.Context) (map[string][]string, error) {
+	nodes, err := m.loader.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[string][]string)
+	for id, node := range nodes {
+		if node.Metadata == nil {
+			continue
+		}
+		if unapproved := node.Plugin.UnapprovedPermissions(node.Metadata.Permissions); len(unapproved) > 0 {
+			pending[id] = unapproved
+		}
+	}
+	return pending, nil
+}
+
+// checkPermissionsApproved 检查插件声明的权限是否已全部批准
+func checkPermissionsApproved(dbPlugin *plugin_model.Plugin, metadata *plugin_model.PluginMetadata) error {
+	if unapproved := dbPlugin.UnapprovedPermissions(metadata.Permissions); len(unapproved) > 0 {
+		return plugin_model.ErrPermissionsNotApproved{PluginID: dbPlugin.PluginID, Permissions: unapproved}
+	}
+	return nil
+}
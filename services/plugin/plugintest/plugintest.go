@@ -0,0 +1,154 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package plugintest 在进程内测试插件：启动带测试数据的数据库，按正式加载流程初始化插件并挂载路由，
+// 再以指定用户的身份发送请求。
+//
+//	func TestMain(m *testing.M) {
+//		plugintest.MainTest(m)
+//	}
+//
+//	func TestList(t *testing.T) {
+//		pt := plugintest.Mount(t, &Plugin, plugintest.Options{})
+//		resp := pt.Request(unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2}), "GET", "/api/v1/xxx", nil)
+//		assert.Equal(t, http.StatusOK, resp.Code)
+//	}
+package plugintest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	plugin_service "code.gitea.io/gitea/services/plugin"
+
+	"github.com/stretchr/testify/require"
+)
+
+// MainTest 在插件测试包的 TestMain 中调用，创建测试数据库并加载 models/fixtures 中的测试数据
+func MainTest(m *testing.M) {
+	unittest.MainTest(m)
+}
+
+// Options 挂载插件的选项
+type Options struct {
+	Permissions []string       // 批准的权限，为 nil 时批准 Info 中声明的全部权限
+	Config      map[string]any // 插件配置，未设置的项使用 config_schema 中的默认值
+	Disabled    bool           // 以禁用状态挂载，请求将返回 503
+}
+
+// Plugin 已挂载的测试插件
+type Plugin struct {
+	t      testing.TB
+	ID     string
+	Plugin plugin_model.IPlugin
+}
+
+// Mount 重置测试数据库，按正式加载流程执行插件的迁移、Init、配置下发和模型同步，再挂载路由。
+// 插件的数据表在每次挂载时清空，测试结束时自动卸载
+func Mount(t testing.TB, p plugin_model.IPlugin, opts Options) *Plugin {
+	t.Helper()
+	require.NoError(t, unittest.PrepareTestDatabase())
+	ctx := t.Context()
+
+	info := p.Info()
+	require.NotEmpty(t, info.ID, "plugin info must have an id")
+	metadata := &plugin_model.PluginMetadata{
+		ID:           info.ID,
+		Name:         info.Name,
+		Version:      info.Version,
+		Permissions:  info.Permissions,
+		ConfigSchema: info.ConfigSchema,
+		Hooks:        map[string]bool{"routes": info.HasRoutes, "api": info.HasAPI, "models": true, "templates": info.HasTemplates},
+	}
+	permissions := opts.Permissions
+	if permissions == nil {
+		permissions = info.Permissions
+	}
+
+	require.NoError(t, plugin_model.DeletePlugin(ctx, info.ID))
+	dbPlugin := &plugin_model.Plugin{
+		PluginID:    info.ID,
+		Name:        info.Name,
+		Version:     info.Version,
+		IsInstalled: true,
+		IsEnabled:   !opts.Disabled,
+		Permissions: permissions,
+	}
+	fields := plugin_model.ParseConfigFields(info.ConfigSchema)
+	require.NoError(t, dbPlugin.SetConfig(fields, setting.SecretKey, opts.Config))
+	require.NoError(t, plugin_model.CreatePlugin(ctx, dbPlugin))
+
+	require.NoError(t, plugin_service.InitPlugin(ctx, info.ID, p, metadata))
+	if models := p.RegisterModels(); len(models) > 0 {
+		require.NoError(t, db.TruncateBeans(ctx, models...))
+	}
+
+	plugin_service.GetRouter().Mount(ctx, info.ID, p, !opts.Disabled)
+	if !opts.Disabled {
+		require.NoError(t, p.Enable())
+	}
+	t.Cleanup(func() {
+		plugin_service.GetRouter().Unmount(context.Background(), info.ID)
+	})
+	return &Plugin{t: t, ID: info.ID, Plugin: p}
+}
+
+// Request 以 doer 的身份发送请求，doer 为 nil 表示未登录。以 /api/ 开头的地址交给插件的 API 路由，
+// 其余交给 Web 路由。Web 路由渲染的模板不经过 Gitea 的页面布局，可用 Template 取得模板名和数据
+func (pt *Plugin) Request(doer *user_model.User, method, url string, body io.Reader) *httptest.ResponseRecorder {
+	pt.t.Helper()
+	req := httptest.NewRequestWithContext(pt.t.Context(), method, url, body)
+
+	info := &plugin_model.RequestInfo{Lang: "en-US"}
+	if doer != nil {
+		info.Doer = plugin_service.ToHostUser(doer)
+	}
+	ctx := plugin_model.WithRequestInfo(req.Context(), info)
+
+	router := plugin_service.GetRouter()
+	handler := router.WebHandler(nil, http.NotFoundHandler())
+	if strings.HasPrefix(req.URL.Path, "/api/") {
+		handler = router.APIHandler(nil, http.NotFoundHandler())
+	} else {
+		ctx = plugin_model.WithHTMLRenderer(ctx, plugin_model.WriteTemplateResponse)
+	}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req.WithContext(ctx))
+	return resp
+}
+
+// RequestJSON 以 JSON 格式发送请求体
+func (pt *Plugin) RequestJSON(doer *user_model.User, method, url string, v any) *httptest.ResponseRecorder {
+	pt.t.Helper()
+	body, err := json.Marshal(v)
+	require.NoError(pt.t, err)
+	return pt.Request(doer, method, url, bytes.NewReader(body))
+}
+
+// DecodeJSON 解析 JSON 响应
+func DecodeJSON(t testing.TB, resp *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), v), "response: %s", resp.Body.String())
+}
+
+// Template 返回 Web 路由渲染的模板名和模板数据，响应不是模板时测试失败
+func Template(t testing.TB, resp *httptest.ResponseRecorder) (string, map[string]any) {
+	t.Helper()
+	name := resp.Header().Get(plugin_model.TemplateHeader)
+	require.NotEmpty(t, name, "response is not a template: %s", resp.Body.String())
+	var data map[string]any
+	DecodeJSON(t, resp, &data)
+	return name, data
+}
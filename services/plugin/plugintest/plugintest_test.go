@@ -0,0 +1,114 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugintest_test
+
+import (
+	"net/http"
+	"testing"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/pluginsdk"
+	"code.gitea.io/gitea/services/plugin/plugintest"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	plugintest.MainTest(m)
+}
+
+type sdkNote struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64
+	Text   string
+}
+
+func (sdkNote) TableName() string { return "plugin_sdk_test_note" }
+
+type sdkPlugin struct {
+	pluginsdk.Base
+}
+
+func (p *sdkPlugin) Info() *plugin_model.PluginInfo {
+	return &plugin_model.PluginInfo{
+		ID:          "sdk-test",
+		Name:        "SDK Test",
+		Version:     "1.0.0",
+		Permissions: []string{plugin_model.PermissionRepoRead, plugin_model.PermissionDatabaseRead, plugin_model.PermissionDatabaseWrite},
+		ConfigSchema: map[string]any{"properties": map[string]any{
+			"greeting": map[string]any{"type": "string", "default": "hello"},
+		}},
+	}
+}
+
+func (p *sdkPlugin) RegisterModels() []any {
+	return []any{new(sdkNote)}
+}
+
+func (p *sdkPlugin) RegisterRoutes(r chi.Router) {
+	r.Get("/sdk-test/{owner}/{repo}", p.Handle(func(ctx *pluginsdk.Context) {
+		ctx.HTML(http.StatusOK, "repo", map[string]any{"Repo": ctx.Repo.Name, "Greeting": p.ConfigString("greeting", "")})
+	}))
+}
+
+func (p *sdkPlugin) RegisterAPIRoutes(r chi.Router) {
+	r.Post("/api/v1/sdk-test/{owner}/{repo}/notes", p.Handle(func(ctx *pluginsdk.Context) {
+		if !ctx.IsSigned() {
+			ctx.APIError(http.StatusUnauthorized, "sign in required")
+			return
+		}
+		note := &sdkNote{RepoID: ctx.Repo.ID, Text: ctx.Doer.Name + ": " + ctx.FormString("text")}
+		if err := p.Host.DB().Insert(ctx, note); err != nil {
+			ctx.ServerError("Insert", err)
+			return
+		}
+		ctx.JSON(http.StatusCreated, note)
+	}))
+}
+
+func TestMount(t *testing.T) {
+	pt := plugintest.Mount(t, &sdkPlugin{}, plugintest.Options{Config: map[string]any{"greeting": "hi"}})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	resp := pt.Request(nil, "GET", "/sdk-test/user2/repo1", nil)
+	require.Equal(t, http.StatusOK, resp.Code)
+	name, data := plugintest.Template(t, resp)
+	assert.Equal(t, "repo", name)
+	assert.Equal(t, "repo1", data["Repo"])
+	assert.Equal(t, "hi", data["Greeting"])
+
+	// 私有仓库对匿名用户不存在
+	resp = pt.Request(nil, "GET", "/sdk-test/user2/repo2", nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	resp = pt.Request(user2, "GET", "/sdk-test/user2/repo2", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = pt.Request(nil, "POST", "/api/v1/sdk-test/user2/repo1/notes?text=x", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	resp = pt.Request(user2, "POST", "/api/v1/sdk-test/user2/repo1/notes?text=first", nil)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+	var note sdkNote
+	plugintest.DecodeJSON(t, resp, &note)
+	assert.EqualValues(t, 1, note.RepoID)
+	assert.Equal(t, "user2: first", note.Text)
+	unittest.AssertExistsAndLoadBean(t, &sdkNote{ID: note.ID, Text: "user2: first"})
+
+	resp = pt.Request(user2, "POST", "/api/v1/sdk-test/user2/missing/notes", nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	var apiErr map[string]string
+	plugintest.DecodeJSON(t, resp, &apiErr)
+	assert.Equal(t, "Not Found", apiErr["message"])
+}
+
+func TestMountPermissions(t *testing.T) {
+	pt := plugintest.Mount(t, &sdkPlugin{}, plugintest.Options{Permissions: []string{}})
+
+	// 未批准 repo.read 时无法加载仓库
+	resp := pt.Request(nil, "GET", "/sdk-test/user2/repo1", nil)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+}
@@ -0,0 +1,775 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/pluginrpc"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	processStartTimeout   = 10 * time.Second // 启动插件进程到完成握手的最长时间
+	processRestartMinWait = time.Second
+	processRestartMaxWait = time.Minute
+	processMaxBodySize    = int64(32 << 20)
+	processRequestTimeout = time.Minute // 插件处理一个 HTTP 请求的最长时间
+	// processMaxMessageSize 插件返回的单条 RPC 消息的最大大小，HTTP 响应体经 base64 编码后约为原大小的 4/3
+	processMaxMessageSize = 2 * processMaxBodySize
+)
+
+// processStrippedHeaders 不转发给插件进程的请求头，插件通过 RequestInfo 获取当前用户，不需要会话和令牌
+var processStrippedHeaders = []string{"Cookie", "Authorization", "Proxy-Authorization"}
+
+// ErrPluginProcessUnavailable 插件进程未运行（崩溃后等待重启或已停止）
+var ErrPluginProcessUnavailable = errors.New("plugin process is not running")
+
+// processPlugin 以子进程方式运行的插件，实现 IPlugin 并将调用代理到插件进程
+type processPlugin struct {
+	pluginID   string
+	pluginPath string
+	metadata   *plugin_model.PluginMetadata
+
+	hostDir      string
+	hostSocket   string
+	hostListener net.Listener
+
+	mu          sync.RWMutex
+	cmd         *exec.Cmd
+	client      *pluginrpc.Client
+	info        *plugin_model.PluginInfo
+	routes      []pluginrpc.Route
+	events      []string
+	slots       []string
+	cronTasks   []*plugin_model.CronTask
+	config      map[string]any
+	initialized bool
+	enabled     bool
+	stopped     bool
+	backoff     time.Duration
+	stopCh      chan struct{}
+}
+
+// newProcessPlugin 启动插件进程并完成握手
+func newProcessPlugin(pluginID, pluginPath string, metadata *plugin_model.PluginMetadata) (*processPlugin, error) {
+	p := &processPlugin{
+		pluginID:   pluginID,
+		pluginPath: pluginPath,
+		metadata:   metadata,
+		stopCh:     make(chan struct{}),
+	}
+	if err := p.serveHost(); err != nil {
+		return nil, err
+	}
+	if err := p.start(); err != nil {
+		p.closeHost()
+		return nil, err
+	}
+	return p, nil
+}
+
+// serveHost 为插件进程提供宿主能力服务，监听在仅 Gitea 用户可访问的临时目录中，
+// 插件进程每次（重新）启动时通过 EnvHostSocket 连接
+func (p *processPlugin) serveHost() error {
+	dir, err := os.MkdirTemp("", "gitea-plugin-host-")
+	if err != nil {
+		return err
+	}
+	socketPath := filepath.Join(dir, "host.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("listen %s: %w", socketPath, err)
+	}
+
+	p.hostDir, p.hostSocket, p.hostListener = dir, socketPath, listener
+	go func() {
+		if err := pluginrpc.ServeHost(graceful.GetManager().ShutdownContext(), listener, newPluginHost(p.pluginID), setting.PluginMaxHTTPResponseSize<<20); err != nil {
+			log.Error("Plugin host service for %s stopped: %v", p.pluginID, err)
+		}
+	}()
+	return nil
+}
+
+func (p *processPlugin) closeHost() {
+	if p.hostListener != nil {
+		_ = p.hostListener.Close()
+	}
+	if p.hostDir != "" {
+		_ = os.RemoveAll(p.hostDir)
+	}
+}
+
+// executablePath 获取插件可执行文件路径，不存在时尝试从源码编译
+func (p *processPlugin) executablePath() (string, error) {
+	name := p.metadata.Executable
+	if name == "" {
+		name = "plugin"
+	}
+	exePath := filepath.Join(p.pluginPath, name)
+	if fileExists(exePath) {
+		return exePath, nil
+	}
+
+	log.Info("Building plugin executable: %s", p.pluginID)
+	cmd := exec.Command("go", "build", "-o", name, ".")
+	cmd.Dir = p.pluginPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("build failed: %s\n%s", err, output)
+	}
+	return exePath, nil
+}
+
+// start 启动插件进程并握手，调用方无需持有锁
+func (p *processPlugin) start() error {
+	exePath, err := p.executablePath()
+	if err != nil {
+		return err
+	}
+
+	env, err := p.environ()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(exePath)
+	cmd.Dir = p.pluginPath
+	cmd.Env = env
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	var conn io.ReadWriteCloser
+	switch p.metadata.GetTransport() {
+	case plugin_model.TransportStdio:
+		conn, err = p.startStdio(cmd)
+	case plugin_model.TransportUnix:
+		conn, err = p.startUnix(cmd)
+	default:
+		return fmt.Errorf("unknown transport: %s", p.metadata.Transport)
+	}
+	if err != nil {
+		return err
+	}
+
+	go p.forwardLog(stderr)
+
+	client := pluginrpc.NewClient(conn, processMaxMessageSize)
+	hs, err := handshake(client)
+	if err != nil {
+		_ = client.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+
+	p.mu.Lock()
+	p.cmd = cmd
+	p.client = client
+	p.info = hs.Info
+	p.routes = hs.Routes
+	p.events = hs.Events
+	p.slots = hs.Slots
+	p.cronTasks = hs.CronTasks
+	p.mu.Unlock()
+
+	go p.supervise(cmd)
+
+	log.Info("Plugin process started: %s (pid %d)", p.pluginID, cmd.Process.Pid)
+	return nil
+}
+
+// handshake 与插件进程握手，插件在 processStartTimeout 内没有响应时返回错误，由调用方结束进程
+func handshake(client *pluginrpc.Client) (*pluginrpc.HandshakeReply, error) {
+	type result struct {
+		hs  *pluginrpc.HandshakeReply
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		hs, err := client.Handshake()
+		done <- result{hs, err}
+	}()
+	select {
+	case r := <-done:
+		return r.hs, r.err
+	case <-time.After(processStartTimeout):
+		return nil, fmt.Errorf("plugin did not complete handshake within %v", processStartTimeout)
+	}
+}
+
+// environ 插件进程的环境变量。不继承 Gitea 的环境变量（其中可能有 GITEA__ 开头的配置和密钥），
+// HOME 指向插件专属的数据目录
+func (p *processPlugin) environ() ([]string, error) {
+	dataDir := pluginDataDir(p.pluginID)
+	if err := os.MkdirAll(dataDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	env := []string{
+		"HOME=" + dataDir,
+		"TMPDIR=" + os.TempDir(),
+		pluginrpc.EnvProtocolVersion + "=" + strconv.Itoa(pluginrpc.ProtocolVersion),
+		pluginrpc.EnvPluginID + "=" + p.pluginID,
+		pluginrpc.EnvHostSocket + "=" + p.hostSocket,
+		pluginrpc.EnvDataDir + "=" + dataDir,
+	}
+	for _, key := range []string{"PATH", "SYSTEMROOT", "TZ"} {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env, nil
+}
+
+func (p *processPlugin) startStdio(cmd *exec.Cmd) (io.ReadWriteCloser, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start plugin process: %w", err)
+	}
+	return &pipeConn{ReadCloser: stdout, WriteCloser: stdin}, nil
+}
+
+// startUnix 启动插件进程并等待其连接。与 serveHost 相同，每次启动都在仅 Gitea 用户可访问的新临时目录中监听，
+// 其他本地用户无法抢先创建或监听该路径；插件连接后（或启动失败时）删除目录
+func (p *processPlugin) startUnix(cmd *exec.Cmd) (io.ReadWriteCloser, error) {
+	dir, err := os.MkdirTemp("", "gitea-plugin-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	socketPath := filepath.Join(dir, "plugin.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("listen %s: %w", socketPath, err)
+	}
+	defer listener.Close()
+
+	cmd.Env = append(cmd.Env, pluginrpc.EnvSocket+"="+socketPath)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start plugin process: %w", err)
+	}
+
+	_ = listener.(*net.UnixListener).SetDeadline(time.Now().Add(processStartTimeout))
+	conn, err := listener.Accept()
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("accept plugin connection: %w", err)
+	}
+	return conn, nil
+}
+
+// forwardLog 将插件进程的 stderr 输出写入 Gitea 日志
+func (p *processPlugin) forwardLog(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		log.Info("[plugin %s] %s", p.pluginID, scanner.Text())
+	}
+}
+
+// supervise 等待插件进程退出，非主动停止时按退避策略重启
+func (p *processPlugin) supervise(cmd *exec.Cmd) {
+	startedAt := time.Now()
+	err := cmd.Wait()
+
+	p.mu.Lock()
+	if p.cmd == cmd {
+		_ = p.client.Close()
+		p.client = nil
+		p.cmd = nil
+	}
+	stopped := p.stopped
+	p.mu.Unlock()
+
+	if stopped {
+		return
+	}
+	log.Error("Plugin process %s exited unexpectedly: %v", p.pluginID, err)
+	recordFailure(p.pluginID, HealthSourceProcess, fmt.Errorf("process exited unexpectedly: %w", err))
+
+	wait := p.nextBackoff(time.Since(startedAt))
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-time.After(wait):
+		}
+
+		if err := p.restart(); err != nil {
+			log.Error("Failed to restart plugin process %s: %v", p.pluginID, err)
+			wait = p.nextBackoff(0)
+			continue
+		}
+		return
+	}
+}
+
+// nextBackoff 计算下一次重启前的等待时间，进程稳定运行过一段时间后重置退避
+func (p *processPlugin) nextBackoff(uptime time.Duration) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.backoff == 0 || uptime >= processRestartMaxWait {
+		p.backoff = processRestartMinWait
+	} else {
+		p.backoff = min(p.backoff*2, processRestartMaxWait)
+	}
+	return p.backoff
+}
+
+// restart 重启插件进程，并恢复初始化、配置和启用状态。
+// 进程启动成功后恢复状态失败只记录日志，进程仍由 supervise 监管。
+func (p *processPlugin) restart() error {
+	if err := p.start(); err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	initialized, enabled, config := p.initialized, p.enabled, p.config
+	p.mu.RUnlock()
+
+	client, err := p.getClient()
+	if err != nil {
+		return nil
+	}
+	if initialized {
+		if err := client.Init(); err != nil {
+			log.Error("Failed to init restarted plugin %s: %v", p.pluginID, err)
+			return nil
+		}
+	}
+	if config != nil {
+		if err := client.SetConfig(config); err != nil {
+			log.Error("Failed to restore config of restarted plugin %s: %v", p.pluginID, err)
+		}
+	}
+	if enabled {
+		if err := client.Enable(); err != nil {
+			log.Error("Failed to enable restarted plugin %s: %v", p.pluginID, err)
+		}
+	}
+	recordStart(p.pluginID)
+	log.Info("Plugin process restarted: %s", p.pluginID)
+	return nil
+}
+
+func (p *processPlugin) getClient() (*pluginrpc.Client, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.client == nil {
+		return nil, ErrPluginProcessUnavailable
+	}
+	return p.client, nil
+}
+
+// Close 停止插件进程，不再重启
+func (p *processPlugin) Close() error {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return nil
+	}
+	p.stopped = true
+	close(p.stopCh)
+	cmd, client := p.cmd, p.client
+	p.mu.Unlock()
+
+	if client != nil {
+		_ = client.Close()
+	}
+	if cmd != nil && cmd.Process != nil {
+		_ = cmd.Process.Kill()
+	}
+	p.closeHost()
+	return nil
+}
+
+// kill 结束无响应或返回过大消息的插件进程，由 supervise 记录失败并按退避策略重启
+func (p *processPlugin) kill() {
+	p.mu.RLock()
+	cmd := p.cmd
+	p.mu.RUnlock()
+	if cmd != nil && cmd.Process != nil {
+		_ = cmd.Process.Kill()
+	}
+}
+
+// Info 获取插件信息（握手时缓存）
+func (p *processPlugin) Info() *plugin_model.PluginInfo {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.info
+}
+
+// Init 初始化插件，插件进程通过自己的宿主连接获取能力，host 参数不会跨进程传递
+func (p *processPlugin) Init(_ plugin_model.Host) error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	if err := client.Init(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.initialized = true
+	p.mu.Unlock()
+	return nil
+}
+
+// RegisterRoutes 为插件声明的 Web 路由注册代理处理器
+func (p *processPlugin) RegisterRoutes(r chi.Router) {
+	p.registerProxyRoutes(r, false)
+}
+
+// RegisterAPIRoutes 为插件声明的 API 路由注册代理处理器
+func (p *processPlugin) RegisterAPIRoutes(r chi.Router) {
+	p.registerProxyRoutes(r, true)
+}
+
+func (p *processPlugin) registerProxyRoutes(r chi.Router, api bool) {
+	p.mu.RLock()
+	routes := p.routes
+	p.mu.RUnlock()
+
+	for _, route := range routes {
+		if route.API != api {
+			continue
+		}
+		r.Method(route.Method, route.Pattern, p.proxyHandler(api))
+	}
+}
+
+// proxyHandler 将 HTTP 请求序列化后转发给插件进程
+func (p *processPlugin) proxyHandler(api bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client, err := p.getClient()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, processMaxBodySize))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		header := r.Header.Clone()
+		for _, key := range processStrippedHeaders {
+			header.Del(key)
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), processRequestTimeout)
+		defer cancel()
+		resp, err := client.ServeHTTP(ctx, &pluginrpc.HTTPRequest{
+			API:        api,
+			Method:     r.Method,
+			URL:        r.URL.RequestURI(),
+			Header:     header,
+			Body:       body,
+			RemoteAddr: r.RemoteAddr,
+			Info:       plugin_model.GetRequestInfo(r.Context()),
+		})
+		if err != nil {
+			if r.Context().Err() != nil {
+				return // 客户端已断开
+			}
+			log.Error("Plugin %s failed to serve %s %s: %v", p.pluginID, r.Method, r.URL.Path, err)
+			if errors.Is(err, context.DeadlineExceeded) {
+				// 插件没有响应，结束进程以免请求和禁用插件时的等待被一直占用
+				p.kill()
+				http.Error(w, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+				return
+			}
+			if errors.Is(err, pluginrpc.ErrMessageTooLarge) {
+				p.kill()
+			}
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+			return
+		}
+		if int64(len(resp.Body)) > processMaxBodySize {
+			log.Error("Plugin %s returned a response larger than %d bytes for %s %s", p.pluginID, processMaxBodySize, r.Method, r.URL.Path)
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+			return
+		}
+
+		// 插件请求渲染模板时，响应体为模板数据，由宿主使用 Gitea 的页面布局渲染
+		if name := resp.Header.Get(plugin_model.TemplateHeader); name != "" {
+			if render := plugin_model.GetHTMLRenderer(r.Context()); render != nil {
+				var data map[string]any
+				if err := json.Unmarshal(resp.Body, &data); err != nil {
+					log.Error("Plugin %s returned invalid template data for %s: %v", p.pluginID, name, err)
+					http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+					return
+				}
+				for k, vs := range resp.Header {
+					if k == plugin_model.TemplateHeader || k == "Content-Type" {
+						continue
+					}
+					for _, v := range vs {
+						w.Header().Add(k, v)
+					}
+				}
+				render(w, resp.StatusCode, name, data)
+				return
+			}
+		}
+
+		for k, vs := range resp.Header {
+			for _, v := range vs {
+				w.Header().Add(k, v)
+			}
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(resp.Body)
+	}
+}
+
+// RegisterModels process 插件无法与 Gitea 共享 Go 类型，不支持注册模型
+func (p *processPlugin) RegisterModels() []any {
+	return nil
+}
+
+// GetTemplatePath 获取模板路径
+func (p *processPlugin) GetTemplatePath() string {
+	return filepath.Join(p.pluginPath, "templates")
+}
+
+// GetAssetsPath 获取静态资源路径
+func (p *processPlugin) GetAssetsPath() string {
+	return filepath.Join(p.pluginPath, "assets")
+}
+
+// GetLocalePath 获取国际化文件路径
+func (p *processPlugin) GetLocalePath() string {
+	return filepath.Join(p.pluginPath, "locales")
+}
+
+// Enable 启用插件
+func (p *processPlugin) Enable() error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	if err := client.Enable(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.enabled = true
+	p.mu.Unlock()
+	return nil
+}
+
+// Disable 禁用插件
+func (p *processPlugin) Disable() error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	if err := client.Disable(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.enabled = false
+	p.mu.Unlock()
+	return nil
+}
+
+// Uninstall 卸载插件
+func (p *processPlugin) Uninstall() error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	return client.Uninstall()
+}
+
+// GetConfig 获取配置，进程不可用时返回最后一次设置的配置
+func (p *processPlugin) GetConfig() map[string]any {
+	if client, err := p.getClient(); err == nil {
+		if config, err := client.GetConfig(); err == nil {
+			return config
+		}
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config
+}
+
+// SetConfig 设置配置，进程重启后会重新下发
+func (p *processPlugin) SetConfig(config map[string]any) error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	if err := client.SetConfig(config); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.config = config
+	p.mu.Unlock()
+	return nil
+}
+
+// SubscribedEvents 返回插件在握手时声明的订阅事件
+func (p *processPlugin) SubscribedEvents() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.events
+}
+
+// HandleEvent 将事件转发给插件进程，ctx 结束时不再等待结果
+func (p *processPlugin) HandleEvent(ctx context.Context, event *plugin_model.Event) error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- client.HandleEvent(event)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HealthCheck 检查插件进程是否正常响应，进程未运行（等待重启）时视为失败
+func (p *processPlugin) HealthCheck(ctx context.Context) error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- client.HealthCheck()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CronTasks 返回插件在握手时声明的定时任务，执行请求转发给插件进程
+func (p *processPlugin) CronTasks() []*plugin_model.CronTask {
+	p.mu.RLock()
+	declared := p.cronTasks
+	p.mu.RUnlock()
+
+	tasks := make([]*plugin_model.CronTask, 0, len(declared))
+	for _, t := range declared {
+		if t == nil {
+			continue
+		}
+		task := *t
+		task.Run = func(ctx context.Context) error {
+			return p.runCronTask(ctx, task.Name)
+		}
+		tasks = append(tasks, &task)
+	}
+	return tasks
+}
+
+func (p *processPlugin) runCronTask(ctx context.Context, name string) error {
+	client, err := p.getClient()
+	if err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- client.RunCronTask(name)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RegisterSlots 注册插件在握手时声明的插槽，渲染请求转发给插件进程
+func (p *processPlugin) RegisterSlots(r plugin_model.SlotRegistry) {
+	p.mu.RLock()
+	slots := p.slots
+	p.mu.RUnlock()
+	for _, slot := range slots {
+		r.RegisterSlot(slot, p.renderSlot)
+	}
+}
+
+func (p *processPlugin) renderSlot(ctx context.Context, sc *plugin_model.SlotContext) (template.HTML, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return "", err
+	}
+	type result struct {
+		html string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		html, err := client.RenderSlot(sc)
+		done <- result{html, err}
+	}()
+	select {
+	case r := <-done:
+		return template.HTML(r.html), r.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// pipeConn 将子进程的 stdout/stdin 组合为一个连接
+type pipeConn struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+func (c *pipeConn) Close() error {
+	errW := c.WriteCloser.Close()
+	errR := c.ReadCloser.Close()
+	if errW != nil {
+		return errW
+	}
+	return errR
+}
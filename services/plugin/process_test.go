@@ -0,0 +1,258 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/pluginrpc"
+	"code.gitea.io/gitea/modules/pluginsdk"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const processTestHangID = "process-test-hang"
+
+// TestMain 测试二进制以插件进程方式启动时（设置了 EnvPluginID）运行测试插件
+func TestMain(m *testing.M) {
+	if id := os.Getenv(pluginrpc.EnvPluginID); id != "" {
+		if id == processTestHangID {
+			select {} // 不响应握手
+		}
+		if err := pluginrpc.Serve(&processTestPlugin{id: id}); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	unittest.MainTest(m)
+}
+
+// processTestPlugin 测试插件，版本取自工作目录（插件安装目录）中的 plugin.json
+type processTestPlugin struct {
+	pluginsdk.Base
+	id string
+}
+
+func (p *processTestPlugin) Info() *plugin_model.PluginInfo {
+	version := "1.0.0"
+	if data, err := os.ReadFile("plugin.json"); err == nil {
+		var metadata plugin_model.PluginMetadata
+		if json.Unmarshal(data, &metadata) == nil && metadata.Version != "" {
+			version = metadata.Version
+		}
+	}
+	return &plugin_model.PluginInfo{ID: p.id, Name: p.id, Version: version}
+}
+
+// Enable 同一插件的多个实例共用数据目录，另一个实例处于启用状态时拒绝启用
+func (p *processTestPlugin) Enable() error {
+	marker := filepath.Join(os.Getenv(pluginrpc.EnvDataDir), "enabled")
+	if _, err := os.Stat(marker); err == nil {
+		return errors.New("another instance is enabled")
+	}
+	return os.WriteFile(marker, nil, 0o644)
+}
+
+func (p *processTestPlugin) Disable() error {
+	return os.Remove(filepath.Join(os.Getenv(pluginrpc.EnvDataDir), "enabled"))
+}
+
+func (p *processTestPlugin) RegisterAPIRoutes(r chi.Router) {
+	r.Post("/api/v1/process-test/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"header": r.Header,
+			"size":   len(body),
+		})
+	})
+	r.Get("/api/v1/process-test/large", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", 2048)))
+	})
+	r.Get("/api/v1/process-test/hang", func(w http.ResponseWriter, r *http.Request) {
+		select {}
+	})
+}
+
+// newTestProcessPlugin 使用当前测试二进制作为插件可执行文件启动插件进程
+func newTestProcessPlugin(t *testing.T, id, transport string) (*processPlugin, error) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.Symlink(exe, filepath.Join(dir, "plugin")))
+
+	p, err := newProcessPlugin(id, dir, &plugin_model.PluginMetadata{ID: id, Transport: transport})
+	if err == nil {
+		t.Cleanup(func() { _ = p.Close() })
+	}
+	return p, err
+}
+
+func TestProcessPluginTransports(t *testing.T) {
+	defer test.MockVariableValue(&setting.PluginDataDir, t.TempDir())()
+
+	for _, transport := range []string{plugin_model.TransportStdio, plugin_model.TransportUnix} {
+		t.Run(transport, func(t *testing.T) {
+			p, err := newTestProcessPlugin(t, "process-test-"+transport, transport)
+			require.NoError(t, err)
+			assert.Equal(t, "process-test-"+transport, p.Info().ID)
+			assert.NoError(t, p.Init(nil))
+		})
+	}
+}
+
+func TestProcessPluginHandshakeTimeout(t *testing.T) {
+	defer test.MockVariableValue(&setting.PluginDataDir, t.TempDir())()
+	defer test.MockVariableValue(&processStartTimeout, 500*time.Millisecond)()
+
+	for _, transport := range []string{plugin_model.TransportStdio, plugin_model.TransportUnix} {
+		t.Run(transport, func(t *testing.T) {
+			start := time.Now()
+			_, err := newTestProcessPlugin(t, processTestHangID, transport)
+			require.Error(t, err)
+			assert.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
+
+func TestProcessPluginRestart(t *testing.T) {
+	defer test.MockVariableValue(&setting.PluginDataDir, t.TempDir())()
+	defer test.MockVariableValue(&processRestartMinWait, 10*time.Millisecond)()
+
+	p, err := newTestProcessPlugin(t, "process-test-restart", plugin_model.TransportStdio)
+	require.NoError(t, err)
+	require.NoError(t, p.Init(nil))
+	require.NoError(t, p.Enable())
+
+	p.mu.RLock()
+	oldCmd := p.cmd
+	p.mu.RUnlock()
+	require.NoError(t, oldCmd.Process.Kill())
+
+	assert.Eventually(t, func() bool {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		return p.cmd != nil && p.cmd != oldCmd && p.client != nil
+	}, 10*time.Second, 20*time.Millisecond)
+	client, err := p.getClient()
+	require.NoError(t, err)
+	assert.NoError(t, client.HealthCheck())
+
+	// 主动关闭后不再重启
+	require.NoError(t, p.Close())
+	assert.Eventually(t, func() bool {
+		_, err := p.getClient()
+		return err != nil
+	}, 10*time.Second, 20*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	_, err = p.getClient()
+	assert.ErrorIs(t, err, ErrPluginProcessUnavailable)
+}
+
+func TestProcessPluginNextBackoff(t *testing.T) {
+	defer test.MockVariableValue(&processRestartMinWait, time.Second)()
+	defer test.MockVariableValue(&processRestartMaxWait, 8*time.Second)()
+
+	p := &processPlugin{}
+	var waits []time.Duration
+	for range 6 {
+		waits = append(waits, p.nextBackoff(0))
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second, 8 * time.Second}, waits)
+
+	// 稳定运行超过最大等待时间后重置
+	assert.Equal(t, time.Second, p.nextBackoff(8*time.Second))
+	assert.Equal(t, 2*time.Second, p.nextBackoff(time.Second))
+}
+
+func TestProcessPluginProxy(t *testing.T) {
+	defer test.MockVariableValue(&setting.PluginDataDir, t.TempDir())()
+	defer test.MockVariableValue(&processMaxBodySize, int64(1024))()
+
+	p, err := newTestProcessPlugin(t, "process-test-proxy", plugin_model.TransportStdio)
+	require.NoError(t, err)
+	r := chi.NewRouter()
+	p.RegisterAPIRoutes(r)
+
+	t.Run("StripCredentials", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/process-test/echo", strings.NewReader("hello"))
+		req.Header.Set("Cookie", "i_like_gitea=secret")
+		req.Header.Set("Authorization", "token secret")
+		req.Header.Set("Proxy-Authorization", "Basic secret")
+		req.Header.Set("X-Custom", "value")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var data struct {
+			Header http.Header `json:"header"`
+			Size   int         `json:"size"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &data))
+		assert.Equal(t, 5, data.Size)
+		assert.Equal(t, "value", data.Header.Get("X-Custom"))
+		assert.Empty(t, data.Header.Get("Cookie"))
+		assert.Empty(t, data.Header.Get("Authorization"))
+		assert.Empty(t, data.Header.Get("Proxy-Authorization"))
+		// 原始请求的请求头不受影响
+		assert.Equal(t, "token secret", req.Header.Get("Authorization"))
+	})
+
+	t.Run("ResponseTooLarge", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/process-test/large", nil))
+		assert.Equal(t, http.StatusBadGateway, resp.Code)
+	})
+
+	t.Run("BodyTooLarge", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/process-test/echo", strings.NewReader(strings.Repeat("a", 2048)))
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	})
+}
+
+func TestProcessPluginProxyTimeout(t *testing.T) {
+	defer test.MockVariableValue(&setting.PluginDataDir, t.TempDir())()
+	defer test.MockVariableValue(&processRequestTimeout, 200*time.Millisecond)()
+	defer test.MockVariableValue(&processRestartMinWait, 10*time.Millisecond)()
+
+	p, err := newTestProcessPlugin(t, "process-test-timeout", plugin_model.TransportStdio)
+	require.NoError(t, err)
+	r := chi.NewRouter()
+	p.RegisterAPIRoutes(r)
+	p.mu.RLock()
+	oldCmd := p.cmd
+	p.mu.RUnlock()
+
+	start := time.Now()
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/process-test/hang", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, resp.Code)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// 无响应的插件进程被结束并重启
+	assert.Eventually(t, func() bool {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		return p.cmd != nil && p.cmd != oldCmd && p.client != nil
+	}, 10*time.Second, 20*time.Millisecond)
+	health := GetHealth()["process-test-timeout"]
+	require.NotEmpty(t, health.Errors)
+	assert.Equal(t, HealthSourceProcess, health.Errors[0].Source)
+}
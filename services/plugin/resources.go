@@ -0,0 +1,70 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/public"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// resourceNamespace 插件模板和静态资源的命名空间：模板名为 plugins/{id}/xxx，静态资源位于 /assets/plugins/{id}/
+func resourceNamespace(pluginID string) string {
+	return "plugins/" + pluginID
+}
+
+// resourceDir 解析插件返回的资源目录，相对路径基于 Gitea 工作目录，目录不存在时返回空
+func resourceDir(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(setting.AppWorkPath, dir)
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// mountResources 注册已启用插件的模板、语言文件和静态资源，未启用时移除
+func mountResources(pluginID string, p plugin_model.IPlugin, enabled bool) {
+	if !enabled {
+		unmountResources(pluginID)
+		return
+	}
+
+	namespace := resourceNamespace(pluginID)
+	if dir := resourceDir(p.GetTemplatePath()); dir != "" {
+		if err := templates.AddTemplateOverlay(namespace, dir); err != nil {
+			log.Error("Failed to load templates of plugin %s: %v", pluginID, err)
+		}
+	} else {
+		templates.RemoveTemplateOverlay(namespace)
+	}
+	if dir := resourceDir(p.GetLocalePath()); dir != "" {
+		translation.AddLocaleOverlay(namespace, dir)
+	} else {
+		translation.RemoveLocaleOverlay(namespace)
+	}
+	if dir := resourceDir(p.GetAssetsPath()); dir != "" {
+		public.AddAssetOverlay(namespace, dir)
+	} else {
+		public.RemoveAssetOverlay(namespace)
+	}
+}
+
+// unmountResources 移除插件的模板、语言文件和静态资源
+func unmountResources(pluginID string) {
+	namespace := resourceNamespace(pluginID)
+	templates.RemoveTemplateOverlay(namespace)
+	translation.RemoveLocaleOverlay(namespace)
+	public.RemoveAssetOverlay(namespace)
+}
@@ -0,0 +1,331 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/templates"
+	gitea_context "code.gitea.io/gitea/services/context"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// pluginDrainTimeout 卸载插件时等待进行中请求完成的最长时间
+const pluginDrainTimeout = 30 * time.Second
+
+// PluginRouter 插件动态挂载点，运行时添加、替换和移除插件的 Web/API 子路由
+type PluginRouter struct {
+	mu     sync.RWMutex
+	mounts map[string]*pluginMount
+	order  []string
+}
+
+// pluginMount 单个插件挂载的子路由
+type pluginMount struct {
+	pluginID  string
+	webRouter *chi.Mux
+	apiRouter *chi.Mux
+	slots     slotRegistry
+	scopes    []*plugin_model.APIScope // 已注册的访问令牌权限类别
+	enabled   bool
+	inflight  sync.WaitGroup
+}
+
+var (
+	globalRouter *PluginRouter
+	routerOnce   sync.Once
+)
+
+// GetRouter 获取全局插件路由
+func GetRouter() *PluginRouter {
+	routerOnce.Do(func() {
+		globalRouter = &PluginRouter{
+			mounts: make(map[string]*pluginMount),
+		}
+	})
+	return globalRouter
+}
+
+// Mount 挂载插件路由，已挂载时替换为新的子路由并等待旧路由上的请求完成
+func (r *PluginRouter) Mount(ctx context.Context, pluginID string, p plugin_model.IPlugin, enabled bool) {
+	m := &pluginMount{
+		pluginID:  pluginID,
+		webRouter: chi.NewRouter(),
+		apiRouter: chi.NewRouter(),
+		slots:     slotRegistry{pluginID: pluginID, renderers: make(map[string][]plugin_model.SlotRenderer)},
+		enabled:   enabled,
+	}
+	m.scopes = registerScopes(pluginID, p.Info().Scopes)
+	p.RegisterRoutes(m.webRouter)
+	p.RegisterAPIRoutes(m.apiRouter)
+	if sp, ok := p.(plugin_model.SlotProvider); ok {
+		sp.RegisterSlots(&m.slots)
+	}
+
+	r.mu.Lock()
+	old := r.mounts[pluginID]
+	r.mounts[pluginID] = m
+	if old == nil {
+		r.order = append(r.order, pluginID)
+	}
+	r.mu.Unlock()
+
+	if old != nil {
+		old.drain(ctx)
+		log.Info("Plugin routes swapped: %s", pluginID)
+	} else {
+		log.Info("Plugin routes mounted: %s", pluginID)
+	}
+}
+
+// Unmount 移除插件路由，并等待进行中的请求完成
+func (r *PluginRouter) Unmount(ctx context.Context, pluginID string) {
+	r.mu.Lock()
+	m := r.mounts[pluginID]
+	if m == nil {
+		r.mu.Unlock()
+		return
+	}
+	delete(r.mounts, pluginID)
+	for i, id := range r.order {
+		if id == pluginID {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	r.mu.Unlock()
+	auth_model.UnregisterPluginScopeCategories(pluginID)
+
+	m.drain(ctx)
+	log.Info("Plugin routes unmounted: %s", pluginID)
+}
+
+// SetEnabled 设置插件路由是否可用，禁用后匹配的请求返回 503。
+// 禁用时与 Unmount 相同，等待进行中的请求完成后返回，调用方随后可以安全地停用插件
+func (r *PluginRouter) SetEnabled(ctx context.Context, pluginID string, enabled bool) {
+	r.mu.Lock()
+	m := r.mounts[pluginID]
+	if m != nil {
+		m.enabled = enabled
+	}
+	r.mu.Unlock()
+
+	if m != nil && !enabled {
+		m.drain(ctx)
+	}
+}
+
+// IsEnabled 检查插件是否已挂载且处于启用状态
+func (r *PluginRouter) IsEnabled(pluginID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m := r.mounts[pluginID]
+	return m != nil && m.enabled
+}
+
+// IsMounted 检查插件路由是否已挂载
+func (r *PluginRouter) IsMounted(pluginID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.mounts[pluginID]
+	return ok
+}
+
+// WebHandler 返回核心 Web 路由未匹配时使用的处理函数。插件路由在核心路由之后匹配，不能覆盖核心路由；
+// 请求匹配插件路由时先调用 verify 进行与核心路由相同的登录和跨站请求检查（返回 false 表示已拒绝请求），
+// 没有插件路由匹配时交给 notFound
+func (r *PluginRouter) WebHandler(verify func(http.ResponseWriter, *http.Request) bool, notFound http.Handler) http.Handler {
+	return r.handler(verify, notFound, false)
+}
+
+// APIHandler 返回核心 API 路由未匹配时使用的处理函数，参数同 WebHandler
+func (r *PluginRouter) APIHandler(verify func(http.ResponseWriter, *http.Request) bool, notFound http.Handler) http.Handler {
+	return r.handler(verify, notFound, true)
+}
+
+func (r *PluginRouter) handler(verify func(http.ResponseWriter, *http.Request) bool, notFound http.Handler, api bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		m, enabled := r.match(req, api)
+		if m == nil {
+			notFound.ServeHTTP(w, req)
+			return
+		}
+		if !enabled {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		defer m.inflight.Done()
+		if verify != nil && !verify(w, req) {
+			return
+		}
+		if api && !m.checkTokenScope(req) {
+			return
+		}
+
+		// 插件路由以完整路径注册，使用新的路由上下文以免受外层 Mount 前缀影响
+		rctx := chi.NewRouteContext()
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		req = withRequestInfo(req, m.pluginID, api)
+		router := m.webRouter
+		if api {
+			router = m.apiRouter
+		}
+		m.serveHTTP(router, w, req)
+	})
+}
+
+// withRequestInfo 从 Gitea 的请求上下文中取出当前用户和语言交给插件，Web 请求还附加使用 Gitea 页面布局的模板渲染函数。
+// 已附加请求信息时（如测试工具直接调用）保持不变
+func withRequestInfo(req *http.Request, pluginID string, api bool) *http.Request {
+	if plugin_model.GetRequestInfo(req.Context()) != nil {
+		return req
+	}
+
+	if api {
+		apiCtx := gitea_context.GetAPIContextOrNil(req)
+		if apiCtx == nil {
+			return req
+		}
+		info := &plugin_model.RequestInfo{Lang: apiCtx.Locale.Language()}
+		if apiCtx.Doer != nil {
+			info.Doer = ToHostUser(apiCtx.Doer)
+		}
+		return req.WithContext(plugin_model.WithRequestInfo(req.Context(), info))
+	}
+
+	webCtx := gitea_context.GetWebContext(req.Context())
+	if webCtx == nil {
+		return req
+	}
+	info := &plugin_model.RequestInfo{Lang: webCtx.Locale.Language()}
+	if webCtx.Doer != nil {
+		info.Doer = ToHostUser(webCtx.Doer)
+	}
+	render := func(w http.ResponseWriter, status int, name string, data map[string]any) {
+		for k, v := range data {
+			webCtx.Data[k] = v
+		}
+		tplName := templates.TplName(resourceNamespace(pluginID) + "/" + name)
+		if err := webCtx.Render.HTML(w, status, tplName, webCtx.Data, webCtx.TemplateContext); err != nil {
+			log.Error("Plugin %s failed to render template %s: %v", pluginID, tplName, templates.HandleTemplateRenderingError(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	}
+	ctx := plugin_model.WithRequestInfo(req.Context(), info)
+	return req.WithContext(plugin_model.WithHTMLRenderer(ctx, render))
+}
+
+// serveHTTP 调用插件的请求处理函数，插件 panic 或返回 5xx 时计入插件的失败次数
+func (m *pluginMount) serveHTTP(router http.Handler, w http.ResponseWriter, req *http.Request) {
+	sw := &statusResponseWriter{ResponseWriter: w}
+	defer func() {
+		if r := recover(); r != nil {
+			if r == http.ErrAbortHandler {
+				panic(r)
+			}
+			log.Error("Plugin %s panicked handling %s %s: %v\n%s", m.pluginID, req.Method, req.URL.Path, r, log.Stack(2))
+			if sw.status == 0 {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			recordFailure(m.pluginID, HealthSourceHTTP, fmt.Errorf("panic handling %s %s: %v", req.Method, req.URL.Path, r))
+			return
+		}
+		if sw.status >= http.StatusInternalServerError {
+			recordFailure(m.pluginID, HealthSourceHTTP, fmt.Errorf("%s %s: status %d", req.Method, req.URL.Path, sw.status))
+			return
+		}
+		recordSuccess(m.pluginID)
+	}()
+	router.ServeHTTP(sw, req)
+}
+
+// statusResponseWriter 记录插件写入的响应状态码
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap 供 http.ResponseController 访问底层的 ResponseWriter（如 Flush）
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// match 查找处理该请求的插件。插件启用时在返回前登记进行中的请求，由调用方负责 Done。
+func (r *PluginRouter) match(req *http.Request, api bool) (*pluginMount, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, id := range r.order {
+		m := r.mounts[id]
+		router := m.webRouter
+		if api {
+			router = m.apiRouter
+		}
+		if !router.Match(chi.NewRouteContext(), req.Method, req.URL.Path) {
+			continue
+		}
+		if m.enabled {
+			m.inflight.Add(1)
+		}
+		return m, m.enabled
+	}
+	return nil, false
+}
+
+// matchSlot 返回已启用插件在插槽上注册的渲染函数，按挂载顺序排列。
+// 返回前为每个插件登记进行中的请求，由调用方负责 Done。
+func (r *PluginRouter) matchSlot(slot string) []*pluginMount {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var mounts []*pluginMount
+	for _, id := range r.order {
+		m := r.mounts[id]
+		if !m.enabled || len(m.slots.renderers[slot]) == 0 {
+			continue
+		}
+		m.inflight.Add(1)
+		mounts = append(mounts, m)
+	}
+	return mounts
+}
+
+// drain 等待进行中的请求完成，超时后放弃等待
+func (m *pluginMount) drain(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		m.inflight.Wait()
+		close(done)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, pluginDrainTimeout)
+	defer cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warn("Timed out waiting for in-flight requests of plugin %s", m.pluginID)
+	}
+}
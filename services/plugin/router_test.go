@@ -0,0 +1,176 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type routeTestPlugin struct {
+	plugin_model.IPlugin
+	body    string
+	scopes  []*plugin_model.APIScope
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *routeTestPlugin) Info() *plugin_model.PluginInfo {
+	return &plugin_model.PluginInfo{ID: "test", Scopes: p.scopes}
+}
+
+func (p *routeTestPlugin) RegisterRoutes(r chi.Router) {
+	r.Get("/test-plugin/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if p.release != nil {
+			close(p.started)
+			<-p.release
+		}
+		_, _ = w.Write([]byte(p.body + " " + chi.URLParam(r, "name")))
+	})
+}
+
+func (p *routeTestPlugin) RegisterAPIRoutes(r chi.Router) {
+	r.Get("/api/v1/test-plugin", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("api " + p.body))
+	})
+}
+
+func serveRouter(h http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestPluginRouter(t *testing.T) {
+	r := &PluginRouter{mounts: make(map[string]*pluginMount)}
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	web := r.WebHandler(nil, notFound)
+	api := r.APIHandler(nil, notFound)
+
+	assert.Equal(t, http.StatusNotFound, serveRouter(web, "/test-plugin/a").Code)
+
+	r.Mount(t.Context(), "test", &routeTestPlugin{body: "v1"}, true)
+	rec := serveRouter(web, "/test-plugin/a")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "v1 a", rec.Body.String())
+	assert.Equal(t, "api v1", serveRouter(api, "/api/v1/test-plugin").Body.String())
+	assert.Equal(t, http.StatusNotFound, serveRouter(web, "/api/v1/test-plugin").Code)
+
+	// swap
+	r.Mount(t.Context(), "test", &routeTestPlugin{body: "v2"}, true)
+	assert.Equal(t, "v2 b", serveRouter(web, "/test-plugin/b").Body.String())
+
+	// disable
+	r.SetEnabled(t.Context(), "test", false)
+	assert.Equal(t, http.StatusServiceUnavailable, serveRouter(web, "/test-plugin/a").Code)
+	r.SetEnabled(t.Context(), "test", true)
+	assert.Equal(t, http.StatusOK, serveRouter(web, "/test-plugin/a").Code)
+
+	// unmount
+	r.Unmount(t.Context(), "test")
+	assert.False(t, r.IsMounted("test"))
+	assert.Equal(t, http.StatusNotFound, serveRouter(web, "/test-plugin/a").Code)
+}
+
+func TestPluginRouterVerify(t *testing.T) {
+	r := &PluginRouter{mounts: make(map[string]*pluginMount)}
+	r.Mount(t.Context(), "test", &routeTestPlugin{body: "v1"}, true)
+
+	var verified []string
+	web := r.WebHandler(func(w http.ResponseWriter, req *http.Request) bool {
+		verified = append(verified, req.URL.Path)
+		if req.Header.Get("Sec-Fetch-Site") == "cross-site" {
+			http.Error(w, "cross-origin request", http.StatusForbidden)
+			return false
+		}
+		return true
+	}, http.NotFoundHandler())
+
+	assert.Equal(t, "v1 a", serveRouter(web, "/test-plugin/a").Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/test-plugin/a", nil)
+	req.Header.Set("Sec-Fetch-Site", "cross-site")
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// requests not matching any plugin route are not verified
+	assert.Equal(t, http.StatusNotFound, serveRouter(web, "/other").Code)
+	assert.Equal(t, []string{"/test-plugin/a", "/test-plugin/a"}, verified)
+}
+
+func TestPluginRouterDrain(t *testing.T) {
+	for name, stop := range map[string]func(ctx context.Context, r *PluginRouter){
+		"Unmount": func(ctx context.Context, r *PluginRouter) { r.Unmount(ctx, "test") },
+		"Disable": func(ctx context.Context, r *PluginRouter) { r.SetEnabled(ctx, "test", false) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := &PluginRouter{mounts: make(map[string]*pluginMount)}
+			p := &routeTestPlugin{body: "slow", started: make(chan struct{}), release: make(chan struct{})}
+			r.Mount(t.Context(), "test", p, true)
+			web := r.WebHandler(nil, http.NotFoundHandler())
+
+			served := make(chan *httptest.ResponseRecorder)
+			go func() { served <- serveRouter(web, "/test-plugin/a") }()
+
+			<-p.started
+
+			stopped := make(chan struct{})
+			go func() {
+				stop(t.Context(), r)
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+				t.Fatal("stop finished before in-flight request")
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			close(p.release)
+			assert.Equal(t, "slow a", (<-served).Body.String())
+			<-stopped
+		})
+	}
+}
+
+func TestPluginRouterScopes(t *testing.T) {
+	r := &PluginRouter{mounts: make(map[string]*pluginMount)}
+	r.Mount(t.Context(), "test", &routeTestPlugin{scopes: []*plugin_model.APIScope{
+		{Name: "test_admin", Paths: []string{"/api/v1/test-plugin/admin/"}},
+		{Name: "test_org", Paths: []string{"/api/v1/orgs/{org}/test-plugin"}},
+		{Name: "test"},
+		{Name: "repository"}, // built-in categories can not be registered
+	}}, true)
+	defer r.Unmount(t.Context(), "test")
+	assert.True(t, auth_model.IsPluginScopeCategory("test"))
+	assert.False(t, auth_model.IsPluginScopeCategory("repository"))
+
+	m := r.mounts["test"]
+	assert.Equal(t, auth_model.AccessTokenScope("read:test_admin"), m.requiredScope(http.MethodGet, "/api/v1/test-plugin/admin/users"))
+	assert.Equal(t, auth_model.AccessTokenScope("write:test_admin"), m.requiredScope(http.MethodDelete, "/api/v1/test-plugin/admin"))
+	assert.Equal(t, auth_model.AccessTokenScope("read:test"), m.requiredScope(http.MethodGet, "/api/v1/test-plugin/administrator"))
+	assert.Equal(t, auth_model.AccessTokenScope("write:test"), m.requiredScope(http.MethodPost, "/api/v1/test-plugin"))
+	assert.Equal(t, auth_model.AccessTokenScope("write:test_org"), m.requiredScope(http.MethodPost, "/api/v1/orgs/org3/test-plugin/items"))
+	assert.Equal(t, auth_model.AccessTokenScope("read:test"), m.requiredScope(http.MethodGet, "/api/v1/orgs/org3/teams"))
+	assert.Equal(t, auth_model.AccessTokenScope("read:test"), m.requiredScope(http.MethodGet, "/api/v1/orgs//test-plugin"))
+
+	// without a matching category the token needs full access
+	m.scopes = m.scopes[:1]
+	assert.Equal(t, auth_model.AccessTokenScopeAll, m.requiredScope(http.MethodGet, "/api/v1/test-plugin"))
+
+	r.Unmount(t.Context(), "test")
+	assert.False(t, auth_model.IsPluginScopeCategory("test"))
+}
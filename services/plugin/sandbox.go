@@ -0,0 +1,143 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/hostmatcher"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var (
+	hostHTTPClientOnce sync.Once
+	hostHTTPClient     *http.Client
+)
+
+// getHostHTTPClient 所有插件共用的 HTTP 客户端，只能访问 [plugin] ALLOWED_HOST_LIST 中的主机，默认只允许外部网络
+func getHostHTTPClient() *http.Client {
+	hostHTTPClientOnce.Do(func() {
+		allowedHostList := setting.PluginAllowedHostList
+		if allowedHostList == "" {
+			allowedHostList = hostmatcher.MatchBuiltinExternal
+		}
+		allowList := hostmatcher.ParseHostMatchList("plugin.ALLOWED_HOST_LIST", allowedHostList)
+
+		hostHTTPClient = &http.Client{
+			Timeout: hostHTTPTimeout,
+			Transport: &http.Transport{
+				Proxy:       proxy.Proxy(),
+				DialContext: hostmatcher.NewDialContext("plugin", allowList, nil, setting.Proxy.ProxyURLFixed),
+			},
+		}
+	})
+	return hostHTTPClient
+}
+
+// pluginDataDir 插件专属数据目录的路径
+func pluginDataDir(pluginID string) string {
+	return filepath.Join(setting.PluginDataDir, pluginID)
+}
+
+// pluginStorage 限定在插件命名空间（以插件 ID 为前缀）的对象存储
+type pluginStorage struct {
+	host *pluginHost
+}
+
+// objectPath 将插件给出的路径转换为对象存储中的路径，路径中的 .. 不能越出命名空间
+func (s *pluginStorage) objectPath(p string) (string, error) {
+	p = util.PathJoinRelX(p)
+	if p == "" || p == "." {
+		return "", util.NewInvalidArgumentErrorf("invalid storage path")
+	}
+	return s.host.pluginID + "/" + p, nil
+}
+
+// Save 保存文件
+func (s *pluginStorage) Save(ctx context.Context, p string, r io.Reader, size int64) (int64, error) {
+	if err := s.host.require(ctx, plugin_model.PermissionStorage); err != nil {
+		return 0, err
+	}
+	objPath, err := s.objectPath(p)
+	if err != nil {
+		return 0, err
+	}
+	return storage.Plugins.Save(objPath, r, size)
+}
+
+// Open 打开文件
+func (s *pluginStorage) Open(ctx context.Context, p string) (io.ReadCloser, error) {
+	if err := s.host.require(ctx, plugin_model.PermissionStorage); err != nil {
+		return nil, err
+	}
+	objPath, err := s.objectPath(p)
+	if err != nil {
+		return nil, err
+	}
+	return storage.Plugins.Open(objPath)
+}
+
+// Delete 删除文件
+func (s *pluginStorage) Delete(ctx context.Context, p string) error {
+	if err := s.host.require(ctx, plugin_model.PermissionStorage); err != nil {
+		return err
+	}
+	objPath, err := s.objectPath(p)
+	if err != nil {
+		return err
+	}
+	return storage.Plugins.Delete(objPath)
+}
+
+// List 列出目录下的所有文件，dir 为空时列出整个命名空间
+func (s *pluginStorage) List(ctx context.Context, dir string) ([]string, error) {
+	if err := s.host.require(ctx, plugin_model.PermissionStorage); err != nil {
+		return nil, err
+	}
+	prefix := s.host.pluginID + "/"
+	if dir = util.PathJoinRelX(dir); dir != "" && dir != "." {
+		prefix += dir + "/"
+	}
+
+	var paths []string
+	err := storage.Plugins.IterateObjects(prefix, func(objPath string, obj storage.Object) error {
+		_ = obj.Close()
+		paths = append(paths, strings.TrimPrefix(filepath.ToSlash(objPath), s.host.pluginID+"/"))
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return paths, err
+}
+
+// removePluginFiles 卸载插件时删除其数据目录和对象存储命名空间中的文件
+func removePluginFiles(pluginID string) error {
+	if err := util.RemoveAll(pluginDataDir(pluginID)); err != nil {
+		return fmt.Errorf("remove data directory: %w", err)
+	}
+
+	err := storage.Plugins.IterateObjects(pluginID+"/", func(objPath string, obj storage.Object) error {
+		_ = obj.Close()
+		return storage.Plugins.Delete(objPath)
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove storage files: %w", err)
+	}
+	log.Info("Removed data directory and storage files of plugin %s", pluginID)
+	return nil
+}
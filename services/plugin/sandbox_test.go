@@ -0,0 +1,64 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginStorageObjectPath(t *testing.T) {
+	s := &pluginStorage{host: newPluginHost("demo")}
+	for input, expected := range map[string]string{
+		"a.txt":              "demo/a.txt",
+		"/cache/b.bin":       "demo/cache/b.bin",
+		"../other/secret":    "demo/other/secret",
+		`cache\..\..\c.json`: "demo/c.json",
+	} {
+		p, err := s.objectPath(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, p, input)
+	}
+
+	for _, input := range []string{"", "/", ".."} {
+		_, err := s.objectPath(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestRemovePluginFiles(t *testing.T) {
+	defer test.MockVariableValue(&setting.PluginDataDir, t.TempDir())()
+	local, err := storage.NewStorage(setting.LocalStorageType, &setting.Storage{Path: t.TempDir()})
+	require.NoError(t, err)
+	defer test.MockVariableValue(&storage.Plugins, local)()
+
+	for _, id := range []string{"demo", "demo2"} {
+		require.NoError(t, os.MkdirAll(pluginDataDir(id), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(pluginDataDir(id), "db.sqlite"), []byte("data"), 0o644))
+		_, err := storage.Plugins.Save(id+"/cache/a.txt", strings.NewReader("a"), 1)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, removePluginFiles("demo"))
+	assert.NoDirExists(t, pluginDataDir("demo"))
+	_, err = storage.Plugins.Stat("demo/cache/a.txt")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// other plugins are not affected
+	assert.FileExists(t, filepath.Join(pluginDataDir("demo2"), "db.sqlite"))
+	_, err = storage.Plugins.Stat("demo2/cache/a.txt")
+	assert.NoError(t, err)
+
+	// nothing left to remove
+	require.NoError(t, removePluginFiles("demo"))
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/log"
+	gitea_context "code.gitea.io/gitea/services/context"
+)
+
+// registerScopes 注册插件声明的访问令牌权限类别，返回注册成功的类别。
+// 与内置类别或其他插件重名的类别被忽略，对应的 API 路由只接受拥有全部权限的令牌
+func registerScopes(pluginID string, scopes []*plugin_model.APIScope) []*plugin_model.APIScope {
+	auth_model.UnregisterPluginScopeCategories(pluginID)
+
+	registered := make([]*plugin_model.APIScope, 0, len(scopes))
+	for _, scope := range scopes {
+		if err := auth_model.RegisterPluginScopeCategory(pluginID, scope.Name); err != nil {
+			log.Error("Plugin %s: %v", pluginID, err)
+			continue
+		}
+		registered = append(registered, scope)
+	}
+	return registered
+}
+
+// requiredScope 使用令牌访问插件 API 路由所需的权限：按请求路径选择第一个适用的类别，按请求方法区分读写。
+// 没有适用的类别时需要令牌拥有全部权限
+func (m *pluginMount) requiredScope(method, path string) auth_model.AccessTokenScope {
+	level := "read:"
+	if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete {
+		level = "write:"
+	}
+	for _, scope := range m.scopes {
+		if len(scope.Paths) == 0 {
+			return auth_model.AccessTokenScope(level + scope.Name)
+		}
+		for _, prefix := range scope.Paths {
+			if matchPathPrefix(prefix, path) {
+				return auth_model.AccessTokenScope(level + scope.Name)
+			}
+		}
+	}
+	return auth_model.AccessTokenScopeAll
+}
+
+// matchPathPrefix 请求路径是否在路径前缀之下，前缀中的 {name} 段匹配任意一段路径，如 /api/v1/orgs/{org}/license
+func matchPathPrefix(prefix, path string) bool {
+	prefixSegs := strings.Split(strings.Trim(prefix, "/"), "/")
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+	if len(pathSegs) < len(prefixSegs) {
+		return false
+	}
+	for i, seg := range prefixSegs {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if pathSegs[i] == "" {
+				return false
+			}
+			continue
+		}
+		if seg != pathSegs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkTokenScope 在插件处理 API 请求前检查访问令牌的权限，未通过时已写入 403 响应
+func (m *pluginMount) checkTokenScope(req *http.Request) bool {
+	ctx := gitea_context.GetAPIContextOrNil(req)
+	if ctx == nil || ctx.Data["IsApiToken"] != true {
+		return true
+	}
+	scope, ok := ctx.Data["ApiTokenScope"].(auth_model.AccessTokenScope)
+	if !ok {
+		return true
+	}
+
+	required := m.requiredScope(req.Method, req.URL.Path)
+	allow, err := scope.HasScope(required)
+	if err != nil {
+		ctx.APIError(http.StatusForbidden, "checking scope failed: "+err.Error())
+		return false
+	}
+	if !allow {
+		ctx.APIError(http.StatusForbidden, fmt.Sprintf("token does not have at least one of required scope(s), required=%v, token scope=%v", required, scope))
+		return false
+	}
+	return true
+}
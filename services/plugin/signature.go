@@ -0,0 +1,198 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/42wim/sshsig"
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// 插件包中的校验和清单及其分离签名
+const (
+	PackageManifestName       = "MANIFEST"     // 每行格式与 sha256sum 输出一致：<sha256>  <path>
+	PackageSignatureName      = "MANIFEST.sig" // 对 MANIFEST 的 SSH 或 GPG 分离签名
+	PackageSignatureNamespace = "gitea-plugin" // ssh-keygen -Y sign -n 使用的命名空间
+)
+
+// verifyPackage 使用受信任的发布者密钥验证插件包，返回签名密钥（未签名且允许时为 nil）
+func verifyPackage(ctx context.Context, zipPath string) (*plugin_model.TrustedKey, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	keys, err := plugin_model.ListTrustedKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return verifyPackageFiles(&r.Reader, keys, setting.PluginRequireSignature)
+}
+
+// verifyPackageFiles 校验清单覆盖包内所有文件且哈希一致，并验证清单签名
+func verifyPackageFiles(r *zip.Reader, keys []*plugin_model.TrustedKey, requireSignature bool) (*plugin_model.TrustedKey, error) {
+	var manifestFile, signatureFile *zip.File
+	for _, f := range r.File {
+		switch f.Name {
+		case PackageManifestName:
+			manifestFile = f
+		case PackageSignatureName:
+			signatureFile = f
+		}
+	}
+
+	// 清单和签名必须同时存在，只有其中之一的包视为格式错误，不能当作未签名包接受
+	switch {
+	case manifestFile == nil && signatureFile == nil:
+		if requireSignature {
+			return nil, plugin_model.ErrPackageVerification{Reason: "package is not signed"}
+		}
+		return nil, nil
+	case manifestFile == nil:
+		return nil, plugin_model.ErrPackageVerification{Reason: "signature without manifest"}
+	case signatureFile == nil:
+		return nil, plugin_model.ErrPackageVerification{Reason: "manifest without signature"}
+	}
+
+	manifest, err := readZipFile(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyManifest(r, manifest); err != nil {
+		return nil, err
+	}
+
+	signature, err := readZipFile(signatureFile)
+	if err != nil {
+		return nil, err
+	}
+	return verifyManifestSignature(manifest, signature, keys)
+}
+
+// verifyManifest 校验清单与包内文件一一对应且 SHA-256 一致
+func verifyManifest(r *zip.Reader, manifest []byte) error {
+	expected := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		sum, name, ok := strings.Cut(line, " ")
+		if !ok {
+			return plugin_model.ErrPackageVerification{Reason: fmt.Sprintf("invalid manifest line: %q", line)}
+		}
+		// sha256sum 二进制模式会在文件名前加 *
+		name = strings.TrimPrefix(strings.TrimLeft(name, " "), "*")
+		if _, exists := expected[name]; exists {
+			return plugin_model.ErrPackageVerification{Reason: "duplicate manifest entry: " + name}
+		}
+		expected[name] = strings.ToLower(sum)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || f.Name == PackageManifestName || f.Name == PackageSignatureName {
+			continue
+		}
+		sum, ok := expected[f.Name]
+		if !ok {
+			return plugin_model.ErrPackageVerification{Reason: "file not covered by manifest: " + f.Name}
+		}
+		delete(expected, f.Name)
+
+		actual, err := hashZipFile(f)
+		if err != nil {
+			return err
+		}
+		if actual != sum {
+			return plugin_model.ErrPackageVerification{Reason: "checksum mismatch: " + f.Name}
+		}
+	}
+
+	for name := range expected {
+		return plugin_model.ErrPackageVerification{Reason: "file listed in manifest is missing: " + name}
+	}
+	return nil
+}
+
+// verifyManifestSignature 使用受信任的密钥验证清单签名，返回签名密钥
+func verifyManifestSignature(manifest, signature []byte, keys []*plugin_model.TrustedKey) (*plugin_model.TrustedKey, error) {
+	switch {
+	case bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN SSH SIGNATURE-----")):
+		for _, key := range keys {
+			if key.KeyType != plugin_model.TrustedKeyTypeSSH {
+				continue
+			}
+			if sshsig.Verify(bytes.NewReader(manifest), signature, []byte(key.Content), PackageSignatureNamespace) == nil {
+				return key, nil
+			}
+		}
+	case bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN PGP SIGNATURE-----")):
+		var keyring openpgp.EntityList
+		byKeyID := make(map[string]*plugin_model.TrustedKey)
+		for _, key := range keys {
+			if key.KeyType != plugin_model.TrustedKeyTypeGPG {
+				continue
+			}
+			entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.Content))
+			if err != nil {
+				continue
+			}
+			keyring = append(keyring, entities...)
+			for _, e := range entities {
+				byKeyID[e.PrimaryKey.KeyIdString()] = key
+			}
+		}
+		if len(keyring) > 0 {
+			signer, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(manifest), bytes.NewReader(signature), nil)
+			if err == nil && signer != nil {
+				if key, ok := byKeyID[signer.PrimaryKey.KeyIdString()]; ok {
+					return key, nil
+				}
+			}
+		}
+	default:
+		return nil, plugin_model.ErrPackageVerification{Reason: "unknown signature format"}
+	}
+	return nil, plugin_model.ErrPackageVerification{Reason: "signature is not made by a trusted publisher"}
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func hashZipFile(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
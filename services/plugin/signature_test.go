@@ -0,0 +1,170 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+
+	"github.com/42wim/sshsig"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func buildManifest(files map[string]string) string {
+	var buf bytes.Buffer
+	for name, content := range files {
+		sum := sha256.Sum256([]byte(content))
+		fmt.Fprintf(&buf, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	return buf.String()
+}
+
+func buildZip(t *testing.T, files map[string]string) *zip.Reader {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return r
+}
+
+func newSSHTrustedKey(t *testing.T) (*plugin_model.TrustedKey, []byte) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+	key := &plugin_model.TrustedKey{
+		KeyType:     plugin_model.TrustedKeyTypeSSH,
+		Content:     string(ssh.MarshalAuthorizedKey(sshPub)),
+		Fingerprint: ssh.FingerprintSHA256(sshPub),
+	}
+	return key, pem.EncodeToMemory(block)
+}
+
+func TestVerifyPackageFilesSSH(t *testing.T) {
+	trusted, privPEM := newSSHTrustedKey(t)
+	untrusted, _ := newSSHTrustedKey(t)
+
+	files := map[string]string{
+		"plugin.json": `{"id":"test"}`,
+		"main.go":     "package main",
+	}
+	manifest := buildManifest(files)
+	sig, err := sshsig.Sign(privPEM, bytes.NewReader([]byte(manifest)), PackageSignatureNamespace)
+	require.NoError(t, err)
+
+	pkg := map[string]string{PackageManifestName: manifest, PackageSignatureName: string(sig)}
+	for k, v := range files {
+		pkg[k] = v
+	}
+
+	signer, err := verifyPackageFiles(buildZip(t, pkg), []*plugin_model.TrustedKey{untrusted, trusted}, true)
+	require.NoError(t, err)
+	assert.Equal(t, trusted.Fingerprint, signer.Fingerprint)
+
+	// signed by an unknown publisher
+	_, err = verifyPackageFiles(buildZip(t, pkg), []*plugin_model.TrustedKey{untrusted}, true)
+	assert.True(t, plugin_model.IsErrPackageVerification(err))
+
+	// tampered file
+	pkg["main.go"] = "package evil"
+	_, err = verifyPackageFiles(buildZip(t, pkg), []*plugin_model.TrustedKey{trusted}, true)
+	assert.True(t, plugin_model.IsErrPackageVerification(err))
+	pkg["main.go"] = files["main.go"]
+
+	// file not covered by manifest
+	pkg["extra.go"] = "package main"
+	_, err = verifyPackageFiles(buildZip(t, pkg), []*plugin_model.TrustedKey{trusted}, true)
+	assert.True(t, plugin_model.IsErrPackageVerification(err))
+	delete(pkg, "extra.go")
+
+	// missing file listed in manifest
+	delete(pkg, "main.go")
+	_, err = verifyPackageFiles(buildZip(t, pkg), []*plugin_model.TrustedKey{trusted}, true)
+	assert.True(t, plugin_model.IsErrPackageVerification(err))
+}
+
+func TestVerifyPackageFilesGPG(t *testing.T) {
+	entity, err := openpgp.NewEntity("publisher", "", "publisher@example.com", nil)
+	require.NoError(t, err)
+
+	var pubBuf bytes.Buffer
+	aw, err := armor.Encode(&pubBuf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(aw))
+	require.NoError(t, aw.Close())
+
+	trusted := &plugin_model.TrustedKey{
+		KeyType:     plugin_model.TrustedKeyTypeGPG,
+		Content:     pubBuf.String(),
+		Fingerprint: entity.PrimaryKey.KeyIdString(),
+	}
+
+	files := map[string]string{"plugin.json": `{"id":"test"}`}
+	manifest := buildManifest(files)
+	var sig bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&sig, entity, bytes.NewReader([]byte(manifest)), nil))
+
+	pkg := map[string]string{
+		"plugin.json":        files["plugin.json"],
+		PackageManifestName:  manifest,
+		PackageSignatureName: sig.String(),
+	}
+	signer, err := verifyPackageFiles(buildZip(t, pkg), []*plugin_model.TrustedKey{trusted}, true)
+	require.NoError(t, err)
+	assert.Equal(t, trusted.Fingerprint, signer.Fingerprint)
+}
+
+func TestVerifyPackageFilesUnsigned(t *testing.T) {
+	pkg := map[string]string{"plugin.json": `{"id":"test"}`}
+
+	_, err := verifyPackageFiles(buildZip(t, pkg), nil, true)
+	assert.True(t, plugin_model.IsErrPackageVerification(err))
+
+	signer, err := verifyPackageFiles(buildZip(t, pkg), nil, false)
+	require.NoError(t, err)
+	assert.Nil(t, signer)
+}
+
+func TestVerifyPackageFilesHalfSigned(t *testing.T) {
+	files := map[string]string{"plugin.json": `{"id":"test"}`}
+	key, _ := newSSHTrustedKey(t)
+
+	manifestOnly := map[string]string{PackageManifestName: buildManifest(files)}
+	signatureOnly := map[string]string{PackageSignatureName: "-----BEGIN SSH SIGNATURE-----\n-----END SSH SIGNATURE-----\n"}
+	for _, extra := range []map[string]string{manifestOnly, signatureOnly} {
+		pkg := map[string]string{}
+		for name, content := range files {
+			pkg[name] = content
+		}
+		for name, content := range extra {
+			pkg[name] = content
+		}
+		for _, requireSignature := range []bool{true, false} {
+			_, err := verifyPackageFiles(buildZip(t, pkg), []*plugin_model.TrustedKey{key}, requireSignature)
+			assert.True(t, plugin_model.IsErrPackageVerification(err), "%v", err)
+		}
+	}
+}
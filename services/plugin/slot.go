@@ -0,0 +1,110 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// slotRenderTimeout 单个插件渲染插槽的最长时间，超时的插件内容不显示
+const slotRenderTimeout = 2 * time.Second
+
+// slotRegistry 插件在挂载时注册的插槽渲染函数
+type slotRegistry struct {
+	pluginID  string
+	renderers map[string][]plugin_model.SlotRenderer
+}
+
+// RegisterSlot 注册插槽渲染函数，未知的插槽会被忽略
+func (r *slotRegistry) RegisterSlot(slot string, render plugin_model.SlotRenderer) {
+	if !plugin_model.IsValidSlot(slot) {
+		log.Warn("Plugin %s registered unknown UI slot %q", r.pluginID, slot)
+		return
+	}
+	r.renderers[slot] = append(r.renderers[slot], render)
+}
+
+// RenderSlot 渲染所有已启用插件在插槽中的内容，data 为页面模板数据。
+// 插件渲染失败、超时或 panic 时只记录日志，不影响页面其他部分
+func RenderSlot(ctx context.Context, slot string, data map[string]any) template.HTML {
+	mounts := GetRouter().matchSlot(slot)
+	if len(mounts) == 0 {
+		return ""
+	}
+
+	sc := newSlotContext(ctx, slot, data)
+	var sb strings.Builder
+	for _, m := range mounts {
+		for _, render := range m.slots.renderers[slot] {
+			content, err := renderSlot(ctx, render, sc)
+			if err != nil {
+				log.Error("Plugin %s failed to render UI slot %s: %v", m.pluginID, slot, err)
+				recordFailure(m.pluginID, HealthSourceSlot, fmt.Errorf("render %s: %w", slot, err))
+				continue
+			}
+			recordSuccess(m.pluginID)
+			sb.WriteString(string(content))
+		}
+		m.inflight.Done()
+	}
+	return template.HTML(sb.String())
+}
+
+func renderSlot(ctx context.Context, render plugin_model.SlotRenderer, sc *plugin_model.SlotContext) (content template.HTML, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, slotRenderTimeout)
+	defer cancel()
+	return render(ctx, sc)
+}
+
+// newSlotContext 从页面模板数据中提取插槽上下文
+func newSlotContext(ctx context.Context, slot string, data map[string]any) *plugin_model.SlotContext {
+	sc := &plugin_model.SlotContext{Slot: slot}
+	sc.Link, _ = data["Link"].(string)
+	if locale, ok := ctx.Value(translation.ContextKey).(translation.Locale); ok {
+		sc.Locale = locale.Language()
+	}
+	if doer, ok := data["SignedUser"].(*user_model.User); ok && doer != nil {
+		sc.Doer = ToHostUser(doer)
+	}
+	if repo, ok := data["Repository"].(*repo_model.Repository); ok && repo != nil {
+		sc.Repo = &plugin_model.SlotRepo{
+			ID:        repo.ID,
+			OwnerName: repo.OwnerName,
+			Name:      repo.Name,
+			IsPrivate: repo.IsPrivate,
+			Link:      repo.Link(),
+		}
+	}
+	if issue, ok := data["Issue"].(*issues_model.Issue); ok && issue != nil {
+		sc.Issue = &plugin_model.SlotIssue{
+			ID:       issue.ID,
+			Index:    issue.Index,
+			Title:    issue.Title,
+			IsPull:   issue.IsPull,
+			IsClosed: issue.IsClosed,
+			Link:     issue.Link(),
+		}
+		if issue.PullRequest != nil {
+			sc.Issue.HasMerged = issue.PullRequest.HasMerged
+		}
+	}
+	return sc
+}
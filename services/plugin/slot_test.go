@@ -0,0 +1,52 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"testing"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type slotTestPlugin struct {
+	routeTestPlugin
+}
+
+func (p *slotTestPlugin) RegisterSlots(r plugin_model.SlotRegistry) {
+	r.RegisterSlot(plugin_model.SlotRepoHeaderTab, func(_ context.Context, sc *plugin_model.SlotContext) (template.HTML, error) {
+		return template.HTML(`<a class="item">` + sc.Doer.Name + "@" + sc.Repo.OwnerName + "/" + sc.Repo.Name + `</a>`), nil
+	})
+	r.RegisterSlot(plugin_model.SlotRepoHeaderTab, func(context.Context, *plugin_model.SlotContext) (template.HTML, error) {
+		return "", errors.New("broken")
+	})
+	r.RegisterSlot(plugin_model.SlotRepoHeaderTab, func(context.Context, *plugin_model.SlotContext) (template.HTML, error) {
+		panic("boom")
+	})
+	r.RegisterSlot("no.such.slot", func(context.Context, *plugin_model.SlotContext) (template.HTML, error) {
+		return "unknown", nil
+	})
+}
+
+func TestRenderSlot(t *testing.T) {
+	data := map[string]any{
+		"SignedUser": &user_model.User{ID: 1, Name: "user1"},
+		"Repository": &repo_model.Repository{ID: 2, OwnerName: "owner", Name: "repo"},
+	}
+
+	GetRouter().Mount(t.Context(), "slot-test", &slotTestPlugin{}, true)
+	defer GetRouter().Unmount(t.Context(), "slot-test")
+
+	assert.EqualValues(t, `<a class="item">user1@owner/repo</a>`, RenderSlot(t.Context(), plugin_model.SlotRepoHeaderTab, data))
+	assert.Empty(t, RenderSlot(t.Context(), plugin_model.SlotIssueSidebar, data))
+
+	GetRouter().SetEnabled(t.Context(), "slot-test", false)
+	assert.Empty(t, RenderSlot(t.Context(), plugin_model.SlotRepoHeaderTab, data))
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// InstallFromFile 从上传的插件包安装插件，适用于无法访问插件市场的离线环境
+func (m *PluginManager) InstallFromFile(ctx context.Context, doer *user_model.User, r io.Reader) (*plugin_model.Plugin, error) {
+	zipPath, err := m.saveTempArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(zipPath)
+
+	return m.installArchive(ctx, doer, zipPath, "", plugin_model.SourceUpload)
+}
+
+// InstallFromReleaseOptions 从仓库发布版本安装插件的选项
+type InstallFromReleaseOptions struct {
+	Owner      string
+	Repo       string
+	TagName    string
+	Attachment string // 附件名，为空时使用发布版本中唯一的 .zip 附件
+}
+
+// InstallFromRelease 从本实例仓库发布版本的附件安装插件
+func (m *PluginManager) InstallFromRelease(ctx context.Context, doer *user_model.User, opts InstallFromReleaseOptions) (*plugin_model.Plugin, error) {
+	attach, err := findReleaseArchive(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fr, err := storage.Attachments.Open(attach.RelativePath())
+	if err != nil {
+		return nil, fmt.Errorf("open attachment: %w", err)
+	}
+	defer fr.Close()
+
+	zipPath, err := m.saveTempArchive(fr)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(zipPath)
+
+	source := plugin_model.ReleaseSource(opts.Owner, opts.Repo, opts.TagName)
+	return m.installArchive(ctx, doer, zipPath, "", source)
+}
+
+// findReleaseArchive 查找发布版本中的插件包附件
+func findReleaseArchive(ctx context.Context, opts InstallFromReleaseOptions) (*repo_model.Attachment, error) {
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, opts.Owner, opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := repo_model.GetRelease(ctx, repo.ID, opts.TagName)
+	if err != nil {
+		return nil, err
+	}
+	if rel.IsDraft {
+		return nil, repo_model.ErrReleaseNotExist{TagName: opts.TagName}
+	}
+	if err := repo_model.GetReleaseAttachments(ctx, rel); err != nil {
+		return nil, err
+	}
+
+	var found *repo_model.Attachment
+	for _, attach := range rel.Attachments {
+		if opts.Attachment != "" {
+			if attach.Name == opts.Attachment {
+				return attach, nil
+			}
+			continue
+		}
+		if strings.HasSuffix(strings.ToLower(attach.Name), ".zip") {
+			if found != nil {
+				return nil, util.NewInvalidArgumentErrorf("release %s has multiple zip attachments, please specify one", opts.TagName)
+			}
+			found = attach
+		}
+	}
+	if found == nil {
+		return nil, util.NewNotExistErrorf("release %s has no plugin package attachment", opts.TagName)
+	}
+	return found, nil
+}
+
+// saveTempArchive 将插件包保存到临时目录
+func (m *PluginManager) saveTempArchive(r io.Reader) (string, error) {
+	f, err := os.CreateTemp(filepath.Join(m.pluginsDir, "temp"), "upload-*.zip")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
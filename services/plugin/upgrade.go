@@ -0,0 +1,254 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Upgrade 从插件市场将插件升级到指定版本。
+// 新版本先解压到独立的版本目录并完成初始化和数据库迁移，成功后才原子替换正在运行的实例；
+// 初始化失败时旧版本继续运行。原版本目录会保留，可通过 Rollback 回滚
+func (m *PluginManager) Upgrade(ctx context.Context, doer *user_model.User, pluginID, version string) (*plugin_model.Plugin, error) {
+	m.upgradeMu.Lock()
+	defer m.upgradeMu.Unlock()
+
+	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	if err != nil {
+		return nil, err
+	}
+	if version == dbPlugin.Version {
+		return nil, plugin_model.ErrPluginVersionInstalled{PluginID: pluginID, Version: version}
+	}
+	// 已安装的是 native 插件时不必下载新版本
+	nodes, err := m.loader.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if node := nodes[pluginID]; node != nil && node.isNative() {
+		return nil, plugin_model.ErrPluginHotUpgradeUnsupported{PluginID: pluginID}
+	}
+
+	zipPath, err := m.downloadPackage(ctx, pluginID, version)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(zipPath)
+
+	return m.upgradeArchive(ctx, doer, dbPlugin, zipPath)
+}
+
+// upgradeArchive 验证插件包并切换到其中的版本
+func (m *PluginManager) upgradeArchive(ctx context.Context, doer *user_model.User, dbPlugin *plugin_model.Plugin, zipPath string) (*plugin_model.Plugin, error) {
+	// 1. 验证签名和校验和
+	signer, err := verifyPackage(ctx, zipPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 解压到临时目录并读取元数据
+	stagingPath, err := os.MkdirTemp(filepath.Join(m.pluginsDir, "temp"), "upgrade-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(stagingPath)
+
+	if err := m.unzip(zipPath, stagingPath); err != nil {
+		return nil, fmt.Errorf("unzip plugin: %w", err)
+	}
+	metadata, err := m.loader.readMetadata(stagingPath)
+	if err != nil {
+		return nil, fmt.Errorf("read metadata: %w", err)
+	}
+	if metadata.ID != dbPlugin.PluginID {
+		return nil, fmt.Errorf("package contains plugin %s, expected %s", metadata.ID, dbPlugin.PluginID)
+	}
+	if !plugin_model.IsValidPluginVersion(metadata.Version) {
+		return nil, plugin_model.ErrPluginInvalidVersion{Version: metadata.Version}
+	}
+	if metadata.Version == dbPlugin.Version {
+		return nil, plugin_model.ErrPluginVersionInstalled{PluginID: dbPlugin.PluginID, Version: metadata.Version}
+	}
+
+	// 3. 检查新版本的 Gitea 版本约束和依赖关系
+	if err := m.checkVersionSwitch(ctx, dbPlugin, metadata); err != nil {
+		return nil, err
+	}
+
+	// 4. 移动到版本目录，覆盖同版本的旧目录（如之前保留的上一个版本）
+	versionPath := m.loader.versionPath(dbPlugin.PluginID, metadata.Version)
+	if err := os.RemoveAll(versionPath); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(versionPath), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(stagingPath, versionPath); err != nil {
+		return nil, fmt.Errorf("move plugin files: %w", err)
+	}
+	if versionPath == dbPlugin.PreviousPath {
+		dbPlugin.PreviousVersion, dbPlugin.PreviousPath = "", ""
+	}
+
+	// 5. 初始化新版本并切换
+	if err := m.switchVersion(ctx, dbPlugin, versionPath); err != nil {
+		_ = os.RemoveAll(versionPath)
+		return nil, err
+	}
+
+	dbPlugin.Signer = ""
+	if signer != nil {
+		dbPlugin.Signer = signer.Fingerprint
+	}
+	if err := plugin_model.UpdatePlugin(ctx, dbPlugin); err != nil {
+		return nil, fmt.Errorf("update database: %w", err)
+	}
+
+	writeAudit(ctx, doer, &plugin_model.Audit{
+		PluginID:   dbPlugin.PluginID,
+		Action:     plugin_model.AuditActionUpgrade,
+		OldVersion: dbPlugin.PreviousVersion,
+		NewVersion: dbPlugin.Version,
+	})
+	log.Info("Plugin upgraded: %s %s -> %s", dbPlugin.PluginID, dbPlugin.PreviousVersion, dbPlugin.Version)
+	return dbPlugin, nil
+}
+
+// Rollback 回滚到升级前保留的版本，当前版本随之成为可回滚的版本
+func (m *PluginManager) Rollback(ctx context.Context, doer *user_model.User, pluginID string) (*plugin_model.Plugin, error) {
+	m.upgradeMu.Lock()
+	defer m.upgradeMu.Unlock()
+
+	dbPlugin, err := plugin_model.GetPluginByID(ctx, pluginID)
+	if err != nil {
+		return nil, err
+	}
+	if !dbPlugin.CanRollback() || !fileExists(filepath.Join(dbPlugin.PreviousPath, "plugin.json")) {
+		return nil, plugin_model.ErrPluginNoPreviousVersion{PluginID: pluginID}
+	}
+
+	metadata, err := m.loader.readMetadata(dbPlugin.PreviousPath)
+	if err != nil {
+		return nil, fmt.Errorf("read metadata: %w", err)
+	}
+	if err := m.checkVersionSwitch(ctx, dbPlugin, metadata); err != nil {
+		return nil, err
+	}
+
+	if err := m.switchVersion(ctx, dbPlugin, dbPlugin.PreviousPath); err != nil {
+		return nil, err
+	}
+	if err := plugin_model.UpdatePlugin(ctx, dbPlugin); err != nil {
+		return nil, fmt.Errorf("update database: %w", err)
+	}
+
+	writeAudit(ctx, doer, &plugin_model.Audit{
+		PluginID:   pluginID,
+		Action:     plugin_model.AuditActionRollback,
+		OldVersion: dbPlugin.PreviousVersion,
+		NewVersion: dbPlugin.Version,
+	})
+	log.Info("Plugin rolled back: %s %s -> %s", pluginID, dbPlugin.PreviousVersion, dbPlugin.Version)
+	return dbPlugin, nil
+}
+
+// checkVersionSwitch 检查目标版本满足 Gitea 版本约束和自身依赖，不破坏依赖它的插件的版本约束，
+// 且已启用时不会引入未经批准的权限
+func (m *PluginManager) checkVersionSwitch(ctx context.Context, dbPlugin *plugin_model.Plugin, metadata *plugin_model.PluginMetadata) error {
+	nodes, err := m.loader.loadNodes(ctx)
+	if err != nil {
+		return err
+	}
+	// Go 插件无法在同一进程中再加载一个包路径相同的 .so，native 插件只能重新安装并重启 Gitea 来更换版本
+	if node := nodes[dbPlugin.PluginID]; metadata.GetRuntime() == plugin_model.RuntimeNative || (node != nil && node.isNative()) {
+		return plugin_model.ErrPluginHotUpgradeUnsupported{PluginID: dbPlugin.PluginID}
+	}
+
+	if err := checkGiteaVersion(metadata); err != nil {
+		return err
+	}
+	deps, err := metadata.ParseDependencies()
+	if err != nil {
+		return err
+	}
+	if err := checkDependencies(dbPlugin.PluginID, deps, nodes, dbPlugin.IsEnabled); err != nil {
+		return err
+	}
+	// 已启用的插件不能在未经批准的情况下通过升级获得新权限
+	if dbPlugin.IsEnabled {
+		if err := checkPermissionsApproved(dbPlugin, metadata); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range dependentsOf(dbPlugin.PluginID, nodes, false) {
+		for _, dep := range nodes[id].Deps {
+			if dep.PluginID == dbPlugin.PluginID && !dep.Satisfies(metadata.Version) {
+				return plugin_model.ErrPluginDependencyNotSatisfied{
+					PluginID:     id,
+					DependencyID: dbPlugin.PluginID,
+					Constraint:   dep.Constraint,
+					Reason:       "version would become " + metadata.Version,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// switchVersion 初始化目标目录中的插件版本，初始化成功后执行其数据库迁移，再替换正在运行的实例，
+// 并将当前版本记录为可回滚的上一个版本；调用方负责保存 dbPlugin。
+// 迁移落后于数据库版本的目标版本（跨越表结构变更的回滚）会被拒绝
+func (m *PluginManager) switchVersion(ctx context.Context, dbPlugin *plugin_model.Plugin, targetPath string) error {
+	pluginInstance, metadata, err := m.loader.openPlugin(ctx, dbPlugin.PluginID, targetPath)
+	if err != nil {
+		return err
+	}
+
+	// 新旧实例不能同时处于启用状态：先停用旧实例再启用新实例，新实例启用失败时恢复旧实例
+	if dbPlugin.IsEnabled {
+		old, _ := m.loader.GetPlugin(dbPlugin.PluginID)
+		if old != nil {
+			if err := callPlugin(old.Disable); err != nil {
+				log.Warn("Failed to disable replaced instance of plugin %s: %v", dbPlugin.PluginID, err)
+			}
+		}
+		if err := callPlugin(pluginInstance.Enable); err != nil {
+			closePlugin(pluginInstance)
+			if old != nil {
+				if err := callPlugin(old.Enable); err != nil {
+					log.Error("Failed to re-enable plugin %s after failed upgrade: %v", dbPlugin.PluginID, err)
+				}
+			}
+			return fmt.Errorf("enable plugin: %w", err)
+		}
+	}
+
+	m.loader.swapPlugin(ctx, dbPlugin.PluginID, pluginInstance, dbPlugin.IsEnabled)
+
+	// 超出保留范围的版本目录（升级前的上一个版本）不再需要
+	stalePath := dbPlugin.PreviousPath
+	dbPlugin.PreviousVersion, dbPlugin.PreviousPath = dbPlugin.Version, m.loader.pluginPath(dbPlugin)
+	if stalePath != "" && stalePath != targetPath && stalePath != dbPlugin.PreviousPath {
+		if err := os.RemoveAll(stalePath); err != nil {
+			log.Warn("Failed to remove stale version of plugin %s: %v", dbPlugin.PluginID, err)
+		}
+	}
+
+	dbPlugin.Name = metadata.Name
+	dbPlugin.Version = metadata.Version
+	dbPlugin.Description = metadata.Description
+	dbPlugin.Author = metadata.Author
+	dbPlugin.Homepage = metadata.Homepage
+	dbPlugin.License = metadata.License
+	dbPlugin.InstallPath = targetPath
+	return nil
+}
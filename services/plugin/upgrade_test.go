@@ -0,0 +1,181 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/pluginsdk"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+)
+
+// writeTestVersion 在 installed/<id>/<version> 下创建以测试二进制为可执行文件的 process 插件
+func writeTestVersion(t *testing.T, l *PluginLoader, pluginID, version string) string {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	dir := l.versionPath(pluginID, version)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.Symlink(exe, filepath.Join(dir, "plugin")))
+
+	data, err := json.Marshal(&plugin_model.PluginMetadata{ID: pluginID, Name: pluginID, Version: version, Runtime: plugin_model.RuntimeProcess})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plugin.json"), data, 0o644))
+	return dir
+}
+
+func TestUpgradeAndRollback(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.PluginDataDir, t.TempDir())()
+
+	const pluginID = "upgrade-test"
+	pluginsDir := t.TempDir()
+	l := &PluginLoader{pluginsDir: pluginsDir, plugins: make(map[string]plugin_model.IPlugin), loading: make(map[string]bool)}
+	m := &PluginManager{loader: l, pluginsDir: pluginsDir}
+
+	v1Path := writeTestVersion(t, l, pluginID, "1.0.0")
+	v2Path := writeTestVersion(t, l, pluginID, "2.0.0")
+	dbPlugin := &plugin_model.Plugin{PluginID: pluginID, Name: pluginID, Version: "1.0.0", IsEnabled: true, IsInstalled: true, InstallPath: v1Path}
+	require.NoError(t, plugin_model.CreatePlugin(t.Context(), dbPlugin))
+	require.NoError(t, l.LoadPlugin(t.Context(), pluginID))
+	defer func() {
+		if p, ok := l.GetPlugin(pluginID); ok {
+			GetRouter().Unmount(t.Context(), pluginID)
+			closePlugin(p)
+		}
+	}()
+	running, _ := l.GetPlugin(pluginID)
+	require.NoError(t, running.Enable())
+
+	// 测试插件在另一个实例已启用时拒绝启用，切换成功说明旧实例先被停用
+	require.NoError(t, m.switchVersion(t.Context(), dbPlugin, v2Path))
+	require.NoError(t, plugin_model.UpdatePlugin(t.Context(), dbPlugin))
+	assert.Equal(t, "2.0.0", dbPlugin.Version)
+	assert.Equal(t, "1.0.0", dbPlugin.PreviousVersion)
+	assert.Equal(t, v1Path, dbPlugin.PreviousPath)
+	running, _ = l.GetPlugin(pluginID)
+	assert.Equal(t, "2.0.0", running.Info().Version)
+
+	rolledBack, err := m.Rollback(t.Context(), nil, pluginID)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", rolledBack.Version)
+	assert.Equal(t, "2.0.0", rolledBack.PreviousVersion)
+	running, _ = l.GetPlugin(pluginID)
+	assert.Equal(t, "1.0.0", running.Info().Version)
+
+	// native 插件不能热升级
+	err = m.checkVersionSwitch(t.Context(), rolledBack, &plugin_model.PluginMetadata{ID: pluginID, Version: "3.0.0", Runtime: plugin_model.RuntimeNative})
+	assert.True(t, plugin_model.IsErrPluginHotUpgradeUnsupported(err), "%v", err)
+}
+
+func TestUpgradeNativePlugin(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	const pluginID = "upgrade-native-test"
+	pluginsDir := t.TempDir()
+	l := &PluginLoader{pluginsDir: pluginsDir, plugins: make(map[string]plugin_model.IPlugin), loading: make(map[string]bool)}
+	m := &PluginManager{loader: l, pluginsDir: pluginsDir}
+
+	// 未声明运行时的插件默认为 native
+	dir := l.versionPath(pluginID, "1.0.0")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plugin.json"), []byte(`{"id":"`+pluginID+`","name":"native","version":"1.0.0"}`), 0o644))
+	require.NoError(t, plugin_model.CreatePlugin(t.Context(), &plugin_model.Plugin{PluginID: pluginID, Name: pluginID, Version: "1.0.0", IsInstalled: true, InstallPath: dir}))
+
+	graph, err := m.DependencyGraph(t.Context())
+	require.NoError(t, err)
+	assert.True(t, graph.Native[pluginID])
+
+	// 在下载新版本之前拒绝，m.market 为 nil，下载会失败
+	_, err = m.Upgrade(t.Context(), nil, pluginID, "2.0.0")
+	assert.True(t, plugin_model.IsErrPluginHotUpgradeUnsupported(err), "%v", err)
+}
+
+type migrateTestPlugin struct {
+	pluginsdk.Base
+	migrations []*plugin_model.Migration
+	calls      []string
+}
+
+func (p *migrateTestPlugin) Info() *plugin_model.PluginInfo {
+	return &plugin_model.PluginInfo{ID: "migrate-test", Name: "migrate-test", Version: "1.0.0"}
+}
+
+func (p *migrateTestPlugin) Init(host plugin_model.Host) error {
+	p.calls = append(p.calls, "init")
+	return p.Base.Init(host)
+}
+
+func (p *migrateTestPlugin) Migrations() []*plugin_model.Migration {
+	return p.migrations
+}
+
+func TestInitPluginMigrations(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	const pluginID = "migrate-test"
+	require.NoError(t, plugin_model.CreatePlugin(t.Context(), &plugin_model.Plugin{PluginID: pluginID, Name: pluginID, Version: "1.0.0", IsInstalled: true}))
+	metadata := &plugin_model.PluginMetadata{ID: pluginID, Version: "1.0.0"}
+
+	p := &migrateTestPlugin{}
+	p.migrations = []*plugin_model.Migration{
+		plugin_model.NewMigration(1, "noop", func(context.Context, *xorm.Engine) error {
+			p.calls = append(p.calls, "migrate")
+			return nil
+		}),
+	}
+	require.NoError(t, InitPlugin(t.Context(), pluginID, p, metadata))
+	assert.Equal(t, []string{"init", "migrate"}, p.calls)
+
+	// 数据库版本高于插件的迁移数量（回滚到迁移之前的版本）时不初始化插件
+	require.NoError(t, plugin_model.SetSchemaVersion(t.Context(), pluginID, 2))
+	p.calls = nil
+	err := InitPlugin(t.Context(), pluginID, p, metadata)
+	assert.True(t, plugin_model.IsErrPluginSchemaDowngrade(err), "%v", err)
+	assert.Empty(t, p.calls)
+}
+
+func TestLoadPluginWithoutLock(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.PluginDataDir, t.TempDir())()
+	defer test.MockVariableValue(&processStartTimeout, 2*time.Second)()
+
+	pluginsDir := t.TempDir()
+	l := &PluginLoader{pluginsDir: pluginsDir, plugins: make(map[string]plugin_model.IPlugin), loading: make(map[string]bool)}
+	// 测试插件不响应握手，打开过程持续到启动超时
+	path := writeTestVersion(t, l, processTestHangID, "1.0.0")
+	require.NoError(t, plugin_model.CreatePlugin(t.Context(), &plugin_model.Plugin{PluginID: processTestHangID, Name: processTestHangID, Version: "1.0.0", IsInstalled: true, InstallPath: path}))
+
+	loaded := make(chan error)
+	go func() { loaded <- l.LoadPlugin(t.Context(), processTestHangID) }()
+	require.Eventually(t, func() bool {
+		l.mu.RLock()
+		defer l.mu.RUnlock()
+		return l.loading[processTestHangID]
+	}, time.Second, 10*time.Millisecond)
+
+	// 加载期间查询和重复加载不被阻塞
+	start := time.Now()
+	_, ok := l.GetPlugin(processTestHangID)
+	assert.False(t, ok)
+	assert.EqualError(t, l.LoadPlugin(t.Context(), processTestHangID), "plugin already loaded: "+processTestHangID)
+	assert.Less(t, time.Since(start), time.Second)
+
+	require.Error(t, <-loaded)
+	l.mu.RLock()
+	assert.Empty(t, l.loading)
+	assert.Empty(t, l.plugins)
+	l.mu.RUnlock()
+}
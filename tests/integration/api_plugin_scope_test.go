@@ -0,0 +1,62 @@
+// Copyright 2026 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	plugin_model "code.gitea.io/gitea/models/plugin"
+	api "code.gitea.io/gitea/modules/structs"
+	plugin_service "code.gitea.io/gitea/services/plugin"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type scopeTestPlugin struct {
+	plugin_model.IPlugin
+}
+
+func (p *scopeTestPlugin) Info() *plugin_model.PluginInfo {
+	return &plugin_model.PluginInfo{
+		ID: "scope-test",
+		Scopes: []*plugin_model.APIScope{{
+			Name:  "scope_test",
+			Paths: []string{"/api/v1/orgs/{org}/scope-test"},
+		}},
+	}
+}
+
+func (p *scopeTestPlugin) RegisterRoutes(chi.Router) {}
+
+func (p *scopeTestPlugin) RegisterAPIRoutes(r chi.Router) {
+	r.Get("/api/v1/orgs/{org}/scope-test", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(chi.URLParam(r, "org")))
+	})
+}
+
+func TestAPIPluginScope(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	router := plugin_service.GetRouter()
+	router.Mount(t.Context(), "scope-test", &scopeTestPlugin{}, true)
+	defer router.Unmount(t.Context(), "scope-test")
+
+	orgToken := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteOrganization)
+	pluginToken := getUserToken(t, "user1", "read:scope_test")
+
+	// core org routes are still checked against the built-in categories
+	req := NewRequestWithJSON(t, "PATCH", "/api/v1/orgs/org3", &api.EditOrgOption{Description: "plugin scope"}).AddTokenAuth(orgToken)
+	MakeRequest(t, req, http.StatusOK)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/orgs/org3").AddTokenAuth(orgToken), http.StatusOK)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/orgs/org3").AddTokenAuth(pluginToken), http.StatusForbidden)
+
+	// the plugin route needs the plugin's category
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/orgs/org3/scope-test").AddTokenAuth(orgToken), http.StatusForbidden)
+	resp := MakeRequest(t, NewRequest(t, "GET", "/api/v1/orgs/org3/scope-test").AddTokenAuth(pluginToken), http.StatusOK)
+	assert.Equal(t, "org3", resp.Body.String())
+}